@@ -1,11 +1,31 @@
 package main
 
 import (
+	actions11 "backend/modules/audit/actions"
 	actions3 "backend/modules/categories/actions"
+	actions13 "backend/modules/emergency/actions"
+	services5 "backend/modules/emergency/services"
+	actions10 "backend/modules/exports/actions"
+	actions9 "backend/modules/imports/actions"
+	actions8 "backend/modules/notifications/actions"
+	services4 "backend/modules/notifications/services"
+	actions12 "backend/modules/organizations/actions"
+	actions4 "backend/modules/passwords/actions"
+	services7 "backend/modules/passwords/services"
+	actions7 "backend/modules/reports/actions"
+	actions14 "backend/modules/sends/actions"
+	middlewares2 "backend/modules/sends/middlewares"
+	services6 "backend/modules/sends/services"
+	actions5 "backend/modules/tags/actions"
+	actions6 "backend/modules/trash/actions"
+	services3 "backend/modules/trash/services"
 	actions2 "backend/modules/users/actions"
 	"backend/modules/users/middlewares"
 	"backend/services"
+	"backend/services/config"
+	"backend/services/storage"
 	"backend/system/actions"
+	"backend/system/commands"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus"
@@ -14,6 +34,7 @@ import (
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	"log"
+	"os"
 	"runtime"
 	"time"
 )
@@ -22,7 +43,26 @@ func main() {
 	services.InitDBConnection()
 	services.Migrations()
 
+	if err := storage.Init(); err != nil {
+		panic("failed to configure storage: " + err.Error())
+	}
+
+	if len(os.Args) > 1 {
+		if err := commands.Run(os.Args[1:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	jobsInit()
+
 	r := gin.Default()
+
+	// Client IPs are read from X-Forwarded-For only behind these proxies, the rate limits and logs rely on them.
+	if err := r.SetTrustedProxies(config.GetList("TRUSTED_PROXIES")); err != nil {
+		log.Fatal(err)
+	}
+
 	routes(r)
 
 	fmt.Println("Server started on 80 port")
@@ -47,8 +87,13 @@ func routes(r *gin.Engine) {
 		user.GET("", middlewares.AuthMiddleware(), actions2.GetUser)
 		user.POST("/register", actions2.UserRegister)
 		user.POST("/login", actions2.UserLogin)
+		user.POST("/prelogin", actions2.PreLogin)
+		user.GET("/vault", middlewares.AuthMiddleware(), actions2.GetVault)
+		user.PUT("/vault", middlewares.AuthMiddleware(), actions2.UpdateVault)
 	}
 
+	r.POST("/send/:id", middlewares2.RateLimitMiddleware(config.GetInt("SEND_OPEN_RATE_PER_MINUTE", 20), time.Minute), actions14.OpenSend)
+
 	authEndpoints := r.Group("/", middlewares.AuthMiddleware())
 	category := authEndpoints.Group("/category")
 	{
@@ -58,6 +103,157 @@ func routes(r *gin.Engine) {
 		category.DELETE("/delete/:id", actions3.DeleteCategory)
 	}
 
+	tag := authEndpoints.Group("/tag")
+	{
+		tag.GET("/all", actions5.GetTags)
+		tag.POST("/create", actions5.CreateTag)
+		tag.PUT("/update/:id", actions5.UpdateTag)
+		tag.DELETE("/delete/:id", actions5.DeleteTag)
+		tag.POST("/attach/:id", actions5.AttachTag)
+		tag.POST("/detach/:id", actions5.DetachTag)
+	}
+
+	trash := authEndpoints.Group("/trash")
+	{
+		trash.GET("/all", actions6.GetTrash)
+		trash.POST("/password/restore/:id", actions6.RestorePassword)
+		trash.DELETE("/password/delete/:id", actions6.DeletePassword)
+		trash.POST("/category/restore/:id", actions6.RestoreCategory)
+		trash.DELETE("/category/delete/:id", actions6.DeleteCategory)
+		trash.DELETE("/empty", actions6.EmptyTrash)
+	}
+
+	notification := authEndpoints.Group("/notification")
+	{
+		notification.GET("/all", actions8.GetNotifications)
+		notification.POST("/read/:id", actions8.ReadNotification)
+		notification.POST("/read-all", actions8.ReadAllNotifications)
+	}
+
+	authEndpoints.POST("/import", actions9.ImportPasswords)
+	authEndpoints.POST("/export", actions10.ExportVault)
+
+	audit := authEndpoints.Group("/audit")
+	{
+		audit.GET("/all", actions11.GetEvents)
+	}
+
+	organization := authEndpoints.Group("/organization")
+	{
+		organization.GET("/all", actions12.GetOrganizations)
+		organization.POST("/create", actions12.CreateOrganization)
+		organization.PUT("/update/:id", actions12.UpdateOrganization)
+		organization.DELETE("/delete/:id", actions12.DeleteOrganization)
+		organization.GET("/invitations", actions12.GetInvitations)
+		organization.POST("/invitations/accept/:id", actions12.AcceptInvitation)
+		organization.POST("/invitations/decline/:id", actions12.DeclineInvitation)
+		organization.GET("/:id/members", actions12.GetMembers)
+		organization.POST("/:id/members/invite", actions12.InviteMember)
+		organization.PUT("/:id/members/update/:member", actions12.UpdateMember)
+		organization.DELETE("/:id/members/delete/:member", actions12.RemoveMember)
+		organization.GET("/:id/groups", actions12.GetGroups)
+		organization.POST("/:id/groups/create", actions12.CreateGroup)
+		organization.PUT("/:id/groups/update/:group", actions12.UpdateGroup)
+		organization.DELETE("/:id/groups/delete/:group", actions12.DeleteGroup)
+		organization.GET("/:id/collections", actions12.GetCollections)
+		organization.POST("/:id/collections/create", actions12.CreateCollection)
+		organization.PUT("/:id/collections/update/:collection", actions12.UpdateCollection)
+		organization.DELETE("/:id/collections/delete/:collection", actions12.DeleteCollection)
+		organization.GET("/:id/collections/:collection/grants", actions12.GetGrants)
+		organization.PUT("/:id/collections/:collection/grants/member/:member", actions12.GrantMember)
+		organization.DELETE("/:id/collections/:collection/grants/member/:member", actions12.RevokeMember)
+		organization.PUT("/:id/collections/:collection/grants/group/:group", actions12.GrantGroup)
+		organization.DELETE("/:id/collections/:collection/grants/group/:group", actions12.RevokeGroup)
+		organization.GET("/:id/collections/:collection/passwords", actions12.GetCollectionPasswords)
+		organization.POST("/:id/collections/:collection/passwords/add", actions12.AddCollectionPassword)
+		organization.DELETE("/:id/collections/:collection/passwords/remove/:password", actions12.RemoveCollectionPassword)
+	}
+
+	send := authEndpoints.Group("/send")
+	{
+		send.GET("/all", actions14.GetSends)
+		send.POST("/create", actions14.CreateSend)
+		send.PUT("/upload/:id", actions14.UploadSendFile)
+		send.DELETE("/delete/:id", actions14.DeleteSend)
+	}
+
+	emergency := authEndpoints.Group("/emergency")
+	{
+		emergency.GET("/contacts", actions13.GetContacts)
+		emergency.POST("/contacts/create", actions13.CreateContact)
+		emergency.PUT("/contacts/update/:id", actions13.UpdateContact)
+		emergency.DELETE("/contacts/delete/:id", actions13.DeleteContact)
+		emergency.POST("/contacts/:id/approve", actions13.ApproveAccess)
+		emergency.POST("/contacts/:id/reject", actions13.RejectAccess)
+		emergency.GET("/granted", actions13.GetGrantors)
+		emergency.POST("/granted/:id/request", actions13.RequestAccess)
+		emergency.GET("/granted/:id/passwords", actions13.GetEmergencyPasswords)
+		emergency.GET("/granted/:id/passwords/:password", actions13.GetEmergencyPassword)
+		emergency.POST("/granted/:id/takeover", actions13.Takeover)
+	}
+
+	reports := authEndpoints.Group("/reports")
+	{
+		reports.GET("/health", actions7.GetHealthReport)
+		reports.GET("/health/export", actions7.ExportHealthReport)
+	}
+
+	password := authEndpoints.Group("/password")
+	{
+		password.GET("/all", actions4.GetPasswords)
+		password.GET("/search", actions4.SearchPasswords)
+		password.PUT("/search/settings", actions4.UpdateSearchSettings)
+		password.GET("/due", actions4.GetDuePasswords)
+		password.GET("/match", actions4.MatchPasswords)
+		password.GET("/shared", actions4.GetReceivedPasswords)
+		password.GET("/shared/:id", actions4.GetReceivedPassword)
+		password.PUT("/shared/:id", actions4.UpdateReceivedPassword)
+		password.GET("/organization/:id", actions4.GetOrganizationPassword)
+		password.PUT("/organization/:id", actions4.UpdateOrganizationPassword)
+		password.GET("/:id", actions4.GetPassword)
+		password.POST("/create", actions4.CreatePassword)
+		password.PUT("/update/:id", actions4.UpdatePassword)
+		password.DELETE("/delete/:id", actions4.DeletePassword)
+		password.POST("/bulk/move", actions4.MovePasswords)
+		password.POST("/bulk/delete", actions4.DeletePasswords)
+		password.POST("/bulk/restore", actions4.RestorePasswords)
+		password.POST("/bulk/tag", actions4.TagPasswords)
+		password.POST("/bulk/untag", actions4.UntagPasswords)
+		password.POST("/bulk/share", actions4.SharePasswords)
+		password.POST("/note/create", actions4.CreateNote)
+		password.PUT("/note/update/:id", actions4.UpdateNote)
+		password.POST("/card/create", actions4.CreateCard)
+		password.PUT("/card/update/:id", actions4.UpdateCard)
+		password.POST("/identity/create", actions4.CreateIdentity)
+		password.PUT("/identity/update/:id", actions4.UpdateIdentity)
+		password.POST("/wifi/create", actions4.CreateWifi)
+		password.PUT("/wifi/update/:id", actions4.UpdateWifi)
+		password.GET("/:id/revisions", actions4.GetRevisions)
+		password.GET("/:id/revisions/:revision", actions4.GetRevision)
+		password.POST("/:id/revisions/:revision/reveal", actions4.RevealRevision)
+		password.POST("/:id/revisions/:revision/restore", actions4.RestoreRevision)
+		password.GET("/:id/totp", actions4.GetTotpCode)
+		password.POST("/:id/used", actions4.MarkPasswordUsed)
+		password.GET("/:id/attachments", actions4.GetAttachments)
+		password.POST("/:id/attachments/upload", actions4.UploadAttachment)
+		password.GET("/:id/attachments/:attachment", actions4.DownloadAttachment)
+		password.DELETE("/:id/attachments/:attachment", actions4.DeleteAttachment)
+		password.GET("/:id/shares", actions4.GetShares)
+		password.POST("/:id/shares", actions4.SharePassword)
+		password.DELETE("/:id/shares/:share", actions4.RevokeShare)
+		password.GET("/:id/access", actions4.GetAccess)
+		password.GET("/:id/access/log", actions4.GetAccessLog)
+		password.POST("/:id/reveal", actions4.RevealPassword)
+		password.POST("/:id/copy", actions4.CopySecret)
+
+		password.POST("/strength", actions4.EstimateStrength)
+		password.POST("/generate", actions4.GeneratePassword)
+		password.GET("/generator/presets", actions4.GetGeneratorPresets)
+		password.POST("/generator/presets/create", actions4.CreateGeneratorPreset)
+		password.PUT("/generator/presets/update/:id", actions4.UpdateGeneratorPreset)
+		password.DELETE("/generator/presets/delete/:id", actions4.DeleteGeneratorPreset)
+	}
+
 	swaggerURL := ginSwagger.URL("http://localhost/api/docs/swagger.json")
 	r.GET("/api/documentation/*any", ginSwagger.WrapHandler(swaggerFiles.Handler, swaggerURL))
 
@@ -105,3 +301,106 @@ func prometheusInit(r *gin.Engine) {
 
 	r.GET("/metrics", gin.WrapH(promhttp.Handler()))
 }
+
+// jobsInit starts the background jobs.
+//
+// The breach rescan runs at startup and then every BREACH_RESCAN_HOURS hours (24 by default), 0 disables it.
+// The trash, expired sends and expired revisions are purged, rotation reminders are sent and emergency access
+// requests are granted every hour, see TrashService.Purge, SendService.Purge, PasswordService.PurgeRevisions,
+// NotificationService.RemindRotations and EmergencyService.GrantDue.
+func jobsInit() {
+	go func() {
+		trashService := services3.TrashService{DB: services.GetDBConnection()}
+
+		for {
+			purged, err := trashService.Purge()
+			if err != nil {
+				log.Println("trash purge failed:", err)
+			} else if purged > 0 {
+				log.Printf("trash purge deleted %d items", purged)
+			}
+
+			time.Sleep(time.Hour)
+		}
+	}()
+
+	go func() {
+		sendService := services6.SendService{DB: services.GetDBConnection()}
+
+		for {
+			purged, err := sendService.Purge()
+			if err != nil {
+				log.Println("send purge failed:", err)
+			} else if purged > 0 {
+				log.Printf("send purge deleted %d sends", purged)
+			}
+
+			time.Sleep(time.Hour)
+		}
+	}()
+
+	go func() {
+		passwordService := services7.PasswordService{DB: services.GetDBConnection()}
+
+		for {
+			purged, err := passwordService.PurgeRevisions()
+			if err != nil {
+				log.Println("revision purge failed:", err)
+			} else if purged > 0 {
+				log.Printf("revision purge deleted %d revisions", purged)
+			}
+
+			time.Sleep(time.Hour)
+		}
+	}()
+
+	go func() {
+		notificationService := services4.NotificationService{DB: services.GetDBConnection()}
+
+		for {
+			reminded, err := notificationService.RemindRotations()
+			if err != nil {
+				log.Println("rotation reminders failed:", err)
+			} else if reminded > 0 {
+				log.Printf("rotation reminders sent %d notifications", reminded)
+			}
+
+			time.Sleep(time.Hour)
+		}
+	}()
+
+	go func() {
+		emergencyService := services5.EmergencyService{DB: services.GetDBConnection()}
+
+		for {
+			granted, err := emergencyService.GrantDue()
+			if err != nil {
+				log.Println("emergency access grants failed:", err)
+			} else if granted > 0 {
+				log.Printf("emergency access granted to %d contacts", granted)
+			}
+
+			time.Sleep(time.Hour)
+		}
+	}()
+
+	rescanHours := config.GetInt("BREACH_RESCAN_HOURS", 24)
+	if rescanHours <= 0 {
+		return
+	}
+
+	go func() {
+		passwordService := services7.PasswordService{DB: services.GetDBConnection()}
+
+		for {
+			changed, err := passwordService.RescanBreaches()
+			if err != nil {
+				log.Println("breach rescan failed:", err)
+			} else {
+				log.Printf("breach rescan updated %d passwords", changed)
+			}
+
+			time.Sleep(time.Duration(rescanHours) * time.Hour)
+		}
+	}()
+}
@@ -0,0 +1,56 @@
+package actions
+
+import (
+	"backend/modules/audit/services"
+	"backend/modules/users/models"
+	services2 "backend/services"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type GetEventsRequest struct {
+	Action string `form:"action"`
+}
+
+// GetEvents retrieves the audit trail of the logged-in user.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Get audit trail
+// @Description Retrieves the audited operations of the logged-in user, newest first, such as vault exports
+// @Tags Audit
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   action  query    string  false  "Only events of this action, e.g. vault_export"
+// @Success 200 {array} services.Event
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /audit/all [get]
+func GetEvents(c *gin.Context) {
+	var request GetEventsRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	auditService, user := getServiceAndUser(c)
+
+	events, err := auditService.GetEvents(user.User.ID, request.Action)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
+
+// getServiceAndUser returns the audit service and user token.
+//
+// It takes a Gin context as a parameter.
+// It returns an AuditService and a Token.
+func getServiceAndUser(c *gin.Context) (services.AuditService, models.Token) {
+	service := services.AuditService{DB: services2.GetDBConnection()}
+	user := services2.GetUserFromContext(c)
+
+	return service, user
+}
@@ -0,0 +1,93 @@
+package models
+
+import (
+	"backend/modules/users/models"
+	"gorm.io/gorm"
+	"time"
+)
+
+const (
+	ActionVaultExport = "vault_export"
+	// ActionVaultExportFailed is an export refused because of a wrong login password.
+	ActionVaultExportFailed = "vault_export_failed"
+	// Emergency access transitions are recorded for both the owner of the vault and the contact.
+	ActionEmergencyContactAdd    = "emergency_contact_add"
+	ActionEmergencyContactUpdate = "emergency_contact_update"
+	ActionEmergencyContactRemove = "emergency_contact_remove"
+	ActionEmergencyRequest       = "emergency_request"
+	ActionEmergencyApprove       = "emergency_approve"
+	ActionEmergencyReject        = "emergency_reject"
+	ActionEmergencyGrant         = "emergency_grant"
+	ActionEmergencyView          = "emergency_view"
+	ActionEmergencyTakeover      = "emergency_takeover"
+)
+
+// AuditEvent records a sensitive operation of a user, e.g. an export of the vault.
+//
+// TokenID is the access token of the request, IP and UserAgent describe the client.
+type AuditEvent struct {
+	gorm.Model
+	UserID    uint        `gorm:"not null;index"`
+	User      models.User `gorm:"foreignKey:UserID"`
+	TokenID   *uint       `gorm:"nullable"`
+	Action    string      `gorm:"not null;index"`
+	Detail    string      `gorm:"not null;default:''"`
+	IP        string      `gorm:"not null;default:''"`
+	UserAgent string      `gorm:"not null;default:''"`
+}
+
+type AuditModel struct {
+	DB *gorm.DB
+}
+
+// Create saves an audit event.
+//
+// event: the event to save.
+// Returns the saved event or an error if the insert fails.
+func (m *AuditModel) Create(event AuditEvent) (AuditEvent, error) {
+	err := m.DB.Omit("User").Create(&event).Error
+
+	return event, err
+}
+
+// GetAll returns the audit events of a given user, newest first.
+//
+// Parameters:
+// - userId: the ID of the user.
+// - action: only return the events of this action, all events when empty.
+//
+// Returns:
+// - []AuditEvent: the events.
+// - error: any error that occurred during the retrieval process.
+func (m *AuditModel) GetAll(userId uint, action string) ([]AuditEvent, error) {
+	var events []AuditEvent
+
+	query := m.DB.Where("user_id = ?", userId)
+	if action != "" {
+		query = query.Where("action = ?", action)
+	}
+
+	err := query.Order("created_at DESC, id DESC").Find(&events).Error
+
+	return events, err
+}
+
+// Count returns the number of events of an action a given user had since a point in time.
+//
+// Parameters:
+// - userId: the ID of the user.
+// - action: the audited action.
+// - since: the start of the period.
+//
+// Returns:
+// - int64: the number of events.
+// - error: any error that occurred during the count.
+func (m *AuditModel) Count(userId uint, action string, since time.Time) (int64, error) {
+	var count int64
+
+	err := m.DB.Model(&AuditEvent{}).
+		Where("user_id = ? AND action = ? AND created_at >= ?", userId, action, since).
+		Count(&count).Error
+
+	return count, err
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"backend/modules/audit/models"
+	"gorm.io/gorm"
+	"time"
+)
+
+type AuditService struct {
+	DB *gorm.DB
+}
+
+type Event struct {
+	ID        uint      `json:"id"`
+	Action    string    `json:"action"`
+	Detail    string    `json:"detail"`
+	TokenID   *uint     `json:"token_id"`
+	IP        string    `json:"ip"`
+	UserAgent string    `json:"user_agent"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Client describes where a request comes from.
+type Client struct {
+	TokenID   *uint
+	IP        string
+	UserAgent string
+}
+
+// getModel returns an AuditModel.
+//
+// No parameters.
+// Returns a models.AuditModel.
+func (s *AuditService) getModel() models.AuditModel {
+	return models.AuditModel{DB: s.DB}
+}
+
+// Record saves an audit event for a given user.
+//
+// Parameters:
+// - userId: the ID of the user.
+// - action: the audited action, e.g. models.ActionVaultExport.
+// - detail: a short description of the operation, without secrets.
+// - client: the token, IP address and user agent of the request.
+//
+// Returns:
+// - error: an error if the event cannot be saved.
+func (s *AuditService) Record(userId uint, action, detail string, client Client) error {
+	auditModel := s.getModel()
+
+	_, err := auditModel.Create(models.AuditEvent{
+		UserID:    userId,
+		TokenID:   client.TokenID,
+		Action:    action,
+		Detail:    detail,
+		IP:        client.IP,
+		UserAgent: client.UserAgent,
+	})
+
+	return err
+}
+
+// Count returns the number of events of an action a given user had since a point in time.
+//
+// Parameters:
+// - userId: the ID of the user.
+// - action: the audited action, e.g. models.ActionVaultExportFailed.
+// - since: the start of the period.
+//
+// Returns:
+// - int64: the number of events.
+// - error: any error that occurred during the count.
+func (s *AuditService) Count(userId uint, action string, since time.Time) (int64, error) {
+	auditModel := s.getModel()
+
+	return auditModel.Count(userId, action, since)
+}
+
+// GetEvents returns the audit trail of a given user.
+//
+// Parameters:
+// - userId: the ID of the user.
+// - action: only return the events of this action, all events when empty.
+//
+// Returns:
+// - []Event: the events, newest first.
+// - error: any error that occurred during the retrieval process.
+func (s *AuditService) GetEvents(userId uint, action string) ([]Event, error) {
+	auditModel := s.getModel()
+
+	events, err := auditModel.GetAll(userId, action)
+
+	eventsList := []Event{}
+
+	for _, event := range events {
+		eventsList = append(eventsList, Event{
+			ID:        event.ID,
+			Action:    event.Action,
+			Detail:    event.Detail,
+			TokenID:   event.TokenID,
+			IP:        event.IP,
+			UserAgent: event.UserAgent,
+			CreatedAt: event.CreatedAt,
+		})
+	}
+
+	return eventsList, err
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BreachRange is one SHA-1 hash of a breached password split like the HIBP range API:
+// the first 5 hex characters are the prefix, the remaining 35 the suffix.
+type BreachRange struct {
+	Prefix string `gorm:"primaryKey;type:char(5)"`
+	Suffix string `gorm:"primaryKey;type:char(35)"`
+	Count  int    `gorm:"not null"`
+}
+
+type BreachRangeModel struct {
+	DB *gorm.DB
+}
+
+// GetRange returns every suffix with its count for a hash prefix.
+//
+// Parameters:
+// - prefix: the first 5 uppercase hex characters of a SHA-1 hash.
+//
+// Returns:
+// - map[string]int: the breach count of every suffix in the range.
+// - error: any error that occurred during the retrieval process.
+func (m *BreachRangeModel) GetRange(prefix string) (map[string]int, error) {
+	var ranges []BreachRange
+
+	err := m.DB.Where("prefix = ?", prefix).Find(&ranges).Error
+	if err != nil {
+		return nil, err
+	}
+
+	suffixes := make(map[string]int, len(ranges))
+	for _, r := range ranges {
+		suffixes[r.Suffix] = r.Count
+	}
+
+	return suffixes, nil
+}
+
+// Upsert inserts a batch of ranges, existing hashes get the new count.
+//
+// ranges: the batch to save.
+// Returns an error if the batch cannot be saved.
+func (m *BreachRangeModel) Upsert(ranges []BreachRange) error {
+	if len(ranges) == 0 {
+		return nil
+	}
+
+	return m.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "prefix"}, {Name: "suffix"}},
+		DoUpdates: clause.AssignmentColumns([]string{"count"}),
+	}).Create(&ranges).Error
+}
+
+// DeleteAll deletes the whole dataset.
+//
+// A DELETE is used instead of TRUNCATE, so concurrent checks keep reading the previous dataset
+// while the import transaction is running instead of waiting for its lock.
+//
+// It does not take any parameters.
+// It returns an error if the deletion fails.
+func (m *BreachRangeModel) DeleteAll() error {
+	return m.DB.Exec("DELETE FROM breach_ranges").Error
+}
@@ -0,0 +1,205 @@
+package services
+
+import (
+	"backend/modules/breaches/models"
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"gorm.io/gorm"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	prefixLength = 5
+	hashLength   = 40
+	batchSize    = 5000
+)
+
+type BreachService struct {
+	DB *gorm.DB
+}
+
+// getModel returns a BreachRangeModel.
+//
+// No parameters.
+// Returns a models.BreachRangeModel.
+func (s *BreachService) getModel() models.BreachRangeModel {
+	return models.BreachRangeModel{DB: s.DB}
+}
+
+// Check returns how many times a password appears in the local breach dataset.
+//
+// Only the 5 character prefix of the SHA-1 hash is used in the query and the suffix is compared
+// in memory, the same way as with the k-anonymity range API of Have I Been Pwned.
+//
+// Parameters:
+// - password: the plaintext password.
+//
+// Returns:
+// - int: the breach count, 0 if the password was not found.
+// - error: any error that occurred during the lookup.
+func (s *BreachService) Check(password string) (int, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	breachModel := s.getModel()
+
+	suffixes, err := breachModel.GetRange(hash[:prefixLength])
+	if err != nil {
+		return 0, err
+	}
+
+	return suffixes[hash[prefixLength:]], nil
+}
+
+// Import loads a HIBP-style dataset into the database.
+//
+// The path is either a file with "HASH:COUNT" lines, like pwned-passwords-sha1-ordered-by-hash.txt,
+// or a directory of range files named by their prefix (e.g. 5BAA6.txt) with "SUFFIX:COUNT" lines,
+// like the output of the official downloader. The import runs in a single transaction, so a failed
+// import leaves the previous dataset as it was and checks keep using it until the import is committed.
+//
+// Parameters:
+// - path: the file or directory to import.
+// - replace: delete the current dataset before importing, otherwise counts of known hashes are refreshed.
+//
+// Returns:
+// - int: the number of imported hashes.
+// - error: an error if the dataset cannot be read or saved.
+func (s *BreachService) Import(path string, replace bool) (int, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+
+	total := 0
+
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		service := BreachService{DB: tx}
+
+		total, err = service.importPath(path, info.IsDir(), replace)
+
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+// importPath replaces or refreshes the dataset with a file or a directory of range files.
+func (s *BreachService) importPath(path string, dir, replace bool) (int, error) {
+	breachModel := s.getModel()
+
+	if replace {
+		if err := breachModel.DeleteAll(); err != nil {
+			return 0, err
+		}
+	}
+
+	if !dir {
+		return s.importFile(path, "")
+	}
+
+	files, err := filepath.Glob(filepath.Join(path, "*"))
+	if err != nil {
+		return 0, err
+	}
+
+	total := 0
+	for _, file := range files {
+		prefix := strings.ToUpper(strings.TrimSuffix(filepath.Base(file), filepath.Ext(file)))
+		if len(prefix) != prefixLength || !isHex(prefix) {
+			continue
+		}
+
+		count, err := s.importFile(file, prefix)
+		total += count
+		if err != nil {
+			return total, err
+		}
+	}
+
+	return total, nil
+}
+
+// importFile imports one file, prefix is empty for files with full hashes.
+func (s *BreachService) importFile(path, prefix string) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	return s.importReader(file, prefix)
+}
+
+// importReader saves the lines of a dataset in batches.
+func (s *BreachService) importReader(reader io.Reader, prefix string) (int, error) {
+	breachModel := s.getModel()
+	scanner := bufio.NewScanner(reader)
+
+	batch := make([]models.BreachRange, 0, batchSize)
+	total, line := 0, 0
+
+	for scanner.Scan() {
+		line++
+
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		hash, countText, found := strings.Cut(text, ":")
+		if !found {
+			return total, fmt.Errorf("line %d: expected HASH:COUNT", line)
+		}
+
+		hash = strings.ToUpper(prefix + hash)
+		if len(hash) != hashLength || !isHex(hash) {
+			return total, fmt.Errorf("line %d: invalid SHA-1 hash", line)
+		}
+
+		count, err := strconv.Atoi(countText)
+		if err != nil {
+			return total, fmt.Errorf("line %d: invalid count", line)
+		}
+
+		batch = append(batch, models.BreachRange{Prefix: hash[:prefixLength], Suffix: hash[prefixLength:], Count: count})
+
+		if len(batch) == batchSize {
+			if err := breachModel.Upsert(batch); err != nil {
+				return total, err
+			}
+			total += len(batch)
+			batch = batch[:0]
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return total, err
+	}
+
+	if err := breachModel.Upsert(batch); err != nil {
+		return total, err
+	}
+
+	return total + len(batch), nil
+}
+
+// isHex reports whether the value contains only hex characters.
+func isHex(value string) bool {
+	for _, r := range value {
+		if !strings.ContainsRune("0123456789ABCDEFabcdef", r) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -4,18 +4,29 @@ import (
 	"backend/modules/categories/services"
 	"backend/modules/users/models"
 	services2 "backend/services"
+	"errors"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 	"net/http"
 )
 
 type CreateOrUpdateCategoryRequest struct {
 	Name string `json:"name"`
+	// Passwords of the category must be rotated every rotation_days days, null for no policy.
+	RotationDays *int `json:"rotation_days" binding:"omitempty,min=1,max=3650"`
 }
 
 type CategoryRequestAndResponse struct {
 	ID uint `json:"id" uri:"id" binding:"required"`
 }
 
+type DeleteCategoryRequest struct {
+	// What happens to the passwords of the category: detach (default), cascade to the trash or reassign.
+	Mode string `form:"mode" binding:"omitempty,oneof=detach cascade reassign"`
+	// Category the passwords are reassigned to, required by the reassign mode.
+	CategoryID *uint `form:"category_id"`
+}
+
 // GetCategories retrieves the categories using the given gin.Context.
 //
 // It expects a *gin.Context parameter and returns nothing.
@@ -45,7 +56,7 @@ func GetCategories(c *gin.Context) {
 // It expects a gin.Context parameter to access the HTTP request and response.
 // It does not have any return values.
 // @Summary Create a new category
-// @Description Creates a new category with the provided name
+// @Description Creates a new category with the provided name and optional password rotation interval
 // @Tags Categories
 // @Accept  json
 // @Produce  json
@@ -64,7 +75,7 @@ func CreateCategory(c *gin.Context) {
 
 	categoryService, user := getServiceAndUser(c)
 
-	category, err := categoryService.CreateCategory(user.User.ID, request.Name)
+	category, err := categoryService.CreateCategory(user.User.ID, request.Name, request.RotationDays)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusInternalServerError, services2.ErrorResponse{Error: err.Error()})
 		return
@@ -81,11 +92,11 @@ func CreateCategory(c *gin.Context) {
 // 3. Binds the JSON body to the CreateOrUpdateCategoryRequest struct.
 // 4. If there is an error binding the JSON body, it aborts the request with a bad request status and returns the error.
 // 5. Gets the categoryService and user from the gin context.
-// 6. Calls the UpdateCategory method of the categoryService with the ID, userID, and name from the request.
+// 6. Calls the UpdateCategory method of the categoryService with the ID, userID, name and rotation interval from the request.
 // 7. If there is an error updating the category, it aborts the request with an internal server error status and returns the error.
 // 8. Returns the updated category ID in the response body.
 // @Summary Update category
-// @Description Updates the category with the given ID
+// @Description Updates the name and password rotation interval of the category with the given ID
 // @Tags Categories
 // @Accept  json
 // @Produce  json
@@ -112,7 +123,7 @@ func UpdateCategory(c *gin.Context) {
 
 	categoryService, user := getServiceAndUser(c)
 
-	id, err := categoryService.UpdateCategory(request.ID, user.User.ID, json.Name)
+	id, err := categoryService.UpdateCategory(request.ID, user.User.ID, json.Name, json.RotationDays)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusInternalServerError, services2.ErrorResponse{Error: err.Error()})
 		return
@@ -126,35 +137,57 @@ func UpdateCategory(c *gin.Context) {
 // The function takes a gin.Context pointer as a parameter.
 // It returns nothing.
 // @Summary Delete category
-// @Description Deletes the category with the given ID
+// @Description Moves the category with the given ID to the trash. Its passwords are kept without a category by default,
+// @Description mode=cascade moves them to the trash as well and mode=reassign moves them to the category given by category_id.
 // @Tags Categories
 // @Accept  json
 // @Produce  json
 // @Security BearerAuth
 // @Param   id       path     int                             true        "Category ID"
+// @Param   mode     query    string                          false       "detach, cascade or reassign"
+// @Param   category_id     query    int                      false       "Category ID the passwords are reassigned to"
 // @Success 200 {object} CategoryRequestAndResponse
 // @Failure 400 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
 // @Failure 500 {object} services2.ErrorResponse
 // @Router /category/delete/{id} [delete]
 func DeleteCategory(c *gin.Context) {
 	var request CategoryRequestAndResponse
+	var query DeleteCategoryRequest
 
 	if err := c.ShouldBindUri(&request); err != nil {
 		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
 		return
 	}
 
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
 	categoryService, user := getServiceAndUser(c)
 
-	err := categoryService.DeleteCategory(request.ID, user.User.ID)
+	err := categoryService.DeleteCategory(request.ID, user.User.ID, query.Mode, query.CategoryID)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, services2.ErrorResponse{Error: err.Error()})
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, CategoryRequestAndResponse{ID: request.ID})
 }
 
+// errorStatus maps a service error to the HTTP status code of the response.
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrInvalidTarget):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
 // getServiceAndUser returns the category service and user token.
 //
 // It takes a Gin context as a parameter.
@@ -3,6 +3,7 @@ package models
 import (
 	"backend/modules/users/models"
 	"gorm.io/gorm"
+	"time"
 )
 
 type Category struct {
@@ -10,6 +11,8 @@ type Category struct {
 	Name   string      `gorm:"not null"`
 	UserID uint        `gorm:"not null"`
 	User   models.User `gorm:"foreignKey:UserID"`
+	// Number of days after which the passwords of the category must be rotated, nil when there is no policy.
+	RotationDays *int `gorm:"nullable"`
 }
 
 type CategoryModel struct {
@@ -32,19 +35,38 @@ func (m *CategoryModel) GetAll(userID uint) ([]Category, error) {
 	return category, nil
 }
 
+// Get returns a single category by its ID for a given user ID.
+//
+// Parameters:
+// - id: the ID of the category.
+// - userId: the ID of the user who owns the category.
+//
+// Returns:
+// - Category: the found category.
+// - error: gorm.ErrRecordNotFound if the category does not exist or belongs to another user.
+func (m *CategoryModel) Get(id, userId uint) (Category, error) {
+	var category Category
+
+	err := m.DB.Where("id = ? AND user_id = ?", id, userId).First(&category).Error
+
+	return category, err
+}
+
 // Create creates a new category with the given ID and name.
 //
 // Parameters:
 // - id: The ID of the category.
 // - name: The name of the category.
+// - rotationDays: The optional rotation interval of its passwords in days.
 //
 // Returns:
 // - The created Category.
 // - An error if there was a problem creating the category.
-func (m *CategoryModel) Create(id uint, name string) (Category, error) {
+func (m *CategoryModel) Create(id uint, name string, rotationDays *int) (Category, error) {
 	category := Category{
-		Name:   name,
-		UserID: id,
+		Name:         name,
+		UserID:       id,
+		RotationDays: rotationDays,
 	}
 
 	err := m.DB.Create(&category).Error
@@ -55,18 +77,21 @@ func (m *CategoryModel) Create(id uint, name string) (Category, error) {
 	return category, nil
 }
 
-// Update updates the name of a category with the given ID and user ID.
+// Update updates the name and rotation interval of a category with the given ID and user ID.
 //
 // Parameters:
 // - id: the ID of the category to update.
 // - userId: the ID of the user who owns the category.
 // - name: the new name for the category.
+// - rotationDays: the new rotation interval in days, nil removes the policy.
 //
 // Returns:
 // - uint: the ID of the category that was updated.
 // - error: an error if the update operation fails.
-func (m *CategoryModel) Update(id, userId uint, name string) (uint, error) {
-	err := m.DB.Model(&Category{}).Where("id = ? AND user_id = ?", id, userId).Update("name", name).Error
+func (m *CategoryModel) Update(id, userId uint, name string, rotationDays *int) (uint, error) {
+	err := m.DB.Model(&Category{}).
+		Where("id = ? AND user_id = ?", id, userId).
+		Updates(map[string]interface{}{"name": name, "rotation_days": rotationDays}).Error
 
 	return id, err
 }
@@ -82,3 +107,76 @@ func (m *CategoryModel) Update(id, userId uint, name string) (uint, error) {
 func (m *CategoryModel) Delete(id, userId uint) error {
 	return m.DB.Where("id = ? AND user_id = ?", id, userId).Delete(&Category{}).Error
 }
+
+// GetTrashed returns the deleted categories of a given user, most recently deleted first.
+//
+// userId: the ID of the user to retrieve categories for.
+// []Category: the deleted categories.
+// error: any error that occurred during the retrieval process.
+func (m *CategoryModel) GetTrashed(userId uint) ([]Category, error) {
+	var categories []Category
+
+	err := m.DB.Unscoped().
+		Where("user_id = ? AND deleted_at IS NOT NULL", userId).
+		Order("deleted_at DESC").
+		Find(&categories).Error
+
+	return categories, err
+}
+
+// GetExpired returns the categories of all users deleted before a given time.
+//
+// before: the deletion time limit.
+// Returns the expired categories or an error if the query fails.
+func (m *CategoryModel) GetExpired(before time.Time) ([]Category, error) {
+	var categories []Category
+
+	err := m.DB.Unscoped().Where("deleted_at < ?", before).Find(&categories).Error
+
+	return categories, err
+}
+
+// Restore restores a deleted category.
+//
+// Parameters:
+// - id: the ID of the category.
+// - userId: the ID of the user who owns the category.
+//
+// Returns:
+// - Category: the restored category.
+// - error: gorm.ErrRecordNotFound if the category is not in the trash, or an error if the update fails.
+func (m *CategoryModel) Restore(id, userId uint) (Category, error) {
+	var category Category
+
+	err := m.DB.Unscoped().Where("id = ? AND user_id = ? AND deleted_at IS NOT NULL", id, userId).First(&category).Error
+	if err != nil {
+		return Category{}, err
+	}
+
+	category.DeletedAt = gorm.DeletedAt{}
+
+	return category, m.DB.Unscoped().Model(&category).Update("deleted_at", nil).Error
+}
+
+// Purge permanently deletes a category from the trash.
+//
+// The passwords referencing the category must be detached from it first.
+//
+// Parameters:
+// - id: the ID of the category.
+// - userId: the ID of the user who owns the category.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound if the category is not in the trash, or an error if the deletion fails.
+func (m *CategoryModel) Purge(id, userId uint) error {
+	result := m.DB.Unscoped().Where("id = ? AND user_id = ? AND deleted_at IS NOT NULL", id, userId).Delete(&Category{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
@@ -2,9 +2,24 @@ package services
 
 import (
 	"backend/modules/categories/models"
+	models2 "backend/modules/passwords/models"
+	"errors"
 	"gorm.io/gorm"
+	"time"
 )
 
+// Deletion modes deciding what happens to the passwords of a deleted category.
+const (
+	// DeleteDetach keeps the passwords outside the trash without a category.
+	DeleteDetach = "detach"
+	// DeleteCascade moves the passwords to the trash with the category.
+	DeleteCascade = "cascade"
+	// DeleteReassign moves the passwords to another category.
+	DeleteReassign = "reassign"
+)
+
+var ErrInvalidTarget = errors.New("the passwords must be reassigned to another existing category")
+
 type CategoryService struct {
 	DB *gorm.DB
 }
@@ -12,6 +27,14 @@ type CategoryService struct {
 type Category struct {
 	ID   uint   `json:"id"`
 	Name string `json:"name"`
+	// Rotation interval of the passwords in days, passwords can override it.
+	RotationDays *int `json:"rotation_days"`
+}
+
+type TrashedCategory struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	DeletedAt time.Time `json:"deleted_at"`
 }
 
 // getModel returns a CategoryModel.
@@ -34,7 +57,11 @@ func (s *CategoryService) CreateCategories(userId uint) ([]Category, error) {
 	categoriesList := []Category{}
 
 	for _, category := range categories {
-		categoriesList = append(categoriesList, Category{ID: category.ID, Name: category.Name})
+		categoriesList = append(categoriesList, Category{
+			ID:           category.ID,
+			Name:         category.Name,
+			RotationDays: category.RotationDays,
+		})
 	}
 
 	return categoriesList, err
@@ -45,18 +72,20 @@ func (s *CategoryService) CreateCategories(userId uint) ([]Category, error) {
 // Parameters:
 // - userId: the ID of the user.
 // - name: the name of the category.
+// - rotationDays: the optional rotation interval of its passwords in days.
 //
 // Returns:
 // - Category: the created category with its ID and name.
 // - error: any error that occurred during the creation process.
-func (s *CategoryService) CreateCategory(userId uint, name string) (Category, error) {
+func (s *CategoryService) CreateCategory(userId uint, name string, rotationDays *int) (Category, error) {
 	categoryModel := s.getModel()
 
-	category, err := categoryModel.Create(userId, name)
+	category, err := categoryModel.Create(userId, name, rotationDays)
 
 	return Category{
-		ID:   category.ID,
-		Name: category.Name,
+		ID:           category.ID,
+		Name:         category.Name,
+		RotationDays: category.RotationDays,
 	}, err
 }
 
@@ -66,25 +95,150 @@ func (s *CategoryService) CreateCategory(userId uint, name string) (Category, er
 // - id: The ID of the category to update.
 // - userId: The ID of the user performing the update.
 // - name: The new name to assign to the category.
+// - rotationDays: The new rotation interval in days, nil removes the policy.
 //
 // Returns:
 // - uint: The ID of the updated category.
 // - error: An error if the update operation fails.
-func (s *CategoryService) UpdateCategory(id, userId uint, name string) (uint, error) {
+func (s *CategoryService) UpdateCategory(id, userId uint, name string, rotationDays *int) (uint, error) {
 	categoryModel := s.getModel()
 
-	return categoryModel.Update(id, userId, name)
+	id, err := categoryModel.Update(id, userId, name, rotationDays)
+	if err != nil {
+		return id, err
+	}
+
+	searchModel := models2.SearchModel{DB: s.DB}
+
+	return id, searchModel.UpdateCategory(id, userId, name)
 }
 
-// DeleteCategory deletes a category by its ID and user ID.
+// DeleteCategory moves a category to the trash by its ID and user ID.
+//
+// Its passwords are detached from it, moved to the trash with it or reassigned to another category depending on
+// the mode. Detaching leaves deleted passwords and revisions untouched until the category is purged.
+// Everything happens in a single transaction.
 //
 // Parameters:
 // - id: the ID of the category to be deleted.
 // - userId: the ID of the user requesting the deletion.
+// - mode: DeleteDetach, DeleteCascade or DeleteReassign, an empty mode detaches the passwords.
+// - targetId: the category the passwords are reassigned to, only used by DeleteReassign.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound if the category does not belong to the user, ErrInvalidTarget, or any update error.
+func (s *CategoryService) DeleteCategory(id, userId uint, mode string, targetId *uint) error {
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		categoryModel := models.CategoryModel{DB: tx}
+
+		if _, err := categoryModel.Get(id, userId); err != nil {
+			return err
+		}
+
+		searchModel := models2.SearchModel{DB: tx}
+		if err := searchModel.UpdateCategory(id, userId, ""); err != nil {
+			return err
+		}
+
+		passwordModel := models2.PasswordModel{DB: tx}
+
+		switch mode {
+		case DeleteCascade:
+			if err := passwordModel.DeleteCategory(id, userId); err != nil {
+				return err
+			}
+		case DeleteReassign:
+			if targetId == nil || *targetId == id {
+				return ErrInvalidTarget
+			}
+
+			target, err := categoryModel.Get(*targetId, userId)
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidTarget
+			}
+			if err != nil {
+				return err
+			}
+
+			if err := passwordModel.MoveCategory(id, target.ID, userId); err != nil {
+				return err
+			}
+
+			if err := searchModel.UpdateCategory(target.ID, userId, target.Name); err != nil {
+				return err
+			}
+		default:
+			if err := passwordModel.DetachActiveCategory(id, userId); err != nil {
+				return err
+			}
+		}
+
+		return categoryModel.Delete(id, userId)
+	})
+}
+
+// GetTrashedCategories returns the deleted categories of a given user.
+//
+// It takes in a userId of type uint as a parameter.
+// It returns a slice of TrashedCategory, most recently deleted first, and an error.
+func (s *CategoryService) GetTrashedCategories(userId uint) ([]TrashedCategory, error) {
+	categoryModel := s.getModel()
+
+	categories, err := categoryModel.GetTrashed(userId)
+
+	categoriesList := []TrashedCategory{}
+
+	for _, category := range categories {
+		categoriesList = append(categoriesList, TrashedCategory{
+			ID:        category.ID,
+			Name:      category.Name,
+			DeletedAt: category.DeletedAt.Time,
+		})
+	}
+
+	return categoriesList, err
+}
+
+// RestoreCategory restores a deleted category of a given user.
 //
-// Return type: error.
-func (s *CategoryService) DeleteCategory(id, userId uint) error {
+// Parameters:
+// - id: the ID of the category.
+// - userId: the ID of the user.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound if the category is not in the trash of the user, or any update error.
+func (s *CategoryService) RestoreCategory(id, userId uint) error {
 	categoryModel := s.getModel()
 
-	return categoryModel.Delete(id, userId)
+	category, err := categoryModel.Restore(id, userId)
+	if err != nil {
+		return err
+	}
+
+	searchModel := models2.SearchModel{DB: s.DB}
+
+	return searchModel.UpdateCategory(id, userId, category.Name)
+}
+
+// PurgeCategory permanently deletes a category from the trash of a given user.
+//
+// The passwords of the category are kept without a category.
+//
+// Parameters:
+// - id: the ID of the category.
+// - userId: the ID of the user.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound if the category is not in the trash of the user, or any deletion error.
+func (s *CategoryService) PurgeCategory(id, userId uint) error {
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		passwordModel := models2.PasswordModel{DB: tx}
+		if err := passwordModel.DetachCategory(id, userId); err != nil {
+			return err
+		}
+
+		categoryModel := models.CategoryModel{DB: tx}
+
+		return categoryModel.Purge(id, userId)
+	})
 }
@@ -0,0 +1,454 @@
+package actions
+
+import (
+	services3 "backend/modules/audit/services"
+	"backend/modules/emergency/services"
+	services4 "backend/modules/passwords/services"
+	"backend/modules/users/models"
+	services2 "backend/services"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"net/http"
+)
+
+type CreateContactRequest struct {
+	// Email of the user to choose as emergency contact.
+	Email string `json:"email" binding:"required,email"`
+	// view for read-only access to the vault, takeover to also reset the login password.
+	Access string `json:"access" binding:"required,oneof=view takeover"`
+	// Days the owner has to reject a request before the access is granted, at least one.
+	WaitDays *int `json:"wait_days" binding:"required,min=1,max=90"`
+}
+
+type UpdateContactRequest struct {
+	Access   string `json:"access" binding:"required,oneof=view takeover"`
+	WaitDays *int   `json:"wait_days" binding:"required,min=1,max=90"`
+}
+
+type TakeoverRequest struct {
+	// Password is the new login password of the owner.
+	Password string `json:"password" binding:"required"`
+}
+
+type EmergencyRequestAndResponse struct {
+	ID uint `json:"id" uri:"id" binding:"required"`
+}
+
+type EmergencyPasswordRequest struct {
+	ID         uint `uri:"id" binding:"required"`
+	PasswordID uint `uri:"password" binding:"required"`
+}
+
+// GetContacts retrieves the emergency contacts of the logged-in user.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Get list of emergency contacts
+// @Description Retrieves the users the logged-in user chose as emergency contacts with the status of their access
+// @Tags Emergency access
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Success 200 {array} services.EmergencyAccess
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /emergency/contacts [get]
+func GetContacts(c *gin.Context) {
+	emergencyService, user := getServiceAndUser(c)
+
+	contacts, err := emergencyService.GetContacts(user.User.ID)
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, contacts)
+}
+
+// CreateContact chooses a user as emergency contact of the logged-in user.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Create emergency contact
+// @Description Chooses the user with the given email as emergency contact. The contact can request access to the vault,
+// @Description which is granted if the logged-in user does not reject the request within wait_days days.
+// @Description The data key of the vault is sealed to the sharing key of the contact.
+// @Tags Emergency access
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   contact     body    CreateContactRequest     true        "Contact"
+// @Success 200 {object} services.EmergencyAccess
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 409 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /emergency/contacts/create [post]
+func CreateContact(c *gin.Context) {
+	var request CreateContactRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	emergencyService, user := getServiceAndUser(c)
+
+	contact, err := emergencyService.AddContact(user.User.ID, request.Email, request.Access, *request.WaitDays, getClient(c, user))
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, contact)
+}
+
+// UpdateContact updates an emergency contact of the logged-in user.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Update emergency contact
+// @Description Changes the access type and the waiting period of the emergency contact, a pending request keeps its waiting period
+// @Tags Emergency access
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Emergency contact ID"
+// @Param   contact     body    UpdateContactRequest     true        "Contact"
+// @Success 200 {object} EmergencyRequestAndResponse
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /emergency/contacts/update/{id} [put]
+func UpdateContact(c *gin.Context) {
+	var request EmergencyRequestAndResponse
+	var json UpdateContactRequest
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := c.ShouldBindJSON(&json); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	emergencyService, user := getServiceAndUser(c)
+
+	err := emergencyService.UpdateContact(request.ID, user.User.ID, json.Access, *json.WaitDays, getClient(c, user))
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, EmergencyRequestAndResponse{ID: request.ID})
+}
+
+// DeleteContact removes an emergency contact of the logged-in user.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Delete emergency contact
+// @Description Removes the emergency contact, its sealed key and any access it was granted
+// @Tags Emergency access
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Emergency contact ID"
+// @Success 200 {object} EmergencyRequestAndResponse
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /emergency/contacts/delete/{id} [delete]
+func DeleteContact(c *gin.Context) {
+	var request EmergencyRequestAndResponse
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	emergencyService, user := getServiceAndUser(c)
+
+	if err := emergencyService.RemoveContact(request.ID, user.User.ID, getClient(c, user)); err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, EmergencyRequestAndResponse{ID: request.ID})
+}
+
+// ApproveAccess approves the access request of an emergency contact.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Approve emergency access
+// @Description Grants the pending access request of the emergency contact without waiting for the end of the waiting period
+// @Tags Emergency access
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Emergency contact ID"
+// @Success 200 {object} EmergencyRequestAndResponse
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /emergency/contacts/{id}/approve [post]
+func ApproveAccess(c *gin.Context) {
+	var request EmergencyRequestAndResponse
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	emergencyService, user := getServiceAndUser(c)
+
+	if err := emergencyService.ApproveAccess(request.ID, user.User.ID, getClient(c, user)); err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, EmergencyRequestAndResponse{ID: request.ID})
+}
+
+// RejectAccess rejects the access request of an emergency contact.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Reject emergency access
+// @Description Rejects the pending access request of the emergency contact, or withdraws its granted access.
+// @Description The contact can request access again.
+// @Tags Emergency access
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Emergency contact ID"
+// @Success 200 {object} EmergencyRequestAndResponse
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /emergency/contacts/{id}/reject [post]
+func RejectAccess(c *gin.Context) {
+	var request EmergencyRequestAndResponse
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	emergencyService, user := getServiceAndUser(c)
+
+	if err := emergencyService.RejectAccess(request.ID, user.User.ID, getClient(c, user)); err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, EmergencyRequestAndResponse{ID: request.ID})
+}
+
+// GetGrantors retrieves the users who chose the logged-in user as emergency contact.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Get list of vaults I am an emergency contact for
+// @Description Retrieves the users who chose the logged-in user as emergency contact with the status of the access
+// @Tags Emergency access
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Success 200 {array} services.EmergencyAccess
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /emergency/granted [get]
+func GetGrantors(c *gin.Context) {
+	emergencyService, user := getServiceAndUser(c)
+
+	grantors, err := emergencyService.GetGrantors(user.User.ID)
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, grantors)
+}
+
+// RequestAccess requests emergency access to the vault of another user.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Request emergency access
+// @Description Starts the waiting period, the owner of the vault is notified and can approve or reject the request.
+// @Description The access is granted at wait_ends_at if the owner does not reject it.
+// @Tags Emergency access
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Emergency contact ID"
+// @Success 200 {object} services.EmergencyAccess
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /emergency/granted/{id}/request [post]
+func RequestAccess(c *gin.Context) {
+	var request EmergencyRequestAndResponse
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	emergencyService, user := getServiceAndUser(c)
+
+	access, err := emergencyService.RequestAccess(request.ID, user.User.ID, getClient(c, user))
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, access)
+}
+
+// GetEmergencyPasswords retrieves the vault of a user through a granted emergency access.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Get list of passwords with emergency access
+// @Description Retrieves the entries of the owner of the granted emergency access. Secrets are masked in the list,
+// @Description use GET /emergency/granted/{id}/passwords/{password} to read them. Every access is recorded in the audit trail.
+// @Tags Emergency access
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Emergency contact ID"
+// @Success 200 {array} services4.Password
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 403 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /emergency/granted/{id}/passwords [get]
+func GetEmergencyPasswords(c *gin.Context) {
+	var request EmergencyRequestAndResponse
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	emergencyService, user := getServiceAndUser(c)
+
+	var passwords []services4.Password
+
+	passwords, err := emergencyService.GetPasswords(request.ID, user.User.ID, getClient(c, user))
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, passwords)
+}
+
+// GetEmergencyPassword retrieves a password of a user through a granted emergency access.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Get password with emergency access
+// @Description Retrieves the entry with the given ID of the owner of the granted emergency access with its secrets.
+// @Description Every access is recorded in the audit trail.
+// @Tags Emergency access
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Emergency contact ID"
+// @Param   password       path     int                             true        "Password ID"
+// @Success 200 {object} services4.Password
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 403 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /emergency/granted/{id}/passwords/{password} [get]
+func GetEmergencyPassword(c *gin.Context) {
+	var request EmergencyPasswordRequest
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	emergencyService, user := getServiceAndUser(c)
+
+	var password services4.Password
+
+	password, err := emergencyService.GetPassword(request.ID, request.PasswordID, user.User.ID, getClient(c, user))
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, password)
+}
+
+// Takeover resets the login password of a user through a granted takeover access.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Take over account with emergency access
+// @Description Sets a new login password on the account of the owner of the granted takeover access and signs out their sessions.
+// @Description Accounts in the zero-knowledge vault mode cannot be taken over.
+// @Tags Emergency access
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Emergency contact ID"
+// @Param   takeover     body    TakeoverRequest     true        "New login password"
+// @Success 200 {object} EmergencyRequestAndResponse
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 403 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /emergency/granted/{id}/takeover [post]
+func Takeover(c *gin.Context) {
+	var request EmergencyRequestAndResponse
+	var json TakeoverRequest
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := c.ShouldBindJSON(&json); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	emergencyService, user := getServiceAndUser(c)
+
+	if err := emergencyService.Takeover(request.ID, user.User.ID, json.Password, getClient(c, user)); err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, EmergencyRequestAndResponse{ID: request.ID})
+}
+
+// errorStatus maps a service error to the HTTP status code of the response.
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound), errors.Is(err, services.ErrContactNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrContactSelf), errors.Is(err, services.ErrInvalidTransition),
+		errors.Is(err, services.ErrZeroKnowledge):
+		return http.StatusBadRequest
+	case errors.Is(err, services.ErrAccessNotGranted), errors.Is(err, services.ErrTakeoverForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, services.ErrContactExists):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// getClient returns the token, IP address and user agent of the request for the audit trail.
+func getClient(c *gin.Context, user models.Token) services3.Client {
+	return services3.Client{TokenID: &user.ID, IP: c.ClientIP(), UserAgent: c.Request.UserAgent()}
+}
+
+// getServiceAndUser returns the emergency service and user token.
+//
+// It takes a Gin context as a parameter.
+// It returns an EmergencyService and a Token.
+func getServiceAndUser(c *gin.Context) (services.EmergencyService, models.Token) {
+	service := services.EmergencyService{DB: services2.GetDBConnection()}
+	user := services2.GetUserFromContext(c)
+
+	return service, user
+}
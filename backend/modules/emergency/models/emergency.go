@@ -0,0 +1,267 @@
+package models
+
+import (
+	"backend/modules/users/models"
+	"gorm.io/gorm"
+	"time"
+)
+
+const (
+	AccessView     = "view"
+	AccessTakeover = "takeover"
+)
+
+const (
+	StatusIdle      = "idle"
+	StatusRequested = "requested"
+	StatusGranted   = "granted"
+)
+
+// EmergencyContact is a trusted user who can request access to the vault of another user.
+//
+// Access is granted when the owner approves the request or does not reject it before WaitEndsAt.
+type EmergencyContact struct {
+	gorm.Model
+	UserID    uint        `gorm:"not null;uniqueIndex:idx_emergency_contacts_contact"`
+	User      models.User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	ContactID uint        `gorm:"not null;index;uniqueIndex:idx_emergency_contacts_contact"`
+	Contact   models.User `gorm:"foreignKey:ContactID;constraint:OnDelete:CASCADE"`
+	Access    string      `gorm:"not null"`
+	WaitDays  int         `gorm:"not null"`
+	Status    string      `gorm:"not null;default:idle;index"`
+	// Key is the data-encryption key of the owner sealed to the public key of the contact.
+	Key         string     `gorm:"not null"`
+	RequestedAt *time.Time `gorm:"nullable"`
+	WaitEndsAt  *time.Time `gorm:"nullable;index"`
+	GrantedAt   *time.Time `gorm:"nullable"`
+}
+
+type EmergencyModel struct {
+	DB *gorm.DB
+}
+
+// GetAll returns the emergency contacts of a given user.
+//
+// userId: the ID of the owner.
+// []EmergencyContact: the contacts with their profile, oldest first.
+// error: any error that occurred during the retrieval process.
+func (m *EmergencyModel) GetAll(userId uint) ([]EmergencyContact, error) {
+	var contacts []EmergencyContact
+
+	err := m.DB.Preload("Contact", selectUser).
+		Where("user_id = ?", userId).
+		Order("created_at, id").
+		Find(&contacts).Error
+
+	return contacts, err
+}
+
+// GetGrantors returns the users who chose a given user as emergency contact.
+//
+// contactId: the ID of the contact.
+// []EmergencyContact: the designations with the profile of their owner, oldest first.
+// error: any error that occurred during the retrieval process.
+func (m *EmergencyModel) GetGrantors(contactId uint) ([]EmergencyContact, error) {
+	var contacts []EmergencyContact
+
+	err := m.DB.Preload("User", selectUser).
+		Where("contact_id = ?", contactId).
+		Order("created_at, id").
+		Find(&contacts).Error
+
+	return contacts, err
+}
+
+// Get returns an emergency contact of a given user.
+//
+// Parameters:
+// - id: the ID of the emergency contact.
+// - userId: the ID of the owner.
+//
+// Returns:
+// - EmergencyContact: the contact with the profiles of both users.
+// - error: gorm.ErrRecordNotFound if the contact does not belong to the user.
+func (m *EmergencyModel) Get(id, userId uint) (EmergencyContact, error) {
+	var contact EmergencyContact
+
+	err := m.DB.Preload("User", selectUser).Preload("Contact", selectUser).
+		Where("id = ? AND user_id = ?", id, userId).
+		First(&contact).Error
+
+	return contact, err
+}
+
+// GetForContact returns an emergency contact designation as seen by the contact.
+//
+// Parameters:
+// - id: the ID of the emergency contact.
+// - contactId: the ID of the contact.
+//
+// Returns:
+// - EmergencyContact: the contact with the profiles of both users.
+// - error: gorm.ErrRecordNotFound if the user is not this contact.
+func (m *EmergencyModel) GetForContact(id, contactId uint) (EmergencyContact, error) {
+	var contact EmergencyContact
+
+	err := m.DB.Preload("User", selectUser).Preload("Contact", selectUser).
+		Where("id = ? AND contact_id = ?", id, contactId).
+		First(&contact).Error
+
+	return contact, err
+}
+
+// Exists reports whether a user already chose another user as emergency contact.
+//
+// Parameters:
+// - userId: the ID of the owner.
+// - contactId: the ID of the contact.
+//
+// Returns:
+// - bool: true if the contact exists.
+// - error: any error that occurred during the retrieval process.
+func (m *EmergencyModel) Exists(userId, contactId uint) (bool, error) {
+	var count int64
+
+	err := m.DB.Model(&EmergencyContact{}).Where("user_id = ? AND contact_id = ?", userId, contactId).Count(&count).Error
+
+	return count > 0, err
+}
+
+// Create saves an emergency contact.
+//
+// contact: the contact to save, in the idle status.
+// Returns the saved contact or an error if the insert fails.
+func (m *EmergencyModel) Create(contact EmergencyContact) (EmergencyContact, error) {
+	contact.Status = StatusIdle
+
+	err := m.DB.Omit("User", "Contact").Create(&contact).Error
+
+	return contact, err
+}
+
+// Update changes the access type and the waiting period of an emergency contact.
+//
+// A pending request keeps the end of its waiting period.
+//
+// Parameters:
+// - id: the ID of the emergency contact.
+// - userId: the ID of the owner.
+// - access: AccessView or AccessTakeover.
+// - waitDays: the waiting period in days.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound if the contact does not belong to the user, or any update error.
+func (m *EmergencyModel) Update(id, userId uint, access string, waitDays int) error {
+	result := m.DB.Model(&EmergencyContact{}).
+		Where("id = ? AND user_id = ?", id, userId).
+		Updates(map[string]interface{}{"access": access, "wait_days": waitDays})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// Delete removes an emergency contact, the sealed key is deleted with it.
+//
+// Parameters:
+// - id: the ID of the emergency contact.
+// - userId: the ID of the owner.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound if the contact does not belong to the user, or any deletion error.
+func (m *EmergencyModel) Delete(id, userId uint) error {
+	result := m.DB.Unscoped().Where("id = ? AND user_id = ?", id, userId).Delete(&EmergencyContact{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// Request starts the waiting period of an idle emergency contact.
+//
+// Parameters:
+// - id: the ID of the emergency contact.
+// - now: the time of the request.
+// - waitEndsAt: the end of the waiting period.
+//
+// Returns:
+// - bool: false if the contact was not idle anymore.
+// - error: any update error.
+func (m *EmergencyModel) Request(id uint, now, waitEndsAt time.Time) (bool, error) {
+	return m.transition(id, []string{StatusIdle}, map[string]interface{}{
+		"status":       StatusRequested,
+		"requested_at": now,
+		"wait_ends_at": waitEndsAt,
+		"granted_at":   nil,
+	})
+}
+
+// Grant grants the access of an emergency contact whose request is pending.
+//
+// Parameters:
+// - id: the ID of the emergency contact.
+// - now: the time of the grant.
+//
+// Returns:
+// - bool: false if no request was pending anymore.
+// - error: any update error.
+func (m *EmergencyModel) Grant(id uint, now time.Time) (bool, error) {
+	return m.transition(id, []string{StatusRequested}, map[string]interface{}{
+		"status":     StatusGranted,
+		"granted_at": now,
+	})
+}
+
+// Reset rejects the pending request or withdraws the granted access of an emergency contact.
+//
+// id: the ID of the emergency contact.
+// Returns false if the contact was already idle, and any update error.
+func (m *EmergencyModel) Reset(id uint) (bool, error) {
+	return m.transition(id, []string{StatusRequested, StatusGranted}, map[string]interface{}{
+		"status":       StatusIdle,
+		"requested_at": nil,
+		"wait_ends_at": nil,
+		"granted_at":   nil,
+	})
+}
+
+// GetAllDue returns the pending requests whose waiting period ended.
+//
+// now: the current time.
+// []EmergencyContact: the contacts with the profiles of both users.
+// error: any error that occurred during the retrieval process.
+func (m *EmergencyModel) GetAllDue(now time.Time) ([]EmergencyContact, error) {
+	var contacts []EmergencyContact
+
+	err := m.DB.Preload("User", selectUser).Preload("Contact", selectUser).
+		Where("status = ? AND wait_ends_at <= ?", StatusRequested, now).
+		Order("wait_ends_at, id").
+		Find(&contacts).Error
+
+	return contacts, err
+}
+
+// transition updates an emergency contact only if it is still in one of the given statuses,
+// so concurrent requests cannot apply the same transition twice.
+func (m *EmergencyModel) transition(id uint, from []string, values map[string]interface{}) (bool, error) {
+	result := m.DB.Model(&EmergencyContact{}).
+		Where("id = ? AND status IN ?", id, from).
+		Updates(values)
+
+	return result.RowsAffected == 1, result.Error
+}
+
+// selectUser only loads the public profile of a preloaded user.
+func selectUser(db *gorm.DB) *gorm.DB {
+	return db.Select("id", "name", "email")
+}
@@ -0,0 +1,686 @@
+package services
+
+import (
+	models3 "backend/modules/audit/models"
+	services3 "backend/modules/audit/services"
+	"backend/modules/emergency/models"
+	models4 "backend/modules/notifications/models"
+	services4 "backend/modules/notifications/services"
+	"backend/modules/passwords/services"
+	models2 "backend/modules/users/models"
+	"backend/services/encryption"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"time"
+)
+
+var (
+	ErrContactNotFound   = errors.New("no user with this email")
+	ErrContactSelf       = errors.New("users cannot be their own emergency contact")
+	ErrContactExists     = errors.New("this user already is an emergency contact")
+	ErrInvalidTransition = errors.New("the emergency access is not in a state allowing this operation")
+	ErrAccessNotGranted  = errors.New("the emergency access is not granted")
+	ErrTakeoverForbidden = errors.New("the emergency access does not allow a takeover")
+	ErrZeroKnowledge     = errors.New("the account cannot be taken over in the zero-knowledge vault mode, its vault key is only known to the client")
+)
+
+type EmergencyService struct {
+	DB *gorm.DB
+}
+
+// EmergencyAccess is an emergency contact seen by one of its two users, Email and Name are those of the other user.
+type EmergencyAccess struct {
+	ID          uint       `json:"id"`
+	Email       string     `json:"email"`
+	Name        string     `json:"name"`
+	Access      string     `json:"access"`
+	WaitDays    int        `json:"wait_days"`
+	Status      string     `json:"status"`
+	RequestedAt *time.Time `json:"requested_at"`
+	WaitEndsAt  *time.Time `json:"wait_ends_at"`
+	GrantedAt   *time.Time `json:"granted_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// getModel returns an EmergencyModel.
+//
+// No parameters.
+// Returns a models.EmergencyModel.
+func (s *EmergencyService) getModel() models.EmergencyModel {
+	return models.EmergencyModel{DB: s.DB}
+}
+
+// GetContacts returns the emergency contacts of a given user.
+//
+// Parameters:
+// - userId: the ID of the owner.
+//
+// Returns:
+// - []EmergencyAccess: the contacts, oldest first.
+// - error: any retrieval error.
+func (s *EmergencyService) GetContacts(userId uint) ([]EmergencyAccess, error) {
+	emergencyModel := s.getModel()
+
+	contacts, err := emergencyModel.GetAll(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	contactsList := []EmergencyAccess{}
+
+	for _, contact := range contacts {
+		contactsList = append(contactsList, toEmergencyAccess(contact, contact.Contact))
+	}
+
+	return contactsList, nil
+}
+
+// AddContact chooses a user as emergency contact.
+//
+// The data-encryption key of the owner is sealed to the public key of the contact, so the contact can decrypt
+// the vault once the access is granted.
+//
+// Parameters:
+// - userId: the ID of the owner.
+// - email: the email of the contact.
+// - access: models.AccessView or models.AccessTakeover.
+// - waitDays: the days the owner has to reject a request before the access is granted.
+// - client: the token, IP address and user agent of the request, for the audit trail.
+//
+// Returns:
+// - EmergencyAccess: the contact.
+// - error: ErrContactNotFound, ErrContactSelf, ErrContactExists or any key or creation error.
+func (s *EmergencyService) AddContact(userId uint, email, access string, waitDays int, client services3.Client) (EmergencyAccess, error) {
+	var contactUser models2.User
+
+	err := s.DB.Select("id", "name", "email").Where("email = ?", email).First(&contactUser).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return EmergencyAccess{}, ErrContactNotFound
+	}
+	if err != nil {
+		return EmergencyAccess{}, err
+	}
+
+	if contactUser.ID == userId {
+		return EmergencyAccess{}, ErrContactSelf
+	}
+
+	emergencyModel := s.getModel()
+
+	exists, err := emergencyModel.Exists(userId, contactUser.ID)
+	if err != nil {
+		return EmergencyAccess{}, err
+	}
+
+	if exists {
+		return EmergencyAccess{}, ErrContactExists
+	}
+
+	userModel := models2.UserModel{DB: s.DB}
+
+	dataKey, err := userModel.GetDataKey(userId)
+	if err != nil {
+		return EmergencyAccess{}, err
+	}
+
+	publicKey, _, err := userModel.GetKeyPair(contactUser.ID)
+	if err != nil {
+		return EmergencyAccess{}, err
+	}
+
+	key, err := encryption.Seal(publicKey, dataKey, keyContext(userId, contactUser.ID))
+	if err != nil {
+		return EmergencyAccess{}, err
+	}
+
+	var contact models.EmergencyContact
+
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		emergencyModel := models.EmergencyModel{DB: tx}
+
+		created, err := emergencyModel.Create(models.EmergencyContact{
+			UserID:    userId,
+			ContactID: contactUser.ID,
+			Access:    access,
+			WaitDays:  waitDays,
+			Key:       key,
+		})
+		if err != nil {
+			return err
+		}
+
+		if contact, err = emergencyModel.Get(created.ID, userId); err != nil {
+			return err
+		}
+
+		detail := fmt.Sprintf("%s chose %s as emergency contact with %s access after %d days", contact.User.Email, contactUser.Email, access, waitDays)
+
+		return s.record(tx, userId, contact, models3.ActionEmergencyContactAdd, detail, client)
+	})
+	if err != nil {
+		return EmergencyAccess{}, err
+	}
+
+	message := fmt.Sprintf("%s chose you as emergency contact, you can request %s access to their vault", contact.User.Email, access)
+
+	if err := s.notify(contact.ContactID, message); err != nil {
+		return EmergencyAccess{}, err
+	}
+
+	return toEmergencyAccess(contact, contact.Contact), nil
+}
+
+// UpdateContact changes the access type and the waiting period of an emergency contact.
+//
+// Parameters:
+// - id: the ID of the emergency contact.
+// - userId: the ID of the owner.
+// - access: models.AccessView or models.AccessTakeover.
+// - waitDays: the new waiting period in days, a pending request keeps the end of its waiting period.
+// - client: the token, IP address and user agent of the request, for the audit trail.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound if the contact does not belong to the user, or any update error.
+func (s *EmergencyService) UpdateContact(id, userId uint, access string, waitDays int, client services3.Client) error {
+	emergencyModel := s.getModel()
+
+	contact, err := emergencyModel.Get(id, userId)
+	if err != nil {
+		return err
+	}
+
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		emergencyModel := models.EmergencyModel{DB: tx}
+
+		if err := emergencyModel.Update(id, userId, access, waitDays); err != nil {
+			return err
+		}
+
+		detail := fmt.Sprintf("%s changed the emergency access of %s to %s access after %d days", contact.User.Email, contact.Contact.Email, access, waitDays)
+
+		return s.record(tx, userId, contact, models3.ActionEmergencyContactUpdate, detail, client)
+	})
+}
+
+// RemoveContact removes an emergency contact and any access it was granted.
+//
+// Parameters:
+// - id: the ID of the emergency contact.
+// - userId: the ID of the owner.
+// - client: the token, IP address and user agent of the request, for the audit trail.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound if the contact does not belong to the user, or any deletion error.
+func (s *EmergencyService) RemoveContact(id, userId uint, client services3.Client) error {
+	emergencyModel := s.getModel()
+
+	contact, err := emergencyModel.Get(id, userId)
+	if err != nil {
+		return err
+	}
+
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		emergencyModel := models.EmergencyModel{DB: tx}
+
+		if err := emergencyModel.Delete(id, userId); err != nil {
+			return err
+		}
+
+		detail := fmt.Sprintf("%s removed %s from their emergency contacts", contact.User.Email, contact.Contact.Email)
+
+		return s.record(tx, userId, contact, models3.ActionEmergencyContactRemove, detail, client)
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.notify(contact.ContactID, fmt.Sprintf("%s removed you from their emergency contacts", contact.User.Email))
+}
+
+// ApproveAccess grants a pending request of an emergency contact before the end of its waiting period.
+//
+// Parameters:
+// - id: the ID of the emergency contact.
+// - userId: the ID of the owner.
+// - client: the token, IP address and user agent of the request, for the audit trail.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound, ErrInvalidTransition if no request is pending, or any update error.
+func (s *EmergencyService) ApproveAccess(id, userId uint, client services3.Client) error {
+	emergencyModel := s.getModel()
+
+	contact, err := emergencyModel.Get(id, userId)
+	if err != nil {
+		return err
+	}
+
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		emergencyModel := models.EmergencyModel{DB: tx}
+
+		granted, err := emergencyModel.Grant(id, time.Now())
+		if err != nil {
+			return err
+		}
+
+		if !granted {
+			return ErrInvalidTransition
+		}
+
+		detail := fmt.Sprintf("%s approved the emergency access request of %s", contact.User.Email, contact.Contact.Email)
+
+		return s.record(tx, userId, contact, models3.ActionEmergencyApprove, detail, client)
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.notify(contact.ContactID, fmt.Sprintf("%s approved your emergency access request", contact.User.Email))
+}
+
+// RejectAccess rejects a pending request of an emergency contact, or withdraws its granted access.
+//
+// The contact stays an emergency contact and can request access again.
+//
+// Parameters:
+// - id: the ID of the emergency contact.
+// - userId: the ID of the owner.
+// - client: the token, IP address and user agent of the request, for the audit trail.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound, ErrInvalidTransition if nothing was requested, or any update error.
+func (s *EmergencyService) RejectAccess(id, userId uint, client services3.Client) error {
+	emergencyModel := s.getModel()
+
+	contact, err := emergencyModel.Get(id, userId)
+	if err != nil {
+		return err
+	}
+
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		emergencyModel := models.EmergencyModel{DB: tx}
+
+		reset, err := emergencyModel.Reset(id)
+		if err != nil {
+			return err
+		}
+
+		if !reset {
+			return ErrInvalidTransition
+		}
+
+		detail := fmt.Sprintf("%s rejected the emergency access of %s", contact.User.Email, contact.Contact.Email)
+
+		return s.record(tx, userId, contact, models3.ActionEmergencyReject, detail, client)
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.notify(contact.ContactID, fmt.Sprintf("%s rejected your emergency access", contact.User.Email))
+}
+
+// GetGrantors returns the users who chose a given user as emergency contact.
+//
+// Requests whose waiting period ended are granted first.
+//
+// Parameters:
+// - contactId: the ID of the contact.
+//
+// Returns:
+// - []EmergencyAccess: the designations, oldest first.
+// - error: any retrieval or update error.
+func (s *EmergencyService) GetGrantors(contactId uint) ([]EmergencyAccess, error) {
+	emergencyModel := s.getModel()
+
+	contacts, err := emergencyModel.GetGrantors(contactId)
+	if err != nil {
+		return nil, err
+	}
+
+	grantorsList := []EmergencyAccess{}
+
+	for _, contact := range contacts {
+		if contact, err = s.grantIfDue(contact); err != nil {
+			return nil, err
+		}
+
+		grantorsList = append(grantorsList, toEmergencyAccess(contact, contact.User))
+	}
+
+	return grantorsList, nil
+}
+
+// RequestAccess starts the waiting period of an emergency contact and notifies the owner.
+//
+// Parameters:
+// - id: the ID of the emergency contact.
+// - contactId: the ID of the contact.
+// - client: the token, IP address and user agent of the request, for the audit trail.
+//
+// Returns:
+// - EmergencyAccess: the designation with the end of the waiting period.
+// - error: gorm.ErrRecordNotFound, ErrInvalidTransition if access was already requested, or any update error.
+func (s *EmergencyService) RequestAccess(id, contactId uint, client services3.Client) (EmergencyAccess, error) {
+	emergencyModel := s.getModel()
+
+	contact, err := emergencyModel.GetForContact(id, contactId)
+	if err != nil {
+		return EmergencyAccess{}, err
+	}
+
+	now := time.Now()
+	waitEndsAt := now.AddDate(0, 0, contact.WaitDays)
+
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		emergencyModel := models.EmergencyModel{DB: tx}
+
+		requested, err := emergencyModel.Request(id, now, waitEndsAt)
+		if err != nil {
+			return err
+		}
+
+		if !requested {
+			return ErrInvalidTransition
+		}
+
+		contact.Status, contact.RequestedAt, contact.WaitEndsAt, contact.GrantedAt = models.StatusRequested, &now, &waitEndsAt, nil
+
+		detail := fmt.Sprintf("%s requested %s access to the vault of %s", contact.Contact.Email, contact.Access, contact.User.Email)
+
+		return s.record(tx, contactId, contact, models3.ActionEmergencyRequest, detail, client)
+	})
+	if err != nil {
+		return EmergencyAccess{}, err
+	}
+
+	message := fmt.Sprintf("%s requested %s access to your vault, it will be granted on %s unless you reject it",
+		contact.Contact.Email, contact.Access, waitEndsAt.Format("2006-01-02 15:04 MST"))
+
+	if err := s.notify(contact.UserID, message); err != nil {
+		return EmergencyAccess{}, err
+	}
+
+	if contact, err = s.grantIfDue(contact); err != nil {
+		return EmergencyAccess{}, err
+	}
+
+	return toEmergencyAccess(contact, contact.User), nil
+}
+
+// GetPasswords returns the vault of the owner of a granted emergency access.
+//
+// The passwords are decrypted with the data key of the owner unsealed with the private key of the contact.
+// Secrets are masked in the list, use GetPassword to read them. Client-encrypted entries are returned
+// as they are stored, the contact cannot decrypt them.
+//
+// Parameters:
+// - id: the ID of the emergency contact.
+// - contactId: the ID of the contact.
+// - client: the token, IP address and user agent of the request, for the audit trail.
+//
+// Returns:
+// - []services.Password: the masked passwords of the owner ordered by name.
+// - error: gorm.ErrRecordNotFound, ErrAccessNotGranted or any decryption error.
+func (s *EmergencyService) GetPasswords(id, contactId uint, client services3.Client) ([]services.Password, error) {
+	contact, passwordService, err := s.getVault(id, contactId)
+	if err != nil {
+		return nil, err
+	}
+
+	passwords, err := passwordService.GetPasswords(contact.UserID, services.PasswordFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	detail := fmt.Sprintf("%s listed the %d entries of %s with emergency access", contact.Contact.Email, len(passwords), contact.User.Email)
+
+	if err := s.record(s.DB, contactId, contact, models3.ActionEmergencyView, detail, client); err != nil {
+		return nil, err
+	}
+
+	return passwords, nil
+}
+
+// GetPassword returns a password of the owner of a granted emergency access with its secrets.
+//
+// The access is recorded in the access log of the password as well.
+//
+// Parameters:
+// - id: the ID of the emergency contact.
+// - passwordId: the ID of the password.
+// - contactId: the ID of the contact.
+// - client: the token, IP address and user agent of the request, for the audit trail.
+//
+// Returns:
+// - services.Password: the password.
+// - error: gorm.ErrRecordNotFound, ErrAccessNotGranted or any decryption error.
+func (s *EmergencyService) GetPassword(id, passwordId, contactId uint, client services3.Client) (services.Password, error) {
+	contact, passwordService, err := s.getVault(id, contactId)
+	if err != nil {
+		return services.Password{}, err
+	}
+
+	password, err := passwordService.OpenPassword(passwordId, contact.UserID, contactId, client)
+	if err != nil {
+		return services.Password{}, err
+	}
+
+	detail := fmt.Sprintf("%s viewed %q of %s with emergency access", contact.Contact.Email, password.Name, contact.User.Email)
+
+	if err := s.record(s.DB, contactId, contact, models3.ActionEmergencyView, detail, client); err != nil {
+		return services.Password{}, err
+	}
+
+	return password, nil
+}
+
+// Takeover sets a new login password on the account of the owner of a granted takeover access.
+//
+// The sessions of the owner are signed out. The vault stays readable since its key is wrapped by the server key,
+// accounts in the zero-knowledge vault mode cannot be taken over.
+//
+// Parameters:
+// - id: the ID of the emergency contact.
+// - contactId: the ID of the contact.
+// - password: the new login password of the owner.
+// - client: the token, IP address and user agent of the request, for the audit trail.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound, ErrAccessNotGranted, ErrTakeoverForbidden, ErrZeroKnowledge or any update error.
+func (s *EmergencyService) Takeover(id, contactId uint, password string, client services3.Client) error {
+	contact, err := s.getGranted(id, contactId)
+	if err != nil {
+		return err
+	}
+
+	if contact.Access != models.AccessTakeover {
+		return ErrTakeoverForbidden
+	}
+
+	var owner models2.User
+	if err := s.DB.Select("id", "vault_mode").Where("id = ?", contact.UserID).First(&owner).Error; err != nil {
+		return err
+	}
+
+	if owner.VaultMode == models2.VaultModeZeroKnowledge {
+		return ErrZeroKnowledge
+	}
+
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		userModel := models2.UserModel{DB: tx}
+
+		if err := userModel.ResetPassword(contact.UserID, password); err != nil {
+			return err
+		}
+
+		detail := fmt.Sprintf("%s took over the account of %s with emergency access", contact.Contact.Email, contact.User.Email)
+
+		return s.record(tx, contactId, contact, models3.ActionEmergencyTakeover, detail, client)
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.notify(contact.UserID, fmt.Sprintf("%s took over your account with emergency access and changed your password", contact.Contact.Email))
+}
+
+// GrantDue grants the pending requests whose waiting period ended without the owner rejecting them.
+//
+// It returns the number of granted requests and an error if the requests cannot be read or updated.
+func (s *EmergencyService) GrantDue() (int, error) {
+	emergencyModel := s.getModel()
+
+	contacts, err := emergencyModel.GetAllDue(time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	granted := 0
+
+	for _, contact := range contacts {
+		contact, err := s.grantIfDue(contact)
+		if err != nil {
+			return granted, err
+		}
+
+		if contact.Status == models.StatusGranted {
+			granted++
+		}
+	}
+
+	return granted, nil
+}
+
+// grantIfDue grants a pending request whose waiting period ended, records it and notifies both users.
+func (s *EmergencyService) grantIfDue(contact models.EmergencyContact) (models.EmergencyContact, error) {
+	now := time.Now()
+
+	if contact.Status != models.StatusRequested || contact.WaitEndsAt == nil || contact.WaitEndsAt.After(now) {
+		return contact, nil
+	}
+
+	granted := false
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		emergencyModel := models.EmergencyModel{DB: tx}
+
+		var err error
+		if granted, err = emergencyModel.Grant(contact.ID, now); err != nil || !granted {
+			return err
+		}
+
+		detail := fmt.Sprintf("%s access of %s to the vault of %s was granted after %d days without rejection",
+			contact.Access, contact.Contact.Email, contact.User.Email, contact.WaitDays)
+
+		return s.record(tx, 0, contact, models3.ActionEmergencyGrant, detail, services3.Client{})
+	})
+	if err != nil || !granted {
+		return contact, err
+	}
+
+	contact.Status, contact.GrantedAt = models.StatusGranted, &now
+
+	if err := s.notify(contact.UserID, fmt.Sprintf("%s was granted emergency access to your vault", contact.Contact.Email)); err != nil {
+		return contact, err
+	}
+
+	return contact, s.notify(contact.ContactID, fmt.Sprintf("your emergency access to the vault of %s was granted", contact.User.Email))
+}
+
+// getGranted returns an emergency contact of a contact whose access is granted.
+func (s *EmergencyService) getGranted(id, contactId uint) (models.EmergencyContact, error) {
+	emergencyModel := s.getModel()
+
+	contact, err := emergencyModel.GetForContact(id, contactId)
+	if err != nil {
+		return models.EmergencyContact{}, err
+	}
+
+	if contact, err = s.grantIfDue(contact); err != nil {
+		return models.EmergencyContact{}, err
+	}
+
+	if contact.Status != models.StatusGranted {
+		return models.EmergencyContact{}, ErrAccessNotGranted
+	}
+
+	return contact, nil
+}
+
+// getVault unseals the data key of the owner of a granted emergency access
+// and returns a PasswordService decrypting with it.
+func (s *EmergencyService) getVault(id, contactId uint) (models.EmergencyContact, services.PasswordService, error) {
+	contact, err := s.getGranted(id, contactId)
+	if err != nil {
+		return models.EmergencyContact{}, services.PasswordService{}, err
+	}
+
+	userModel := models2.UserModel{DB: s.DB}
+
+	_, privateKey, err := userModel.GetKeyPair(contactId)
+	if err != nil {
+		return models.EmergencyContact{}, services.PasswordService{}, err
+	}
+
+	dataKey, err := encryption.Open(privateKey, contact.Key, keyContext(contact.UserID, contactId))
+	if err != nil {
+		return models.EmergencyContact{}, services.PasswordService{}, err
+	}
+
+	cipher, err := encryption.NewCipher(dataKey)
+	if err != nil {
+		return models.EmergencyContact{}, services.PasswordService{}, err
+	}
+
+	return contact, services.PasswordService{DB: s.DB, Cipher: &cipher}, nil
+}
+
+// record saves an emergency access transition in the audit trail of the owner and of the contact.
+//
+// tx is the transaction changing the state, so a transition is never saved without its audit events.
+// The client of the request is only recorded for the user who made it, actorId is 0 for background transitions.
+func (s *EmergencyService) record(tx *gorm.DB, actorId uint, contact models.EmergencyContact, action, detail string, client services3.Client) error {
+	auditService := services3.AuditService{DB: tx}
+
+	for _, userId := range []uint{contact.UserID, contact.ContactID} {
+		userClient := services3.Client{}
+		if userId == actorId {
+			userClient = client
+		}
+
+		if err := auditService.Record(userId, action, detail, userClient); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// notify sends an emergency access notification to a user.
+func (s *EmergencyService) notify(userId uint, message string) error {
+	notificationService := services4.NotificationService{DB: s.DB}
+
+	return notificationService.Notify(userId, models4.TypeEmergencyAccess, message)
+}
+
+// keyContext is the additional data binding the sealed data key to its owner and contact.
+func keyContext(userId, contactId uint) string {
+	return fmt.Sprintf("emergency_access:%d:%d", userId, contactId)
+}
+
+// toEmergencyAccess converts an emergency contact to its response representation seen by one of its users.
+func toEmergencyAccess(contact models.EmergencyContact, other models2.User) EmergencyAccess {
+	return EmergencyAccess{
+		ID:          contact.ID,
+		Email:       other.Email,
+		Name:        other.Name,
+		Access:      contact.Access,
+		WaitDays:    contact.WaitDays,
+		Status:      contact.Status,
+		RequestedAt: contact.RequestedAt,
+		WaitEndsAt:  contact.WaitEndsAt,
+		GrantedAt:   contact.GrantedAt,
+		CreatedAt:   contact.CreatedAt,
+	}
+}
@@ -0,0 +1,96 @@
+package actions
+
+import (
+	services3 "backend/modules/audit/services"
+	"backend/modules/exports/services"
+	"backend/modules/users/models"
+	services2 "backend/services"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"mime"
+	"net/http"
+)
+
+type ExportRequest struct {
+	Format string `json:"format" binding:"required,oneof=json kdbx"`
+	// Password is the login password, exports require re-authentication.
+	Password string `json:"password" binding:"required"`
+	// Passphrase protects the export file.
+	Passphrase string `json:"passphrase" binding:"required,min=12,max=1024"`
+}
+
+// ExportVault exports the vault of the logged-in user.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Export vault
+// @Description Exports the categories, entries, custom fields and attachments as a passphrase-protected JSON archive
+// @Description (Argon2id and AES-256-GCM, see the README for the schema) or as a KeePass KDBX 4 file whose master
+// @Description password is the passphrase. The login password is required and the export is recorded in the audit trail.
+// @Description Wrong login passwords are recorded too, after 5 of them in 15 minutes exports are refused with 429.
+// @Description Client-encrypted entries are not exported. The file is streamed, a failure while it is written truncates it.
+// @Tags Export
+// @Accept  json
+// @Produce  octet-stream
+// @Security BearerAuth
+// @Param   exportRequest  body    ExportRequest  true  "Export format, login password and passphrase"
+// @Success 200 {file} file
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 401 {object} services2.ErrorResponse
+// @Failure 429 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /export [post]
+func ExportVault(c *gin.Context) {
+	var request ExportRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	exportService, user := getServiceAndUser(c)
+
+	file, err := exportService.Export(c.Request.Context(), user.User.ID, services.ExportOptions{
+		Format:     request.Format,
+		Password:   request.Password,
+		Passphrase: request.Passphrase,
+	}, services3.Client{TokenID: &user.ID, IP: c.ClientIP(), UserAgent: c.Request.UserAgent()})
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": file.Name}))
+	c.Header("Content-Type", file.ContentType)
+	c.Header("Cache-Control", "no-store")
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Status(http.StatusOK)
+
+	// The status is already sent, the client gets a truncated file that does not open.
+	if err := file.Write(c.Writer); err != nil {
+		_ = c.Error(err)
+	}
+}
+
+// errorStatus maps a service error to the HTTP status code of the response.
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrWrongPassword):
+		return http.StatusUnauthorized
+	case errors.Is(err, services.ErrTooManyAttempts):
+		return http.StatusTooManyRequests
+	case errors.Is(err, services.ErrUnknownFormat), errors.Is(err, services.ErrZeroKnowledge):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// getServiceAndUser returns the export service and user token.
+//
+// It takes a Gin context as a parameter.
+// It returns an ExportService and a Token.
+func getServiceAndUser(c *gin.Context) (services.ExportService, models.Token) {
+	service := services.ExportService{DB: services2.GetDBConnection()}
+	user := services2.GetUserFromContext(c)
+
+	return service, user
+}
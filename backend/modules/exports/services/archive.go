@@ -0,0 +1,345 @@
+package services
+
+import (
+	"backend/modules/passwords/services"
+	"backend/modules/users/models"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"golang.org/x/crypto/argon2"
+	"io"
+	"strconv"
+	"time"
+)
+
+const (
+	ArchiveFormat  = "save-my-pass-export"
+	ArchiveVersion = 2
+	// ArchiveChunkSize is the size of the plaintext sealed in each chunk, the last chunk may be shorter.
+	ArchiveChunkSize = 64 * 1024
+)
+
+// Archive is the passphrase-protected JSON export.
+//
+// The key is derived from the passphrase with Argon2id using the kdf parameters (memory in KiB),
+// and the Vault encoded as JSON is split into chunks of chunk_size bytes, each sealed with AES-256-GCM
+// and the "save-my-pass-export/2" additional data. The nonce of a chunk is the 7 bytes of nonce,
+// the index of the chunk as a big-endian uint32 and a byte set to 1 for the last chunk, 0 otherwise,
+// so chunks cannot be reordered, dropped or truncated. Byte values are encoded with standard base64.
+type Archive struct {
+	Format    string     `json:"format"`
+	Version   int        `json:"version"`
+	Kdf       ArchiveKdf `json:"kdf"`
+	Cipher    string     `json:"cipher"`
+	ChunkSize int        `json:"chunk_size"`
+	Nonce     []byte     `json:"nonce"`
+	// Chunks is streamed by writeArchive, it must stay the last field.
+	Chunks [][]byte `json:"chunks"`
+}
+
+type ArchiveKdf struct {
+	Algorithm   string `json:"algorithm"`
+	Salt        []byte `json:"salt"`
+	Memory      uint32 `json:"memory"`
+	Iterations  uint32 `json:"iterations"`
+	Parallelism uint8  `json:"parallelism"`
+}
+
+// Vault is the decrypted content of an archive.
+type Vault struct {
+	ExportedAt time.Time  `json:"exported_at"`
+	Categories []Category `json:"categories"`
+	// Entries is streamed by writeVault, it must stay the last field.
+	Entries []Entry `json:"entries"`
+}
+
+type Category struct {
+	ID           uint   `json:"id"`
+	Name         string `json:"name"`
+	RotationDays *int   `json:"rotation_days"`
+}
+
+// Entry is a password of any type, the payload of notes, cards, identities and Wi-Fi networks
+// is in the object named after the type, as in the API.
+type Entry struct {
+	ID         uint  `json:"id"`
+	CategoryID *uint `json:"category_id"`
+	services.Item
+	Name         string           `json:"name"`
+	Login        string           `json:"login"`
+	Password     string           `json:"password"`
+	Totp         string           `json:"totp"`
+	Fields       []services.Field `json:"fields"`
+	URIs         []services.URI   `json:"uris"`
+	Tags         []string         `json:"tags"`
+	RotationDays *int             `json:"rotation_days"`
+	// Attachments is streamed by writeVault, it must stay the last field.
+	Attachments []Attachment `json:"attachments"`
+}
+
+// Attachment is a file of an entry, its content is read from the storage while the export is written.
+type Attachment struct {
+	Name        string `json:"name"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	// Data is streamed by writeVault from open, it must stay the last field.
+	Data []byte `json:"data"`
+	open func() (io.ReadCloser, error)
+}
+
+// writeArchive writes a vault as a JSON archive protected by a passphrase.
+//
+// The vault is encrypted while it is written, so the attachments are never held in memory.
+func writeArchive(w io.Writer, vault Vault, passphrase string) error {
+	archive := Archive{
+		Format:  ArchiveFormat,
+		Version: ArchiveVersion,
+		Kdf: ArchiveKdf{
+			Algorithm:   "argon2id",
+			Salt:        make([]byte, 16),
+			Memory:      models.DefaultKdfMemory,
+			Iterations:  models.DefaultKdfIterations,
+			Parallelism: models.DefaultKdfParallelism,
+		},
+		Cipher:    "aes-256-gcm",
+		ChunkSize: ArchiveChunkSize,
+		Nonce:     make([]byte, 7),
+	}
+
+	if _, err := rand.Read(archive.Kdf.Salt); err != nil {
+		return err
+	}
+
+	if _, err := rand.Read(archive.Nonce); err != nil {
+		return err
+	}
+
+	key := argon2.IDKey([]byte(passphrase), archive.Kdf.Salt, archive.Kdf.Iterations, archive.Kdf.Memory, archive.Kdf.Parallelism, 32)
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return err
+	}
+
+	head, err := marshalOpen(archive)
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write(append(head, '[')); err != nil {
+		return err
+	}
+
+	chunks := &chunkWriter{w: w, gcm: gcm, prefix: archive.Nonce}
+
+	if err := writeVault(chunks, vault); err != nil {
+		return err
+	}
+
+	if err := chunks.Close(); err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "]}\n")
+
+	return err
+}
+
+// writeVault writes a vault as JSON, streaming the content of the attachments as base64.
+func writeVault(w io.Writer, vault Vault) error {
+	entries := vault.Entries
+	vault.Entries = nil
+
+	head, err := marshalOpen(vault)
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write(append(head, '[')); err != nil {
+		return err
+	}
+
+	for i, entry := range entries {
+		if i > 0 {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+
+		if err := writeEntry(w, entry); err != nil {
+			return err
+		}
+	}
+
+	_, err = io.WriteString(w, "]}")
+
+	return err
+}
+
+// writeEntry writes an entry as JSON with the content of its attachments.
+func writeEntry(w io.Writer, entry Entry) error {
+	attachments := entry.Attachments
+	entry.Attachments = nil
+
+	head, err := marshalOpen(entry)
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write(append(head, '[')); err != nil {
+		return err
+	}
+
+	for i, attachment := range attachments {
+		if i > 0 {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+
+		head, err := marshalOpen(attachment)
+		if err != nil {
+			return err
+		}
+
+		if _, err := w.Write(append(head, '"')); err != nil {
+			return err
+		}
+
+		if err := copyAttachment(w, attachment); err != nil {
+			return err
+		}
+
+		if _, err := io.WriteString(w, `"}`); err != nil {
+			return err
+		}
+	}
+
+	_, err = io.WriteString(w, "]}")
+
+	return err
+}
+
+// copyAttachment writes the content of an attachment encoded with base64.
+func copyAttachment(w io.Writer, attachment Attachment) error {
+	content, err := attachment.open()
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	encoder := base64.NewEncoder(base64.StdEncoding, w)
+	if _, err := io.Copy(encoder, content); err != nil {
+		return err
+	}
+
+	return encoder.Close()
+}
+
+// marshalOpen encodes a value whose last field is nil as JSON, without the null value and the closing brace,
+// so the content of the last field can be streamed after it.
+func marshalOpen(value interface{}) ([]byte, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	open, found := bytes.CutSuffix(data, []byte("null}"))
+	if !found {
+		return nil, fmt.Errorf("%T must end with a nil field", value)
+	}
+
+	return open, nil
+}
+
+// chunkWriter seals the archive content in chunks and writes them as a JSON array of base64 strings,
+// Close seals the last chunk.
+type chunkWriter struct {
+	w      io.Writer
+	gcm    cipher.AEAD
+	prefix []byte
+	index  uint32
+	buffer []byte
+}
+
+// Write buffers the content, a full chunk is sealed once more content follows it.
+func (c *chunkWriter) Write(p []byte) (int, error) {
+	written := len(p)
+
+	for len(p) > 0 {
+		if len(c.buffer) == ArchiveChunkSize {
+			if err := c.seal(false); err != nil {
+				return 0, err
+			}
+		}
+
+		size := min(ArchiveChunkSize-len(c.buffer), len(p))
+		c.buffer = append(c.buffer, p[:size]...)
+		p = p[size:]
+	}
+
+	return written, nil
+}
+
+// Close seals the remaining content as the last chunk.
+func (c *chunkWriter) Close() error {
+	return c.seal(true)
+}
+
+// seal encrypts the buffered content and writes it as an element of the chunks array.
+func (c *chunkWriter) seal(last bool) error {
+	nonce := binary.BigEndian.AppendUint32(append([]byte{}, c.prefix...), c.index)
+	if last {
+		nonce = append(nonce, 1)
+	} else {
+		nonce = append(nonce, 0)
+	}
+
+	sealed := c.gcm.Seal(nil, nonce, c.buffer, []byte(ArchiveFormat+"/"+strconv.Itoa(ArchiveVersion)))
+
+	element := `"` + base64.StdEncoding.EncodeToString(sealed) + `"`
+	if c.index > 0 {
+		element = "," + element
+	}
+
+	if _, err := io.WriteString(c.w, element); err != nil {
+		return err
+	}
+
+	c.index++
+	c.buffer = c.buffer[:0]
+
+	return nil
+}
+
+// toEntry converts a password to an entry of the export, without its attachments.
+func toEntry(password services.Password) Entry {
+	tags := []string{}
+	for _, tag := range password.Tags {
+		tags = append(tags, tag.Name)
+	}
+
+	return Entry{
+		ID:           password.ID,
+		CategoryID:   password.CategoryID,
+		Item:         password.Item,
+		Name:         password.Name,
+		Login:        password.Login,
+		Password:     password.Password,
+		Totp:         password.Totp,
+		Fields:       password.Fields,
+		URIs:         password.URIs,
+		Tags:         tags,
+		RotationDays: password.RotationDays,
+	}
+}
@@ -0,0 +1,250 @@
+package services
+
+import (
+	models3 "backend/modules/audit/models"
+	services3 "backend/modules/audit/services"
+	models2 "backend/modules/categories/models"
+	"backend/modules/passwords/services"
+	"backend/modules/users/models"
+	"context"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"io"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	FormatJSON = "json"
+	FormatKDBX = "kdbx"
+)
+
+// A user may enter a wrong login password maxFailedExports times in failedExportWindow,
+// further exports are refused until older failures leave the window.
+const (
+	maxFailedExports   = 5
+	failedExportWindow = 15 * time.Minute
+)
+
+var Formats = []string{FormatJSON, FormatKDBX}
+
+var (
+	ErrUnknownFormat   = errors.New("unknown export format")
+	ErrWrongPassword   = bcrypt.ErrMismatchedHashAndPassword
+	ErrZeroKnowledge   = errors.New("exports are not available in the zero-knowledge vault mode, export on the client")
+	ErrTooManyAttempts = errors.New("too many failed export attempts, try again later")
+)
+
+type ExportService struct {
+	DB *gorm.DB
+}
+
+type ExportOptions struct {
+	Format string
+	// Password is the login password of the user, exports require re-authentication.
+	Password string
+	// Passphrase protects the export: the key of the JSON archive is derived from it,
+	// and it is the master password of the KeePass file.
+	Passphrase string
+}
+
+// ExportFile is an export ready to be downloaded, its content is written by Write.
+type ExportFile struct {
+	Name        string
+	ContentType string
+	write       func(w io.Writer) error
+}
+
+// Write encrypts the export to w, reading the attachments from the storage on the way.
+//
+// If an error is returned, part of the file may have been written already. Both formats authenticate
+// their end, so a truncated file does not open.
+//
+// w: the destination of the file.
+// Returns an error if an attachment cannot be read or the destination fails.
+func (f ExportFile) Write(w io.Writer) error {
+	return f.write(w)
+}
+
+// Export prepares the export of the vault of a given user and records it in the audit trail.
+//
+// The export contains the categories, the entries with their custom fields, URIs and tags,
+// and the attachments. Client-encrypted entries are not included, the server cannot decrypt them.
+// A wrong login password is recorded in the audit trail as well, and exports are refused after
+// too many of them.
+//
+// Parameters:
+// - ctx: the context of the request, used to read the attachments.
+// - userId: the ID of the user.
+// - options: the format, the login password and the passphrase of the export.
+// - client: the token, IP address and user agent of the request, for the audit trail.
+//
+// Returns:
+// - ExportFile: the export to write.
+// - error: ErrUnknownFormat, ErrTooManyAttempts, ErrWrongPassword, ErrZeroKnowledge or any retrieval or audit error.
+func (s *ExportService) Export(ctx context.Context, userId uint, options ExportOptions, client services3.Client) (ExportFile, error) {
+	if !slices.Contains(Formats, options.Format) {
+		return ExportFile{}, fmt.Errorf("%w %q, expected one of %s", ErrUnknownFormat, options.Format, strings.Join(Formats, ", "))
+	}
+
+	if err := s.checkPassword(userId, options, client); err != nil {
+		return ExportFile{}, err
+	}
+
+	var user models.User
+	if err := s.DB.Select("id", "vault_mode").Where("id = ?", userId).First(&user).Error; err != nil {
+		return ExportFile{}, err
+	}
+
+	if user.VaultMode == models.VaultModeZeroKnowledge {
+		return ExportFile{}, ErrZeroKnowledge
+	}
+
+	vault, attachments, err := s.getVault(ctx, userId)
+	if err != nil {
+		return ExportFile{}, err
+	}
+
+	file := ExportFile{Name: "save-my-pass-" + vault.ExportedAt.Format(time.DateOnly)}
+
+	switch options.Format {
+	case FormatJSON:
+		file.Name += ".json"
+		file.ContentType = "application/json"
+		file.write = func(w io.Writer) error {
+			return writeArchive(w, vault, options.Passphrase)
+		}
+	case FormatKDBX:
+		file.Name += ".kdbx"
+		file.ContentType = "application/octet-stream"
+		file.write = func(w io.Writer) error {
+			return writeKeePass(w, vault, options.Passphrase)
+		}
+	}
+
+	// The export is recorded before it is written, so an interrupted download is recorded as well.
+	detail := fmt.Sprintf("%s export of %d entries and %d attachments", options.Format, len(vault.Entries), attachments)
+
+	auditService := services3.AuditService{DB: s.DB}
+
+	if err := auditService.Record(userId, models3.ActionVaultExport, detail, client); err != nil {
+		return ExportFile{}, err
+	}
+
+	return file, nil
+}
+
+// checkPassword re-authenticates a user before an export and records a wrong password in the audit trail.
+//
+// The user row is locked from counting the failures until the attempt is recorded, so parallel attempts
+// are checked one after another and cannot exceed maxFailedExports.
+//
+// It returns ErrTooManyAttempts, ErrWrongPassword or any retrieval or audit error.
+func (s *ExportService) checkPassword(userId uint, options ExportOptions, client services3.Client) error {
+	var checkErr error
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			Where("id = ?", userId).
+			First(&models.User{}).Error
+		if err != nil {
+			return err
+		}
+
+		auditService := services3.AuditService{DB: tx}
+
+		failures, err := auditService.Count(userId, models3.ActionVaultExportFailed, time.Now().Add(-failedExportWindow))
+		if err != nil {
+			return err
+		}
+
+		if failures >= maxFailedExports {
+			return ErrTooManyAttempts
+		}
+
+		userModel := models.UserModel{DB: tx}
+
+		// A wrong password is recorded and the transaction committed, other errors roll it back.
+		checkErr = userModel.CheckPassword(userId, options.Password)
+		if !errors.Is(checkErr, ErrWrongPassword) {
+			return checkErr
+		}
+
+		detail := fmt.Sprintf("%s export refused, wrong login password", options.Format)
+
+		return auditService.Record(userId, models3.ActionVaultExportFailed, detail, client)
+	})
+	if err != nil {
+		return err
+	}
+
+	return checkErr
+}
+
+// getVault reads the categories and the entries of a user, the attachments are opened when the export is written.
+//
+// It returns the vault and the number of attachments.
+func (s *ExportService) getVault(ctx context.Context, userId uint) (Vault, int, error) {
+	vault := Vault{ExportedAt: time.Now().UTC(), Categories: []Category{}, Entries: []Entry{}}
+
+	categoryModel := models2.CategoryModel{DB: s.DB}
+
+	categories, err := categoryModel.GetAll(userId)
+	if err != nil {
+		return Vault{}, 0, err
+	}
+
+	for _, category := range categories {
+		vault.Categories = append(vault.Categories, Category{
+			ID:           category.ID,
+			Name:         category.Name,
+			RotationDays: category.RotationDays,
+		})
+	}
+
+	passwordService := services.PasswordService{DB: s.DB}
+
+	passwords, err := passwordService.GetVault(userId)
+	if err != nil {
+		return Vault{}, 0, err
+	}
+
+	count := 0
+
+	for _, password := range passwords {
+		entry := toEntry(password)
+
+		attachments, err := passwordService.GetAttachments(password.ID, userId)
+		if err != nil {
+			return Vault{}, 0, err
+		}
+
+		for _, attachment := range attachments {
+			entry.Attachments = append(entry.Attachments, Attachment{
+				Name:        attachment.Name,
+				ContentType: attachment.ContentType,
+				Size:        attachment.Size,
+				open:        openAttachment(ctx, &passwordService, attachment.ID, password.ID, userId),
+			})
+			count++
+		}
+
+		vault.Entries = append(vault.Entries, entry)
+	}
+
+	return vault, count, nil
+}
+
+// openAttachment returns a function opening the decrypted content of an attachment.
+func openAttachment(ctx context.Context, passwordService *services.PasswordService, id, passwordId, userId uint) func() (io.ReadCloser, error) {
+	return func() (io.ReadCloser, error) {
+		_, content, err := passwordService.OpenAttachment(ctx, id, passwordId, userId)
+
+		return content, err
+	}
+}
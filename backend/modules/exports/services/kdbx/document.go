@@ -0,0 +1,144 @@
+package kdbx
+
+import (
+	"encoding/base64"
+	"encoding/xml"
+	"golang.org/x/crypto/chacha20"
+	"strings"
+)
+
+type xmlFile struct {
+	XMLName xml.Name `xml:"KeePassFile"`
+	Meta    xmlMeta  `xml:"Meta"`
+	Root    struct {
+		Group xmlGroup `xml:"Group"`
+	} `xml:"Root"`
+}
+
+type xmlMeta struct {
+	Generator        string `xml:"Generator"`
+	DatabaseName     string `xml:"DatabaseName"`
+	MemoryProtection struct {
+		ProtectTitle    string `xml:"ProtectTitle"`
+		ProtectUserName string `xml:"ProtectUserName"`
+		ProtectPassword string `xml:"ProtectPassword"`
+		ProtectURL      string `xml:"ProtectURL"`
+		ProtectNotes    string `xml:"ProtectNotes"`
+	} `xml:"MemoryProtection"`
+	RecycleBinEnabled string `xml:"RecycleBinEnabled"`
+}
+
+type xmlGroup struct {
+	UUID    string     `xml:"UUID"`
+	Name    string     `xml:"Name"`
+	Entries []xmlEntry `xml:"Entry"`
+	Groups  []xmlGroup `xml:"Group"`
+}
+
+type xmlEntry struct {
+	UUID     string      `xml:"UUID"`
+	Tags     string      `xml:"Tags,omitempty"`
+	Strings  []xmlString `xml:"String"`
+	Binaries []xmlBinary `xml:"Binary"`
+}
+
+type xmlString struct {
+	Key   string `xml:"Key"`
+	Value struct {
+		Text      string `xml:",chardata"`
+		Protected string `xml:"Protected,attr,omitempty"`
+	} `xml:"Value"`
+}
+
+type xmlBinary struct {
+	Key   string `xml:"Key"`
+	Value struct {
+		Ref int `xml:"Ref,attr"`
+	} `xml:"Value"`
+}
+
+// document is the XML document of a database and the attachments referenced by its entries.
+//
+// The attachments are not opened, their content is copied by writeInner.
+type document struct {
+	file     xmlFile
+	binaries []Binary
+	stream   *chacha20.Cipher
+	err      error
+}
+
+// newDocument builds the XML document, encrypting the protected values with the inner stream.
+//
+// The groups are built in the order they are encoded, entries before subgroups,
+// so the values are decrypted with the same key stream.
+func newDocument(database Database, stream *chacha20.Cipher) (*document, error) {
+	d := &document{stream: stream}
+
+	d.file.Meta.Generator = "Save My Pass"
+	d.file.Meta.DatabaseName = database.Name
+	d.file.Meta.MemoryProtection.ProtectTitle = "False"
+	d.file.Meta.MemoryProtection.ProtectUserName = "False"
+	d.file.Meta.MemoryProtection.ProtectPassword = "True"
+	d.file.Meta.MemoryProtection.ProtectURL = "False"
+	d.file.Meta.MemoryProtection.ProtectNotes = "False"
+	d.file.Meta.RecycleBinEnabled = "False"
+	d.file.Root.Group = d.group(database.Root)
+
+	return d, d.err
+}
+
+// group converts a group and its subgroups.
+func (d *document) group(group Group) xmlGroup {
+	converted := xmlGroup{UUID: d.uuid(), Name: group.Name}
+
+	for _, entry := range group.Entries {
+		converted.Entries = append(converted.Entries, d.entry(entry))
+	}
+
+	for _, subgroup := range group.Groups {
+		converted.Groups = append(converted.Groups, d.group(subgroup))
+	}
+
+	return converted
+}
+
+// entry converts an entry and adds its attachments to the binary pool.
+func (d *document) entry(entry Entry) xmlEntry {
+	converted := xmlEntry{UUID: d.uuid(), Tags: strings.Join(entry.Tags, ";")}
+
+	for _, value := range entry.Strings {
+		var field xmlString
+		field.Key = value.Key
+		field.Value.Text = value.Value
+
+		if value.Protected {
+			field.Value.Text = protect(d.stream, value.Value)
+			field.Value.Protected = "True"
+		}
+
+		converted.Strings = append(converted.Strings, field)
+	}
+
+	for _, attachment := range entry.Binaries {
+		var field xmlBinary
+		field.Key = attachment.Name
+		field.Value.Ref = len(d.binaries)
+
+		d.binaries = append(d.binaries, attachment)
+		converted.Binaries = append(converted.Binaries, field)
+	}
+
+	return converted
+}
+
+// uuid returns a random identifier of a group or entry, the error is kept in the document.
+func (d *document) uuid() string {
+	if d.err != nil {
+		return ""
+	}
+
+	var id []byte
+	id, d.err = random(16)
+
+	return base64.StdEncoding.EncodeToString(id)
+}
@@ -0,0 +1,470 @@
+package kdbx
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/xml"
+	"errors"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20"
+	"io"
+	"math"
+)
+
+// ErrBinarySize is returned when the content of an attachment is longer than its size.
+var ErrBinarySize = errors.New("attachment content does not match its size")
+
+// blockSize is the size of the blocks of the HMAC-protected payload.
+const blockSize = 1 << 20
+
+const (
+	signature1 uint32 = 0x9AA2D903
+	signature2 uint32 = 0xB54BFB67
+	version4   uint32 = 0x00040000
+)
+
+// Outer header fields.
+const (
+	headerEnd         byte = 0
+	headerCipherID    byte = 2
+	headerCompression byte = 3
+	headerMasterSeed  byte = 4
+	headerIV          byte = 7
+	headerKdf         byte = 11
+)
+
+// Inner header fields.
+const (
+	innerEnd       byte = 0
+	innerStreamID  byte = 1
+	innerStreamKey byte = 2
+	innerBinary    byte = 3
+)
+
+// Value types of a variant dictionary.
+const (
+	variantUInt32    byte = 0x04
+	variantUInt64    byte = 0x05
+	variantByteArray byte = 0x42
+)
+
+var (
+	cipherAES256 = []byte{0x31, 0xc1, 0xf2, 0xe6, 0xbf, 0x71, 0x43, 0x50, 0xbe, 0x58, 0x05, 0x21, 0x6a, 0xfc, 0x5a, 0xff}
+	kdfArgon2id  = []byte{0x9e, 0x29, 0x8b, 0x19, 0x56, 0xdb, 0x47, 0x73, 0xb2, 0x3d, 0xfc, 0x3e, 0xc6, 0xf0, 0xa1, 0xe6}
+)
+
+// streamChaCha20 is the ID of the ChaCha20 inner random stream protecting the values in the XML.
+const streamChaCha20 uint32 = 3
+
+// Database is the content of a KeePass file.
+type Database struct {
+	Name string
+	Root Group
+}
+
+// Group is a KeePass group, shown as a folder.
+type Group struct {
+	Name    string
+	Entries []Entry
+	Groups  []Group
+}
+
+// Entry is a KeePass entry. Title, UserName, Password, URL, Notes and otp are the standard keys.
+type Entry struct {
+	Strings  []String
+	Tags     []string
+	Binaries []Binary
+}
+
+// String is a value of an entry, protected values are encrypted in the file and hidden by KeePass.
+type String struct {
+	Key       string
+	Value     string
+	Protected bool
+}
+
+// Binary is a file attached to an entry, its content is copied to the file while it is written.
+type Binary struct {
+	Name string
+	// Size is the length of the content.
+	Size int64
+	// Open returns the content, it is called once by Write.
+	Open func() (io.ReadCloser, error)
+}
+
+// KdfParams are the Argon2id parameters deriving the key of the file, Memory is in KiB.
+type KdfParams struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+}
+
+// Write encodes a database as a KDBX 4 file protected by a master password.
+//
+// The file uses Argon2id, AES-256-CBC and gzip compression, protected values are encrypted with the
+// ChaCha20 inner stream and attachments are stored in the inner header, like KeePassXC does.
+// The payload is streamed to w, so the attachments are never held in memory. If an error is returned,
+// part of the file may have been written already and the file must be discarded.
+//
+// Parameters:
+// - w: the destination of the file.
+// - database: the groups and entries.
+// - password: the master password of the file.
+// - kdf: the Argon2id parameters.
+//
+// Returns:
+// - error: an error if the random source, an attachment or the destination fails.
+func Write(w io.Writer, database Database, password string, kdf KdfParams) error {
+	masterSeed, err := random(32)
+	if err != nil {
+		return err
+	}
+
+	iv, err := random(aes.BlockSize)
+	if err != nil {
+		return err
+	}
+
+	salt, err := random(32)
+	if err != nil {
+		return err
+	}
+
+	streamKey, err := random(64)
+	if err != nil {
+		return err
+	}
+
+	header := outerHeader(masterSeed, iv, salt, kdf)
+
+	composite := sha256.Sum256(sha256Sum([]byte(password)))
+	transformed := argon2.IDKey(composite[:], salt, kdf.Iterations, kdf.Memory, kdf.Parallelism, 32)
+
+	encryptionKey := sha256Sum(masterSeed, transformed)
+	hmacKey := sha512Sum(masterSeed, transformed, []byte{1})
+
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+
+	if _, err := w.Write(sha256Sum(header)); err != nil {
+		return err
+	}
+
+	if _, err := w.Write(blockHMAC(hmacKey, math.MaxUint64, header)); err != nil {
+		return err
+	}
+
+	blocks := &blockWriter{w: w, hmacKey: hmacKey}
+
+	encrypter, err := newCBCWriter(blocks, encryptionKey, iv)
+	if err != nil {
+		return err
+	}
+
+	compressor := gzip.NewWriter(encrypter)
+
+	if err := writeInner(compressor, database, streamKey); err != nil {
+		return err
+	}
+
+	if err := compressor.Close(); err != nil {
+		return err
+	}
+
+	if err := encrypter.Close(); err != nil {
+		return err
+	}
+
+	return blocks.Close()
+}
+
+// outerHeader builds the signatures, version and outer header fields.
+func outerHeader(masterSeed, iv, salt []byte, kdf KdfParams) []byte {
+	header := binary.LittleEndian.AppendUint32(nil, signature1)
+	header = binary.LittleEndian.AppendUint32(header, signature2)
+	header = binary.LittleEndian.AppendUint32(header, version4)
+
+	field := func(id byte, data []byte) {
+		header = append(header, id)
+		header = binary.LittleEndian.AppendUint32(header, uint32(len(data)))
+		header = append(header, data...)
+	}
+
+	var parameters variantDictionary
+	parameters.add(variantByteArray, "$UUID", kdfArgon2id)
+	parameters.add(variantByteArray, "S", salt)
+	parameters.add(variantUInt32, "P", binary.LittleEndian.AppendUint32(nil, uint32(kdf.Parallelism)))
+	parameters.add(variantUInt64, "M", binary.LittleEndian.AppendUint64(nil, uint64(kdf.Memory)*1024))
+	parameters.add(variantUInt64, "I", binary.LittleEndian.AppendUint64(nil, uint64(kdf.Iterations)))
+	parameters.add(variantUInt32, "V", binary.LittleEndian.AppendUint32(nil, 0x13))
+
+	field(headerCipherID, cipherAES256)
+	field(headerCompression, binary.LittleEndian.AppendUint32(nil, 1))
+	field(headerMasterSeed, masterSeed)
+	field(headerIV, iv)
+	field(headerKdf, parameters.bytes())
+	field(headerEnd, []byte("\r\n\r\n"))
+
+	return header
+}
+
+// writeInner writes the inner header with the attachments and the XML document.
+func writeInner(w io.Writer, database Database, streamKey []byte) error {
+	streamHash := sha512Sum(streamKey)
+
+	stream, err := chacha20.NewUnauthenticatedCipher(streamHash[:32], streamHash[32:44])
+	if err != nil {
+		return err
+	}
+
+	document, err := newDocument(database, stream)
+	if err != nil {
+		return err
+	}
+
+	field := func(id byte, size int, data []byte) error {
+		header := binary.LittleEndian.AppendUint32([]byte{id}, uint32(size))
+		_, err := w.Write(append(header, data...))
+
+		return err
+	}
+
+	if err := field(innerStreamID, 4, binary.LittleEndian.AppendUint32(nil, streamChaCha20)); err != nil {
+		return err
+	}
+
+	if err := field(innerStreamKey, len(streamKey), streamKey); err != nil {
+		return err
+	}
+
+	for _, attachment := range document.binaries {
+		// The first byte holds the flags, 0 as the content is not protected in memory.
+		if err := field(innerBinary, int(attachment.Size)+1, []byte{0}); err != nil {
+			return err
+		}
+
+		if err := copyBinary(w, attachment); err != nil {
+			return err
+		}
+	}
+
+	if err := field(innerEnd, 0, nil); err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "\t")
+
+	return encoder.Encode(document.file)
+}
+
+// copyBinary copies the content of an attachment, which must be exactly as long as its size.
+func copyBinary(w io.Writer, attachment Binary) error {
+	content, err := attachment.Open()
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	if _, err := io.CopyN(w, content, attachment.Size); err != nil {
+		return err
+	}
+
+	// Reading to the end also lets the storage check the integrity of the content.
+	extra, err := io.Copy(io.Discard, content)
+	if err != nil {
+		return err
+	}
+
+	if extra > 0 {
+		return ErrBinarySize
+	}
+
+	return nil
+}
+
+// cbcWriter encrypts what is written with AES-256-CBC, Close adds the PKCS#7 padding.
+type cbcWriter struct {
+	w      io.Writer
+	mode   cipher.BlockMode
+	buffer []byte
+}
+
+// newCBCWriter returns a cbcWriter writing the ciphertext to w.
+func newCBCWriter(w io.Writer, key, iv []byte) (*cbcWriter, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cbcWriter{w: w, mode: cipher.NewCBCEncrypter(block, iv)}, nil
+}
+
+// Write encrypts the complete AES blocks and keeps the rest for the next write.
+func (c *cbcWriter) Write(p []byte) (int, error) {
+	c.buffer = append(c.buffer, p...)
+
+	complete := len(c.buffer) - len(c.buffer)%aes.BlockSize
+	if complete == 0 {
+		return len(p), nil
+	}
+
+	c.mode.CryptBlocks(c.buffer[:complete], c.buffer[:complete])
+	if _, err := c.w.Write(c.buffer[:complete]); err != nil {
+		return 0, err
+	}
+
+	c.buffer = append(c.buffer[:0], c.buffer[complete:]...)
+
+	return len(p), nil
+}
+
+// Close pads and encrypts the last block.
+func (c *cbcWriter) Close() error {
+	padding := aes.BlockSize - len(c.buffer)
+	c.buffer = append(c.buffer, bytes.Repeat([]byte{byte(padding)}, padding)...)
+
+	c.mode.CryptBlocks(c.buffer, c.buffer)
+	_, err := c.w.Write(c.buffer)
+
+	return err
+}
+
+// blockWriter splits the encrypted payload into HMAC-protected blocks, Close writes the empty last block.
+type blockWriter struct {
+	w       io.Writer
+	hmacKey []byte
+	index   uint64
+	buffer  []byte
+}
+
+// Write buffers the payload and writes every full block.
+func (b *blockWriter) Write(p []byte) (int, error) {
+	written := len(p)
+
+	for len(p) > 0 {
+		size := min(blockSize-len(b.buffer), len(p))
+		b.buffer = append(b.buffer, p[:size]...)
+		p = p[size:]
+
+		if len(b.buffer) == blockSize {
+			if err := b.flush(); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return written, nil
+}
+
+// Close writes the remaining payload and the empty block that marks the end of the file.
+func (b *blockWriter) Close() error {
+	if len(b.buffer) > 0 {
+		if err := b.flush(); err != nil {
+			return err
+		}
+	}
+
+	return b.flush()
+}
+
+// flush signs and writes the buffered block.
+func (b *blockWriter) flush() error {
+	signed := binary.LittleEndian.AppendUint64(nil, b.index)
+	signed = binary.LittleEndian.AppendUint32(signed, uint32(len(b.buffer)))
+	signed = append(signed, b.buffer...)
+
+	if _, err := b.w.Write(blockHMAC(b.hmacKey, b.index, signed)); err != nil {
+		return err
+	}
+
+	if _, err := b.w.Write(signed[8:]); err != nil {
+		return err
+	}
+
+	b.index++
+	b.buffer = b.buffer[:0]
+
+	return nil
+}
+
+// blockHMAC signs a block of the file, the header being the block math.MaxUint64.
+func blockHMAC(hmacKey []byte, index uint64, data []byte) []byte {
+	mac := hmac.New(sha256.New, sha512Sum(binary.LittleEndian.AppendUint64(nil, index), hmacKey))
+	mac.Write(data)
+
+	return mac.Sum(nil)
+}
+
+// variantDictionary encodes the KDF parameters.
+type variantDictionary struct {
+	items []byte
+}
+
+// add appends a typed value.
+func (d *variantDictionary) add(valueType byte, key string, value []byte) {
+	d.items = append(d.items, valueType)
+	d.items = binary.LittleEndian.AppendUint32(d.items, uint32(len(key)))
+	d.items = append(d.items, key...)
+	d.items = binary.LittleEndian.AppendUint32(d.items, uint32(len(value)))
+	d.items = append(d.items, value...)
+}
+
+// bytes returns the version, the values and the terminator.
+func (d *variantDictionary) bytes() []byte {
+	out := binary.LittleEndian.AppendUint16(nil, 0x0100)
+	out = append(out, d.items...)
+
+	return append(out, 0)
+}
+
+// random returns size random bytes.
+func random(size int) ([]byte, error) {
+	data := make([]byte, size)
+	if _, err := rand.Read(data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// sha256Sum hashes the concatenation of the parts with SHA-256.
+func sha256Sum(parts ...[]byte) []byte {
+	hash := sha256.New()
+	for _, part := range parts {
+		hash.Write(part)
+	}
+
+	return hash.Sum(nil)
+}
+
+// sha512Sum hashes the concatenation of the parts with SHA-512.
+func sha512Sum(parts ...[]byte) []byte {
+	hash := sha512.New()
+	for _, part := range parts {
+		hash.Write(part)
+	}
+
+	return hash.Sum(nil)
+}
+
+// protect encrypts a value with the inner stream, values must be protected in document order.
+func protect(stream *chacha20.Cipher, value string) string {
+	data := []byte(value)
+	stream.XORKeyStream(data, data)
+
+	return base64.StdEncoding.EncodeToString(data)
+}
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"backend/modules/exports/services/kdbx"
+	models2 "backend/modules/passwords/models"
+	"backend/modules/users/models"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// writeKeePass writes a vault as a KeePass KDBX 4 file whose master password is the passphrase.
+//
+// Categories become groups of the root group. Cards, identities and Wi-Fi networks keep their
+// values in named strings, hidden values being protected, and extra URIs use the KP2A_URL keys
+// read by KeePassXC and KeePass2Android.
+func writeKeePass(w io.Writer, vault Vault, passphrase string) error {
+	root := kdbx.Group{Name: "Save My Pass"}
+
+	groups := map[uint]int{}
+	for _, category := range vault.Categories {
+		groups[category.ID] = len(root.Groups)
+		root.Groups = append(root.Groups, kdbx.Group{Name: category.Name})
+	}
+
+	for _, entry := range vault.Entries {
+		converted := toKeePassEntry(entry)
+
+		if entry.CategoryID != nil {
+			if index, ok := groups[*entry.CategoryID]; ok {
+				root.Groups[index].Entries = append(root.Groups[index].Entries, converted)
+				continue
+			}
+		}
+
+		root.Entries = append(root.Entries, converted)
+	}
+
+	return kdbx.Write(w, kdbx.Database{Name: "Save My Pass", Root: root}, passphrase, kdbx.KdfParams{
+		Memory:      models.DefaultKdfMemory,
+		Iterations:  models.DefaultKdfIterations,
+		Parallelism: models.DefaultKdfParallelism,
+	})
+}
+
+// keePassStrings collects the strings of an entry, keys must be unique.
+type keePassStrings struct {
+	values []kdbx.String
+	keys   map[string]bool
+}
+
+// add appends a value, a key already used gets a number.
+func (s *keePassStrings) add(key, value string, protected bool) {
+	unique := key
+	for i := 2; s.keys[unique]; i++ {
+		unique = key + " (" + strconv.Itoa(i) + ")"
+	}
+
+	s.keys[unique] = true
+	s.values = append(s.values, kdbx.String{Key: unique, Value: value, Protected: protected})
+}
+
+// addValue appends a value if it is not empty.
+func (s *keePassStrings) addValue(key, value string, protected bool) {
+	if value != "" {
+		s.add(key, value, protected)
+	}
+}
+
+// toKeePassEntry converts an entry of the export to a KeePass entry.
+func toKeePassEntry(entry Entry) kdbx.Entry {
+	strs := keePassStrings{keys: map[string]bool{}}
+
+	var link, notes string
+	if len(entry.URIs) > 0 {
+		link = entry.URIs[0].URI
+	}
+	if entry.Note != nil {
+		notes = entry.Note.Text
+	}
+
+	strs.add("Title", entry.Name, false)
+	strs.add("UserName", entry.Login, false)
+	strs.add("Password", entry.Password, true)
+	strs.add("URL", link, false)
+	strs.add("Notes", notes, false)
+	strs.addValue("otp", otpURI(entry.Totp, entry.Name, entry.Login), true)
+
+	for i := 1; i < len(entry.URIs); i++ {
+		strs.add("KP2A_URL_"+strconv.Itoa(i), entry.URIs[i].URI, false)
+	}
+
+	if card := entry.Card; card != nil {
+		strs.addValue("Cardholder", card.Cardholder, false)
+		strs.addValue("Brand", card.Brand, false)
+		strs.addValue("Card number", card.Number, true)
+		if card.ExpMonth != "" || card.ExpYear != "" {
+			strs.add("Expiry", card.ExpMonth+"/"+card.ExpYear, false)
+		}
+		strs.addValue("CVV", card.CVV, true)
+	}
+
+	if identity := entry.Identity; identity != nil {
+		strs.addValue("Honorific", identity.Title, false)
+		strs.addValue("First name", identity.FirstName, false)
+		strs.addValue("Middle name", identity.MiddleName, false)
+		strs.addValue("Last name", identity.LastName, false)
+		strs.addValue("Company", identity.Company, false)
+		strs.addValue("Email", identity.Email, false)
+		strs.addValue("Phone", identity.Phone, false)
+		strs.addValue("Address line 1", identity.Address.Line1, false)
+		strs.addValue("Address line 2", identity.Address.Line2, false)
+		strs.addValue("City", identity.Address.City, false)
+		strs.addValue("State", identity.Address.State, false)
+		strs.addValue("Postal code", identity.Address.PostalCode, false)
+		strs.addValue("Country", identity.Address.Country, false)
+		strs.addValue("Passport number", identity.PassportNumber, true)
+		strs.addValue("License number", identity.LicenseNumber, true)
+		strs.addValue("National ID", identity.NationalID, true)
+	}
+
+	if wifi := entry.Wifi; wifi != nil {
+		strs.addValue("SSID", wifi.SSID, false)
+		strs.addValue("Security", wifi.Security, false)
+		strs.addValue("Key", wifi.Key, true)
+		if wifi.Hidden {
+			strs.add("Hidden network", "true", false)
+		}
+	}
+
+	for _, field := range entry.Fields {
+		strs.add(field.Label, field.Value, field.Type == models2.FieldHidden)
+	}
+
+	converted := kdbx.Entry{Strings: strs.values, Tags: entry.Tags}
+
+	names := map[string]bool{}
+	for _, attachment := range entry.Attachments {
+		name := attachment.Name
+		for i := 2; names[name]; i++ {
+			name = strconv.Itoa(i) + "-" + attachment.Name
+		}
+
+		names[name] = true
+		converted.Binaries = append(converted.Binaries, kdbx.Binary{Name: name, Size: attachment.Size, Open: attachment.open})
+	}
+
+	return converted
+}
+
+// otpURI returns the TOTP secret as an otpauth URI, which KeePassXC expects, a bare base32 secret is wrapped.
+func otpURI(secret, name, login string) string {
+	if secret == "" || strings.HasPrefix(strings.ToLower(secret), "otpauth://") {
+		return secret
+	}
+
+	label := name
+	if login != "" {
+		label += ":" + login
+	}
+
+	query := url.Values{"secret": {strings.ReplaceAll(strings.ToUpper(secret), " ", "")}}
+
+	return (&url.URL{Scheme: "otpauth", Host: "totp", Path: "/" + label, RawQuery: query.Encode()}).String()
+}
@@ -0,0 +1,96 @@
+package actions
+
+import (
+	"backend/modules/imports/services"
+	"backend/modules/imports/services/parsers"
+	"backend/modules/users/models"
+	services2 "backend/services"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"io"
+	"net/http"
+)
+
+// MaxImportSize limits the size of an export file.
+const MaxImportSize = 50 << 20
+
+type ImportRequest struct {
+	Format      string `form:"format" binding:"required,oneof=chrome firefox lastpass bitwarden keepass 1password"`
+	DryRun      bool   `form:"dry_run"`
+	SkipInvalid bool   `form:"skip_invalid"`
+}
+
+// ImportPasswords imports the export file of another password manager.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Import passwords
+// @Description Imports the export file sent as the raw request body: a Chrome, Firefox or LastPass CSV file, an unencrypted
+// @Description Bitwarden JSON file, a KeePass 2 XML file or a 1Password 1PUX archive. Folders become categories.
+// @Description Items already in the vault are skipped as duplicates. When an item is invalid nothing is imported unless
+// @Description skip_invalid is set, and dry_run returns the same report without saving anything.
+// @Tags Import
+// @Accept  octet-stream
+// @Produce  json
+// @Security BearerAuth
+// @Param   format  query    string  true  "Export format: chrome, firefox, lastpass, bitwarden, keepass or 1password"
+// @Param   dry_run  query    bool  false  "Preview the import without saving it"
+// @Param   skip_invalid  query    bool  false  "Import the valid items when some are invalid"
+// @Success 200 {object} services.ImportReport
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 413 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /import [post]
+func ImportPasswords(c *gin.Context) {
+	var request ImportRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	data, err := io.ReadAll(io.LimitReader(c.Request.Body, MaxImportSize+1))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if len(data) > MaxImportSize {
+		c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, services2.ErrorResponse{Error: "export file is larger than 50 MB"})
+		return
+	}
+
+	importService, user := getServiceAndUser(c)
+
+	report, err := importService.Import(user.User.ID, data, services.ImportOptions{
+		Format:      request.Format,
+		DryRun:      request.DryRun,
+		SkipInvalid: request.SkipInvalid,
+	})
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// errorStatus maps a service error to the HTTP status code of the response.
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrUnknownFormat), errors.Is(err, services.ErrZeroKnowledge),
+		errors.Is(err, parsers.ErrInvalidFile):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// getServiceAndUser returns the import service and user token.
+//
+// It takes a Gin context as a parameter.
+// It returns an ImportService and a Token.
+func getServiceAndUser(c *gin.Context) (services.ImportService, models.Token) {
+	service := services.ImportService{DB: services2.GetDBConnection()}
+	user := services2.GetUserFromContext(c)
+
+	return service, user
+}
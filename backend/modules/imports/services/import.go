@@ -0,0 +1,263 @@
+package services
+
+import (
+	models2 "backend/modules/categories/models"
+	"backend/modules/imports/services/parsers"
+	"backend/modules/passwords/services"
+	"backend/modules/users/models"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"strings"
+)
+
+const (
+	StatusCreated   = "created"
+	StatusDuplicate = "duplicate"
+	StatusFailed    = "failed"
+)
+
+var (
+	ErrUnknownFormat = errors.New("unknown import format")
+	ErrZeroKnowledge = errors.New("imports are not available in the zero-knowledge vault mode, import on the client")
+
+	// errRollback ends the import transaction without saving anything.
+	errRollback = errors.New("import rolled back")
+)
+
+// invalidEntry lists the errors of entries rejected by the password service, other errors abort the import.
+var invalidEntry = []error{
+	services.ErrInvalidItem,
+	services.ErrInvalidField,
+	services.ErrInvalidURI,
+	services.ErrInvalidTotp,
+}
+
+type ImportService struct {
+	DB *gorm.DB
+}
+
+type ImportOptions struct {
+	Format string
+	// DryRun runs the whole import and rolls it back, so the report is a preview.
+	DryRun bool
+	// SkipInvalid imports the valid entries when some fail, otherwise nothing is imported.
+	SkipInvalid bool
+}
+
+type ImportRow struct {
+	Row    int    `json:"row"`
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Folder string `json:"folder"`
+	// Status is created, duplicate or failed.
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	// ID is the ID of the created password, 0 in a dry run.
+	ID uint `json:"id,omitempty"`
+}
+
+type ImportReport struct {
+	Format string `json:"format"`
+	DryRun bool   `json:"dry_run"`
+	// Committed tells whether the entries were saved, false for dry runs and failed imports.
+	Committed  bool        `json:"committed"`
+	Total      int         `json:"total"`
+	Created    int         `json:"created"`
+	Duplicates int         `json:"duplicates"`
+	Failed     int         `json:"failed"`
+	Categories []string    `json:"categories"`
+	Rows       []ImportRow `json:"rows"`
+}
+
+// Import imports the export file of another password manager for a given user.
+//
+// Folders become categories, reusing the categories of the user with the same name, and items become passwords.
+// Items with the same content as an existing password or an earlier item of the file are reported as duplicates
+// and skipped. Everything runs in one transaction: when an item is invalid nothing is saved unless SkipInvalid
+// is set, and a dry run is rolled back at the end.
+//
+// Parameters:
+// - userId: the ID of the user.
+// - data: the content of the export file.
+// - options: the format of the file and the import options.
+//
+// Returns:
+// - ImportReport: the status of each item.
+// - error: ErrUnknownFormat, an error wrapping parsers.ErrInvalidFile, ErrZeroKnowledge or any database error.
+func (s *ImportService) Import(userId uint, data []byte, options ImportOptions) (ImportReport, error) {
+	parser, ok := parsers.Get(options.Format)
+	if !ok {
+		return ImportReport{}, fmt.Errorf("%w %q, expected one of %s", ErrUnknownFormat, options.Format, strings.Join(parsers.Formats, ", "))
+	}
+
+	var user models.User
+	if err := s.DB.Select("id", "vault_mode").Where("id = ?", userId).First(&user).Error; err != nil {
+		return ImportReport{}, err
+	}
+
+	if user.VaultMode == models.VaultModeZeroKnowledge {
+		return ImportReport{}, ErrZeroKnowledge
+	}
+
+	result, err := parser.Parse(data)
+	if err != nil {
+		return ImportReport{}, err
+	}
+
+	passwordService := services.PasswordService{DB: s.DB}
+
+	keys, err := passwordService.GetDuplicateKeys(userId)
+	if err != nil {
+		return ImportReport{}, err
+	}
+
+	report := ImportReport{
+		Format:     options.Format,
+		DryRun:     options.DryRun,
+		Total:      len(result.Entries) + len(result.Errors),
+		Categories: []string{},
+		Rows:       []ImportRow{},
+	}
+
+	for _, rowError := range result.Errors {
+		report.Failed++
+		report.Rows = append(report.Rows, ImportRow{Row: rowError.Row, Name: rowError.Name, Status: StatusFailed, Error: rowError.Error})
+	}
+
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		categories, err := s.getCategories(tx, userId)
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range result.Entries {
+			row := ImportRow{Row: entry.Row, Name: entry.Data.Name, Type: entry.Data.Type, Folder: entry.Folder}
+
+			key, err := services.DuplicateKey(entry.Data)
+			if err != nil {
+				return err
+			}
+
+			if keys[key] {
+				row.Status = StatusDuplicate
+				report.Duplicates++
+				report.Rows = append(report.Rows, row)
+				continue
+			}
+
+			id, created, err := s.importEntry(tx, userId, entry, categories)
+			if err != nil && !isInvalid(err) {
+				return err
+			}
+
+			if err != nil {
+				row.Status, row.Error = StatusFailed, err.Error()
+				report.Failed++
+				report.Rows = append(report.Rows, row)
+				continue
+			}
+
+			keys[key] = true
+			if created != "" {
+				report.Categories = append(report.Categories, created)
+			}
+
+			row.Status = StatusCreated
+			if !options.DryRun {
+				row.ID = id
+			}
+			report.Created++
+			report.Rows = append(report.Rows, row)
+		}
+
+		if options.DryRun || (report.Failed > 0 && !options.SkipInvalid) {
+			return errRollback
+		}
+
+		return nil
+	})
+	if err != nil && !errors.Is(err, errRollback) {
+		return ImportReport{}, err
+	}
+
+	report.Committed = err == nil
+
+	return report, nil
+}
+
+// importEntry saves an entry and creates its category if needed, in a savepoint so a rejected entry leaves no trace.
+//
+// It returns the ID of the password, the name of the created category if any, and the error of the password service.
+func (s *ImportService) importEntry(tx *gorm.DB, userId uint, entry parsers.Entry, categories map[string]uint) (uint, string, error) {
+	var id uint
+	var created string
+
+	err := tx.Transaction(func(tx *gorm.DB) error {
+		folder := strings.TrimSpace(entry.Folder)
+
+		if folder != "" {
+			categoryId, ok := categories[strings.ToLower(folder)]
+			if !ok {
+				categoryModel := models2.CategoryModel{DB: tx}
+
+				category, err := categoryModel.Create(userId, folder, nil)
+				if err != nil {
+					return err
+				}
+
+				categoryId, created = category.ID, category.Name
+			}
+
+			entry.Data.CategoryID = &categoryId
+		}
+
+		passwordService := services.PasswordService{DB: tx}
+
+		password, err := passwordService.CreatePassword(userId, entry.Data)
+		if err != nil {
+			return err
+		}
+
+		id = password.ID
+
+		return nil
+	})
+	if err != nil {
+		return 0, "", err
+	}
+
+	if created != "" {
+		categories[strings.ToLower(created)] = *entry.Data.CategoryID
+	}
+
+	return id, created, nil
+}
+
+// getCategories returns the IDs of the categories of a user by lowercase name.
+func (s *ImportService) getCategories(tx *gorm.DB, userId uint) (map[string]uint, error) {
+	categoryModel := models2.CategoryModel{DB: tx}
+
+	categories, err := categoryModel.GetAll(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := map[string]uint{}
+	for _, category := range categories {
+		ids[strings.ToLower(category.Name)] = category.ID
+	}
+
+	return ids, nil
+}
+
+// isInvalid reports whether an error of the password service rejects an entry.
+func isInvalid(err error) bool {
+	for _, target := range invalidEntry {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
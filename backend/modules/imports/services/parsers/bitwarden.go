@@ -0,0 +1,195 @@
+package parsers
+
+import (
+	"backend/modules/passwords/models"
+	"backend/modules/passwords/services"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Bitwarden item types.
+const (
+	bitwardenLogin    = 1
+	bitwardenNote     = 2
+	bitwardenCard     = 3
+	bitwardenIdentity = 4
+)
+
+// bitwardenMatches maps the URI match detection of Bitwarden to a match strategy.
+// Exact matches become starts_with matches, the closest strategy.
+var bitwardenMatches = map[int]string{
+	0: models.MatchDomain,
+	1: models.MatchHost,
+	2: models.MatchStartsWith,
+	3: models.MatchStartsWith,
+	4: models.MatchRegex,
+	5: models.MatchNever,
+}
+
+// bitwardenParser reads the unencrypted JSON export of Bitwarden.
+type bitwardenParser struct{}
+
+type bitwardenExport struct {
+	Encrypted bool `json:"encrypted"`
+	Folders   []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"folders"`
+	Items []bitwardenItem `json:"items"`
+}
+
+type bitwardenItem struct {
+	Type     int     `json:"type"`
+	Name     string  `json:"name"`
+	Notes    string  `json:"notes"`
+	FolderID *string `json:"folderId"`
+	Login    *struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+		Totp     string `json:"totp"`
+		URIs     []struct {
+			URI   string `json:"uri"`
+			Match *int   `json:"match"`
+		} `json:"uris"`
+	} `json:"login"`
+	Card *struct {
+		CardholderName string `json:"cardholderName"`
+		Brand          string `json:"brand"`
+		Number         string `json:"number"`
+		ExpMonth       string `json:"expMonth"`
+		ExpYear        string `json:"expYear"`
+		Code           string `json:"code"`
+	} `json:"card"`
+	Identity *struct {
+		Title          string `json:"title"`
+		FirstName      string `json:"firstName"`
+		MiddleName     string `json:"middleName"`
+		LastName       string `json:"lastName"`
+		Address1       string `json:"address1"`
+		Address2       string `json:"address2"`
+		City           string `json:"city"`
+		State          string `json:"state"`
+		PostalCode     string `json:"postalCode"`
+		Country        string `json:"country"`
+		Company        string `json:"company"`
+		Email          string `json:"email"`
+		Phone          string `json:"phone"`
+		SSN            string `json:"ssn"`
+		PassportNumber string `json:"passportNumber"`
+		LicenseNumber  string `json:"licenseNumber"`
+	} `json:"identity"`
+	Fields []struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+		Type  int    `json:"type"`
+	} `json:"fields"`
+}
+
+// Parse reads a Bitwarden JSON export, password protected and account encrypted exports are rejected.
+func (bitwardenParser) Parse(data []byte) (Result, error) {
+	var export bitwardenExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return Result{}, fmt.Errorf("%w: %s", ErrInvalidFile, err)
+	}
+
+	if export.Encrypted {
+		return Result{}, fmt.Errorf("%w: encrypted Bitwarden exports are not supported, export as unencrypted JSON", ErrInvalidFile)
+	}
+
+	folders := map[string]string{}
+	for _, folder := range export.Folders {
+		folders[folder.ID] = folder.Name
+	}
+
+	var result Result
+
+	for i, item := range export.Items {
+		entry := Entry{Row: i + 1}
+		if item.FolderID != nil {
+			entry.Folder = folders[*item.FolderID]
+		}
+
+		switch {
+		case item.Type == bitwardenLogin && item.Login != nil:
+			entry.Data = login(item.Name, item.Login.Username, item.Login.Password, item.Login.Totp, item.Notes)
+
+			for _, uri := range item.Login.URIs {
+				if uri.URI == "" {
+					continue
+				}
+
+				match := models.MatchDomain
+				if uri.Match != nil {
+					match = bitwardenMatches[*uri.Match]
+				}
+				if match == "" {
+					match = models.MatchDomain
+				}
+
+				entry.Data.URIs = append(entry.Data.URIs, services.URI{URI: uri.URI, Match: match})
+			}
+		case item.Type == bitwardenNote:
+			entry.Data = note(item.Name, item.Notes)
+		case item.Type == bitwardenCard && item.Card != nil:
+			entry.Data = services.PasswordData{
+				Item: services.Item{Type: models.TypeCard, Card: &services.Card{
+					Cardholder: item.Card.CardholderName,
+					Brand:      item.Card.Brand,
+					Number:     item.Card.Number,
+					ExpMonth:   item.Card.ExpMonth,
+					ExpYear:    item.Card.ExpYear,
+					CVV:        item.Card.Code,
+				}},
+				Name: fallbackName(item.Name),
+			}
+			addNotes(&entry.Data, item.Notes)
+		case item.Type == bitwardenIdentity && item.Identity != nil:
+			identity := item.Identity
+			entry.Data = services.PasswordData{
+				Item: services.Item{Type: models.TypeIdentity, Identity: &services.Identity{
+					Title:      identity.Title,
+					FirstName:  identity.FirstName,
+					MiddleName: identity.MiddleName,
+					LastName:   identity.LastName,
+					Company:    identity.Company,
+					Email:      identity.Email,
+					Phone:      identity.Phone,
+					Address: services.Address{
+						Line1:      identity.Address1,
+						Line2:      identity.Address2,
+						City:       identity.City,
+						State:      identity.State,
+						PostalCode: identity.PostalCode,
+						Country:    identity.Country,
+					},
+					PassportNumber: identity.PassportNumber,
+					LicenseNumber:  identity.LicenseNumber,
+					NationalID:     identity.SSN,
+				}},
+				Name: fallbackName(item.Name),
+			}
+			addNotes(&entry.Data, item.Notes)
+		default:
+			result.Errors = append(result.Errors, RowError{
+				Row:   entry.Row,
+				Name:  item.Name,
+				Error: "unsupported item type " + strconv.Itoa(item.Type),
+			})
+			continue
+		}
+
+		for _, field := range item.Fields {
+			// Bitwarden field types: 0 text, 1 hidden, 2 boolean, 3 linked.
+			if field.Type == 3 {
+				continue
+			}
+
+			addField(&entry.Data, field.Name, field.Value, field.Type == 1)
+		}
+
+		result.Entries = append(result.Entries, entry)
+	}
+
+	return result, nil
+}
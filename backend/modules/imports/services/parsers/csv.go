@@ -0,0 +1,114 @@
+package parsers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// chromeParser reads the CSV export of Chrome and other Chromium browsers:
+// name,url,username,password and, in recent versions, note.
+type chromeParser struct{}
+
+// firefoxParser reads the CSV export of Firefox:
+// url,username,password,httpRealm,formActionOrigin,guid and timestamps.
+type firefoxParser struct{}
+
+// lastPassParser reads the CSV export of LastPass: url,username,password,totp,extra,name,grouping,fav.
+//
+// Secure notes have the http://sn URL and their text in the extra column.
+type lastPassParser struct{}
+
+// Parse reads a Chrome CSV export.
+func (chromeParser) Parse(data []byte) (Result, error) {
+	return parseCSV(data, []string{"url", "username", "password"}, func(row csvRow) Entry {
+		return Entry{Data: login(row.get("name"), row.get("username"), row.get("password"), "", row.get("note"), row.get("url"))}
+	})
+}
+
+// Parse reads a Firefox CSV export.
+func (firefoxParser) Parse(data []byte) (Result, error) {
+	return parseCSV(data, []string{"url", "username", "password"}, func(row csvRow) Entry {
+		return Entry{Data: login("", row.get("username"), row.get("password"), "", "", row.get("url"))}
+	})
+}
+
+// Parse reads a LastPass CSV export.
+func (lastPassParser) Parse(data []byte) (Result, error) {
+	return parseCSV(data, []string{"url", "username", "password", "extra", "name", "grouping"}, func(row csvRow) Entry {
+		entry := Entry{Folder: strings.ReplaceAll(row.get("grouping"), "\\", "/")}
+
+		if row.get("url") == "http://sn" {
+			entry.Data = note(row.get("name"), row.get("extra"))
+		} else {
+			entry.Data = login(row.get("name"), row.get("username"), row.get("password"), row.get("totp"), row.get("extra"), row.get("url"))
+		}
+
+		return entry
+	})
+}
+
+// csvRow is a record of a CSV file with a header.
+type csvRow struct {
+	columns map[string]int
+	record  []string
+}
+
+// get returns the value of a column, empty when the file does not have it.
+func (r csvRow) get(column string) string {
+	index, ok := r.columns[column]
+	if !ok || index >= len(r.record) {
+		return ""
+	}
+
+	return r.record[index]
+}
+
+// parseCSV reads a CSV file whose header has the required columns and converts each record to an entry.
+func parseCSV(data []byte, required []string, convert func(row csvRow) Entry) (Result, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\ufeff"))))
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return Result{}, fmt.Errorf("%w: %s", ErrInvalidFile, err)
+	}
+
+	columns := map[string]int{}
+	for i, column := range header {
+		columns[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+
+	for _, column := range required {
+		if _, ok := columns[column]; !ok {
+			return Result{}, fmt.Errorf("%w: missing %s column", ErrInvalidFile, column)
+		}
+	}
+
+	var result Result
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			result.Errors = append(result.Errors, RowError{Row: parseErr.StartLine, Error: parseErr.Err.Error()})
+			continue
+		}
+		if err != nil {
+			return Result{}, fmt.Errorf("%w: %s", ErrInvalidFile, err)
+		}
+
+		entry := convert(csvRow{columns: columns, record: record})
+		entry.Row, _ = reader.FieldPos(0)
+		result.Entries = append(result.Entries, entry)
+	}
+
+	return result, nil
+}
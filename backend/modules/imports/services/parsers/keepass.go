@@ -0,0 +1,107 @@
+package parsers
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// keePassParser reads the KeePass 2 XML export, also written by KeePassXC.
+//
+// Groups become folders, the root group excluded, and the recycle bin and entry histories are skipped.
+type keePassParser struct{}
+
+type keePassFile struct {
+	Meta struct {
+		RecycleBinUUID string `xml:"RecycleBinUUID"`
+	} `xml:"Meta"`
+	Root struct {
+		Groups []keePassGroup `xml:"Group"`
+	} `xml:"Root"`
+}
+
+type keePassGroup struct {
+	UUID    string         `xml:"UUID"`
+	Name    string         `xml:"Name"`
+	Entries []keePassEntry `xml:"Entry"`
+	Groups  []keePassGroup `xml:"Group"`
+}
+
+type keePassEntry struct {
+	Strings []struct {
+		Key   string `xml:"Key"`
+		Value struct {
+			Text            string `xml:",chardata"`
+			ProtectInMemory string `xml:"ProtectInMemory,attr"`
+		} `xml:"Value"`
+	} `xml:"String"`
+}
+
+// Parse reads a KeePass 2 XML export.
+func (keePassParser) Parse(data []byte) (Result, error) {
+	var file keePassFile
+
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+
+	if err := decoder.Decode(&file); err != nil {
+		return Result{}, fmt.Errorf("%w: %s", ErrInvalidFile, err)
+	}
+
+	if len(file.Root.Groups) == 0 {
+		return Result{}, fmt.Errorf("%w: no KeePass root group", ErrInvalidFile)
+	}
+
+	var result Result
+
+	for _, root := range file.Root.Groups {
+		readKeePassGroup(&result, root, "", file.Meta.RecycleBinUUID)
+	}
+
+	return result, nil
+}
+
+// readKeePassGroup adds the entries of a group and its subgroups to the result.
+func readKeePassGroup(result *Result, group keePassGroup, folder, recycleBin string) {
+	for _, entry := range group.Entries {
+		values := map[string]string{}
+		protected := map[string]bool{}
+		var extra []string
+
+		for _, field := range entry.Strings {
+			values[field.Key] = field.Value.Text
+			protected[field.Key] = strings.EqualFold(field.Value.ProtectInMemory, "true")
+
+			switch field.Key {
+			case "Title", "UserName", "Password", "URL", "Notes", "otp":
+			default:
+				extra = append(extra, field.Key)
+			}
+		}
+
+		data := login(values["Title"], values["UserName"], values["Password"], values["otp"], values["Notes"], values["URL"])
+		for _, key := range extra {
+			addField(&data, key, values[key], protected[key])
+		}
+
+		result.Entries = append(result.Entries, Entry{
+			Row:    len(result.Entries) + len(result.Errors) + 1,
+			Folder: folder,
+			Data:   data,
+		})
+	}
+
+	for _, subgroup := range group.Groups {
+		if recycleBin != "" && subgroup.UUID == recycleBin {
+			continue
+		}
+
+		path := subgroup.Name
+		if folder != "" {
+			path = folder + "/" + subgroup.Name
+		}
+
+		readKeePassGroup(result, subgroup, path, recycleBin)
+	}
+}
@@ -0,0 +1,275 @@
+package parsers
+
+import (
+	"archive/zip"
+	"backend/modules/passwords/models"
+	"backend/modules/passwords/services"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// maxExportData limits the size of the decompressed export.data file of a 1PUX archive.
+const maxExportData = 100 << 20
+
+// 1Password item categories.
+const (
+	onePasswordLogin    = "001"
+	onePasswordCard     = "002"
+	onePasswordNote     = "003"
+	onePasswordIdentity = "004"
+	onePasswordPassword = "005"
+	onePasswordWifi     = "109"
+	onePasswordAPI      = "112"
+)
+
+// onePasswordParser reads the 1PUX export of 1Password, a zip archive with an export.data JSON file.
+//
+// Vaults become folders and archived items are skipped.
+type onePasswordParser struct{}
+
+type onePasswordExport struct {
+	Accounts []struct {
+		Vaults []struct {
+			Attrs struct {
+				Name string `json:"name"`
+			} `json:"attrs"`
+			Items []onePasswordItem `json:"items"`
+		} `json:"vaults"`
+	} `json:"accounts"`
+}
+
+type onePasswordItem struct {
+	State        string `json:"state"`
+	CategoryUUID string `json:"categoryUuid"`
+	Overview     struct {
+		Title string `json:"title"`
+		URL   string `json:"url"`
+		URLs  []struct {
+			URL string `json:"url"`
+		} `json:"urls"`
+	} `json:"overview"`
+	Details struct {
+		LoginFields []struct {
+			Value       string `json:"value"`
+			Designation string `json:"designation"`
+		} `json:"loginFields"`
+		NotesPlain string `json:"notesPlain"`
+		Password   string `json:"password"`
+		Sections   []struct {
+			Title  string `json:"title"`
+			Fields []struct {
+				Title string                     `json:"title"`
+				ID    string                     `json:"id"`
+				Value map[string]json.RawMessage `json:"value"`
+			} `json:"fields"`
+		} `json:"sections"`
+	} `json:"details"`
+}
+
+// Parse reads a 1Password 1PUX export.
+func (onePasswordParser) Parse(data []byte) (Result, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return Result{}, fmt.Errorf("%w: %s", ErrInvalidFile, err)
+	}
+
+	file, err := archive.Open("export.data")
+	if err != nil {
+		return Result{}, fmt.Errorf("%w: missing export.data", ErrInvalidFile)
+	}
+	defer file.Close()
+
+	var export onePasswordExport
+	if err := json.NewDecoder(io.LimitReader(file, maxExportData)).Decode(&export); err != nil {
+		return Result{}, fmt.Errorf("%w: %s", ErrInvalidFile, err)
+	}
+
+	var result Result
+	row := 0
+
+	for _, account := range export.Accounts {
+		for _, vault := range account.Vaults {
+			for _, item := range vault.Items {
+				row++
+
+				if item.State == "archived" {
+					continue
+				}
+
+				entry, err := onePasswordEntry(item)
+				if err != nil {
+					result.Errors = append(result.Errors, RowError{Row: row, Name: item.Overview.Title, Error: err.Error()})
+					continue
+				}
+
+				entry.Row = row
+				entry.Folder = vault.Attrs.Name
+				result.Entries = append(result.Entries, entry)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// onePasswordEntry converts a 1Password item to an entry.
+//
+// Logins and passwords keep their section fields as custom fields, credit cards and identities
+// are mapped by field ID and secure notes keep their text.
+func onePasswordEntry(item onePasswordItem) (Entry, error) {
+	title := item.Overview.Title
+	fields := onePasswordFields(item)
+
+	switch item.CategoryUUID {
+	case onePasswordLogin, onePasswordPassword, onePasswordAPI:
+		var username, password string
+		for _, field := range item.Details.LoginFields {
+			switch field.Designation {
+			case "username":
+				username = field.Value
+			case "password":
+				password = field.Value
+			}
+		}
+		if password == "" {
+			password = item.Details.Password
+		}
+
+		urls := []string{item.Overview.URL}
+		for _, link := range item.Overview.URLs {
+			if link.URL != item.Overview.URL {
+				urls = append(urls, link.URL)
+			}
+		}
+
+		data := login(title, username, password, fields.totp, item.Details.NotesPlain, urls...)
+		fields.addTo(&data)
+
+		return Entry{Data: data}, nil
+	case onePasswordNote:
+		return Entry{Data: note(title, item.Details.NotesPlain)}, nil
+	case onePasswordCard:
+		data := services.PasswordData{
+			Item: services.Item{Type: models.TypeCard, Card: &services.Card{
+				Cardholder: fields.take("cardholder"),
+				Brand:      fields.take("type"),
+				Number:     fields.take("ccnum"),
+				CVV:        fields.take("cvv"),
+			}},
+			Name: fallbackName(title),
+		}
+
+		if expiry := fields.take("expiry"); len(expiry) == 6 {
+			data.Card.ExpYear, data.Card.ExpMonth = expiry[:4], expiry[4:]
+		}
+
+		addNotes(&data, item.Details.NotesPlain)
+		fields.addTo(&data)
+
+		return Entry{Data: data}, nil
+	case onePasswordIdentity:
+		data := services.PasswordData{
+			Item: services.Item{Type: models.TypeIdentity, Identity: &services.Identity{
+				FirstName:  fields.take("firstname"),
+				MiddleName: fields.take("initial"),
+				LastName:   fields.take("lastname"),
+				Company:    fields.take("company"),
+				Email:      fields.take("email"),
+				Phone:      fields.take("defphone"),
+			}},
+			Name: fallbackName(title),
+		}
+
+		addNotes(&data, item.Details.NotesPlain)
+		fields.addTo(&data)
+
+		return Entry{Data: data}, nil
+	case onePasswordWifi:
+		data := services.PasswordData{
+			Item: services.Item{Type: models.TypeWifi, Wifi: &services.Wifi{
+				SSID:     fields.take("network_name"),
+				Security: services.WifiWPA2,
+				Key:      fields.take("wireless_password"),
+			}},
+			Name: fallbackName(title),
+		}
+
+		if data.Wifi.Key == "" {
+			data.Wifi.Security = services.WifiOpen
+		}
+
+		addNotes(&data, item.Details.NotesPlain)
+		fields.addTo(&data)
+
+		return Entry{Data: data}, nil
+	}
+
+	return Entry{}, fmt.Errorf("unsupported item category %s", item.CategoryUUID)
+}
+
+// onePasswordValues holds the section fields of a 1Password item in their order.
+type onePasswordValues struct {
+	ids    []string
+	labels map[string]string
+	values map[string]string
+	hidden map[string]bool
+	totp   string
+}
+
+// onePasswordFields reads the section fields of an item, the first one-time password is kept apart.
+//
+// Field values are typed objects such as {"string": "..."} or {"concealed": "..."}, dates and
+// month-years are numbers and addresses are objects, which are kept as JSON text.
+func onePasswordFields(item onePasswordItem) onePasswordValues {
+	fields := onePasswordValues{labels: map[string]string{}, values: map[string]string{}, hidden: map[string]bool{}}
+
+	for _, section := range item.Details.Sections {
+		for i, field := range section.Fields {
+			for kind, raw := range field.Value {
+				value := string(raw)
+
+				var text string
+				if err := json.Unmarshal(raw, &text); err == nil {
+					value = text
+				}
+
+				if kind == "totp" && fields.totp == "" {
+					fields.totp = value
+					continue
+				}
+
+				id := field.ID
+				if _, exists := fields.values[id]; id == "" || exists {
+					id = section.Title + "." + strconv.Itoa(i)
+				}
+
+				fields.ids = append(fields.ids, id)
+				fields.labels[id] = field.Title
+				fields.values[id] = value
+				fields.hidden[id] = kind == "concealed" || kind == "creditCardNumber"
+			}
+		}
+	}
+
+	return fields
+}
+
+// take returns the value of a field and removes it from the custom fields.
+func (f *onePasswordValues) take(id string) string {
+	value := f.values[id]
+	delete(f.values, id)
+
+	return value
+}
+
+// addTo keeps the remaining fields in custom fields.
+func (f *onePasswordValues) addTo(data *services.PasswordData) {
+	for _, id := range f.ids {
+		if value, ok := f.values[id]; ok {
+			addField(data, f.labels[id], value, f.hidden[id])
+		}
+	}
+}
@@ -0,0 +1,156 @@
+// Package parsers reads the export files of other password managers.
+package parsers
+
+import (
+	"backend/modules/passwords/models"
+	"backend/modules/passwords/services"
+	"errors"
+	"net/url"
+	"strings"
+)
+
+var ErrInvalidFile = errors.New("invalid import file")
+
+// Entry is an item read from an export file.
+type Entry struct {
+	// Row is the line of a CSV file or the position of the item in other formats, starting at 1.
+	Row int
+	// Folder is the folder path of the item, empty when it has none.
+	Folder string
+	Data   services.PasswordData
+}
+
+// RowError is an item of an export file that could not be read.
+type RowError struct {
+	Row   int
+	Name  string
+	Error string
+}
+
+type Result struct {
+	Entries []Entry
+	Errors  []RowError
+}
+
+// Parser reads an export file.
+//
+// Parse returns an error wrapping ErrInvalidFile when the file cannot be read at all,
+// items that cannot be read are reported in Result.Errors instead.
+type Parser interface {
+	Parse(data []byte) (Result, error)
+}
+
+var parsers = map[string]Parser{
+	"chrome":    chromeParser{},
+	"firefox":   firefoxParser{},
+	"lastpass":  lastPassParser{},
+	"bitwarden": bitwardenParser{},
+	"keepass":   keePassParser{},
+	"1password": onePasswordParser{},
+}
+
+// Formats lists the supported export formats.
+var Formats = []string{"chrome", "firefox", "lastpass", "bitwarden", "keepass", "1password"}
+
+// Get returns the parser of an export format.
+func Get(format string) (Parser, bool) {
+	parser, ok := parsers[format]
+
+	return parser, ok
+}
+
+// login builds the service input of a login.
+//
+// The name falls back to the host of the first URL, URLs that have no host are kept in a text field,
+// and notes are kept in a text field as well.
+func login(name, username, password, totp, notes string, urls ...string) services.PasswordData {
+	data := services.PasswordData{
+		Item:     services.Item{Type: models.TypeLogin},
+		Name:     strings.TrimSpace(name),
+		Login:    username,
+		Password: password,
+		Totp:     strings.TrimSpace(totp),
+	}
+
+	for _, raw := range urls {
+		raw = strings.TrimSpace(raw)
+		if raw == "" || raw == "http://" {
+			continue
+		}
+
+		if host := uriHost(raw); host != "" {
+			data.URIs = append(data.URIs, services.URI{URI: raw, Match: models.MatchDomain})
+			if data.Name == "" {
+				data.Name = host
+			}
+		} else {
+			data.Fields = append(data.Fields, services.Field{Label: "URL", Type: models.FieldText, Value: raw})
+		}
+	}
+
+	if data.Name == "" {
+		data.Name = fallbackName(username)
+	}
+
+	addNotes(&data, notes)
+
+	return data
+}
+
+// note builds the service input of a secure note.
+func note(name, text string) services.PasswordData {
+	return services.PasswordData{
+		Item: services.Item{Type: models.TypeNote, Note: &services.Note{Text: text}},
+		Name: fallbackName(strings.TrimSpace(name)),
+	}
+}
+
+// addNotes keeps the notes of an item in a text field.
+func addNotes(data *services.PasswordData, notes string) {
+	if strings.TrimSpace(notes) == "" {
+		return
+	}
+
+	data.Fields = append(data.Fields, services.Field{Label: "Notes", Type: models.FieldText, Value: notes})
+}
+
+// addField keeps an extra value of an item in a custom field, empty values are skipped.
+func addField(data *services.PasswordData, label, value string, hidden bool) {
+	if value == "" {
+		return
+	}
+
+	if label == "" {
+		label = "Field"
+	}
+
+	fieldType := models.FieldText
+	if hidden {
+		fieldType = models.FieldHidden
+	}
+
+	data.Fields = append(data.Fields, services.Field{Label: label, Type: fieldType, Value: value})
+}
+
+// fallbackName returns the name of an item without a title.
+func fallbackName(name string) string {
+	if name == "" {
+		return "Untitled"
+	}
+
+	return name
+}
+
+// uriHost returns the host of a URL, the https scheme is assumed when it has none.
+func uriHost(raw string) string {
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+
+	return parsed.Hostname()
+}
@@ -0,0 +1,132 @@
+package actions
+
+import (
+	"backend/modules/notifications/services"
+	"backend/modules/users/models"
+	services2 "backend/services"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"net/http"
+)
+
+type GetNotificationsRequest struct {
+	Unread bool `form:"unread"`
+}
+
+type NotificationRequestAndResponse struct {
+	ID uint `json:"id" uri:"id" binding:"required"`
+}
+
+type ReadAllNotificationsResponse struct {
+	Count int64 `json:"count"`
+}
+
+// GetNotifications retrieves the notifications of the logged-in user.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Get notifications
+// @Description Retrieves the notifications of the logged-in user, newest first, such as password rotation reminders
+// @Tags Notifications
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   unread  query    bool  false  "Only unread notifications"
+// @Success 200 {array} services.Notification
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /notification/all [get]
+func GetNotifications(c *gin.Context) {
+	var request GetNotificationsRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	notificationService, user := getServiceAndUser(c)
+
+	notifications, err := notificationService.GetNotifications(user.User.ID, request.Unread)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, notifications)
+}
+
+// ReadNotification marks a notification as read.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Read notification
+// @Description Marks the notification with the given ID as read
+// @Tags Notifications
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Notification ID"
+// @Success 200 {object} NotificationRequestAndResponse
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /notification/read/{id} [post]
+func ReadNotification(c *gin.Context) {
+	var request NotificationRequestAndResponse
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	notificationService, user := getServiceAndUser(c)
+
+	if err := notificationService.MarkRead(request.ID, user.User.ID); err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, NotificationRequestAndResponse{ID: request.ID})
+}
+
+// ReadAllNotifications marks all notifications as read.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Read all notifications
+// @Description Marks all unread notifications of the logged-in user as read
+// @Tags Notifications
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Success 200 {object} ReadAllNotificationsResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /notification/read-all [post]
+func ReadAllNotifications(c *gin.Context) {
+	notificationService, user := getServiceAndUser(c)
+
+	count, err := notificationService.MarkAllRead(user.User.ID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, ReadAllNotificationsResponse{Count: count})
+}
+
+// errorStatus maps a service error to the HTTP status code of the response.
+func errorStatus(err error) int {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return http.StatusNotFound
+	}
+
+	return http.StatusInternalServerError
+}
+
+// getServiceAndUser returns the notification service and user token.
+//
+// It takes a Gin context as a parameter.
+// It returns a NotificationService and a Token.
+func getServiceAndUser(c *gin.Context) (services.NotificationService, models.Token) {
+	service := services.NotificationService{DB: services2.GetDBConnection()}
+	user := services2.GetUserFromContext(c)
+
+	return service, user
+}
@@ -0,0 +1,113 @@
+package models
+
+import (
+	models2 "backend/modules/passwords/models"
+	"backend/modules/users/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+const (
+	TypeRotationDue        = "rotation_due"
+	TypeOrganizationInvite = "organization_invite"
+	TypeEmergencyAccess    = "emergency_access"
+)
+
+type Notification struct {
+	gorm.Model
+	UserID     uint             `gorm:"not null;index"`
+	User       models.User      `gorm:"foreignKey:UserID"`
+	PasswordID *uint            `gorm:"nullable;uniqueIndex:idx_notifications_reminder"`
+	Password   models2.Password `gorm:"foreignKey:PasswordID;constraint:OnDelete:CASCADE"`
+	Type       string           `gorm:"not null;uniqueIndex:idx_notifications_reminder"`
+	Message    string           `gorm:"not null"`
+	// DueAt is the due date the notification reminds of, a password gets one reminder per due date.
+	DueAt  *time.Time `gorm:"nullable;uniqueIndex:idx_notifications_reminder"`
+	ReadAt *time.Time `gorm:"nullable"`
+}
+
+type NotificationModel struct {
+	DB *gorm.DB
+}
+
+// GetAll returns the notifications of a given user, newest first.
+//
+// Parameters:
+// - userId: the ID of the user.
+// - unread: only return the notifications that were not read.
+//
+// Returns:
+// - []Notification: the notifications.
+// - error: any error that occurred during the retrieval process.
+func (m *NotificationModel) GetAll(userId uint, unread bool) ([]Notification, error) {
+	var notifications []Notification
+
+	query := m.DB.Where("user_id = ?", userId)
+	if unread {
+		query = query.Where("read_at IS NULL")
+	}
+
+	err := query.Order("created_at DESC, id DESC").Find(&notifications).Error
+
+	return notifications, err
+}
+
+// Create saves a notification.
+//
+// notification: the notification to save.
+// Returns an error if the insert fails.
+func (m *NotificationModel) Create(notification Notification) error {
+	return m.DB.Omit(clause.Associations).Create(&notification).Error
+}
+
+// CreateReminders saves reminders, the ones already sent for the same password and due date are skipped.
+//
+// notifications: the reminders to save.
+// Returns the number of new reminders or an error if the insert fails.
+func (m *NotificationModel) CreateReminders(notifications []Notification) (int64, error) {
+	if len(notifications) == 0 {
+		return 0, nil
+	}
+
+	result := m.DB.Omit(clause.Associations).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		CreateInBatches(&notifications, 500)
+
+	return result.RowsAffected, result.Error
+}
+
+// MarkRead marks a notification of a given user as read.
+//
+// Parameters:
+// - id: the ID of the notification.
+// - userId: the ID of the user who owns the notification.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound if the notification does not exist, or any update error.
+func (m *NotificationModel) MarkRead(id, userId uint) error {
+	result := m.DB.Model(&Notification{}).
+		Where("id = ? AND user_id = ?", id, userId).
+		Update("read_at", gorm.Expr("COALESCE(read_at, ?)", time.Now()))
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// MarkAllRead marks all unread notifications of a given user as read.
+//
+// userId: the ID of the user.
+// Returns the number of notifications marked as read or an error if the update fails.
+func (m *NotificationModel) MarkAllRead(userId uint) (int64, error) {
+	result := m.DB.Model(&Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userId).
+		Update("read_at", time.Now())
+
+	return result.RowsAffected, result.Error
+}
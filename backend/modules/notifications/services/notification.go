@@ -0,0 +1,147 @@
+package services
+
+import (
+	"backend/modules/notifications/models"
+	models2 "backend/modules/passwords/models"
+	"backend/services/config"
+	"fmt"
+	"gorm.io/gorm"
+	"time"
+)
+
+type NotificationService struct {
+	DB *gorm.DB
+}
+
+type Notification struct {
+	ID         uint       `json:"id"`
+	Type       string     `json:"type"`
+	Message    string     `json:"message"`
+	PasswordID *uint      `json:"password_id"`
+	DueAt      *time.Time `json:"due_at"`
+	ReadAt     *time.Time `json:"read_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// getModel returns a NotificationModel.
+//
+// No parameters.
+// Returns a models.NotificationModel.
+func (s *NotificationService) getModel() models.NotificationModel {
+	return models.NotificationModel{DB: s.DB}
+}
+
+// GetNotifications returns the notifications of a given user.
+//
+// Parameters:
+// - userId: the ID of the user.
+// - unread: only return the notifications that were not read.
+//
+// Returns:
+// - []Notification: the notifications, newest first.
+// - error: any error that occurred during the retrieval process.
+func (s *NotificationService) GetNotifications(userId uint, unread bool) ([]Notification, error) {
+	notificationModel := s.getModel()
+
+	notifications, err := notificationModel.GetAll(userId, unread)
+
+	notificationsList := []Notification{}
+
+	for _, notification := range notifications {
+		notificationsList = append(notificationsList, Notification{
+			ID:         notification.ID,
+			Type:       notification.Type,
+			Message:    notification.Message,
+			PasswordID: notification.PasswordID,
+			DueAt:      notification.DueAt,
+			ReadAt:     notification.ReadAt,
+			CreatedAt:  notification.CreatedAt,
+		})
+	}
+
+	return notificationsList, err
+}
+
+// MarkRead marks a notification of a given user as read.
+//
+// Parameters:
+// - id: the ID of the notification.
+// - userId: the ID of the user.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound if the notification does not exist, or any update error.
+func (s *NotificationService) MarkRead(id, userId uint) error {
+	notificationModel := s.getModel()
+
+	return notificationModel.MarkRead(id, userId)
+}
+
+// MarkAllRead marks all notifications of a given user as read.
+//
+// userId: the ID of the user.
+// Returns the number of notifications marked as read and an error if the update fails.
+func (s *NotificationService) MarkAllRead(userId uint) (int64, error) {
+	notificationModel := s.getModel()
+
+	return notificationModel.MarkAllRead(userId)
+}
+
+// Notify sends a notification to a user.
+//
+// Parameters:
+// - userId: the ID of the user.
+// - notificationType: the type of the notification, e.g. models.TypeOrganizationInvite.
+// - message: the text of the notification.
+//
+// Returns an error if the notification cannot be saved.
+func (s *NotificationService) Notify(userId uint, notificationType, message string) error {
+	notificationModel := s.getModel()
+
+	return notificationModel.Create(models.Notification{UserID: userId, Type: notificationType, Message: message})
+}
+
+// RemindRotations notifies the owners of the passwords that are due for rotation.
+//
+// Passwords are reminded ROTATION_REMINDER_DAYS days before their due date (7 by default, 0 only
+// reminds overdue passwords), once per due date, so rotating a password schedules a new reminder.
+//
+// It returns the number of new reminders and an error if the due passwords cannot be read or the reminders saved.
+func (s *NotificationService) RemindRotations() (int64, error) {
+	reminderDays := config.GetInt("ROTATION_REMINDER_DAYS", 7)
+	if reminderDays < 0 {
+		reminderDays = 0
+	}
+
+	passwordModel := models2.PasswordModel{DB: s.DB}
+
+	now := time.Now()
+
+	due, err := passwordModel.GetAllDueRotations(now.AddDate(0, 0, reminderDays))
+	if err != nil {
+		return 0, err
+	}
+
+	var reminders []models.Notification
+
+	for _, password := range due {
+		message := fmt.Sprintf("%q must be rotated by %s", password.Name, password.DueAt.Format("2006-01-02"))
+		if !password.DueAt.After(now) {
+			message = fmt.Sprintf("%q is overdue for rotation since %s", password.Name, password.DueAt.Format("2006-01-02"))
+		}
+
+		passwordId := password.ID
+		dueAt := password.DueAt
+
+		reminders = append(reminders, models.Notification{
+			UserID:     password.UserID,
+			PasswordID: &passwordId,
+			Type:       models.TypeRotationDue,
+			Message:    message,
+			DueAt:      &dueAt,
+		})
+	}
+
+	notificationModel := s.getModel()
+
+	return notificationModel.CreateReminders(reminders)
+}
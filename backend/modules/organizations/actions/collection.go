@@ -0,0 +1,510 @@
+package actions
+
+import (
+	services3 "backend/modules/passwords/services"
+	services2 "backend/services"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type CreateOrUpdateCollectionRequest struct {
+	Name string `json:"name" binding:"required,max=255"`
+}
+
+type CollectionRequest struct {
+	ID           uint `uri:"id" binding:"required"`
+	CollectionID uint `uri:"collection" binding:"required"`
+}
+
+type GrantRequest struct {
+	Permission string `json:"permission" binding:"required,oneof=read write"`
+}
+
+type CollectionMemberRequest struct {
+	ID           uint `uri:"id" binding:"required"`
+	CollectionID uint `uri:"collection" binding:"required"`
+	MemberID     uint `uri:"member" binding:"required"`
+}
+
+type CollectionGroupRequest struct {
+	ID           uint `uri:"id" binding:"required"`
+	CollectionID uint `uri:"collection" binding:"required"`
+	GroupID      uint `uri:"group" binding:"required"`
+}
+
+type AddCollectionPasswordRequest struct {
+	PasswordID uint `json:"password_id" binding:"required"`
+}
+
+type CollectionPasswordRequest struct {
+	ID           uint `uri:"id" binding:"required"`
+	CollectionID uint `uri:"collection" binding:"required"`
+	PasswordID   uint `uri:"password" binding:"required"`
+}
+
+// GetCollections retrieves the collections of an organization.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Get list of organization collections
+// @Description Retrieves the collections of the organization with the given ID the logged-in user can access,
+// @Description with their read or write permission. Owners and admins access every collection.
+// @Tags Organizations
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Organization ID"
+// @Success 200 {array} services.Collection
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /organization/{id}/collections [get]
+func GetCollections(c *gin.Context) {
+	var request OrganizationRequestAndResponse
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	organizationService, user := getServiceAndUser(c)
+
+	collections, err := organizationService.GetCollections(request.ID, user.User.ID)
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, collections)
+}
+
+// CreateCollection creates a collection in an organization.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Create organization collection
+// @Description Creates a collection, managers, admins and owners only. Managers get the write permission on it.
+// @Tags Organizations
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Organization ID"
+// @Param   collection     body    CreateOrUpdateCollectionRequest     true        "Collection"
+// @Success 200 {object} services.Collection
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 403 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /organization/{id}/collections/create [post]
+func CreateCollection(c *gin.Context) {
+	var request OrganizationRequestAndResponse
+	var json CreateOrUpdateCollectionRequest
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := c.ShouldBindJSON(&json); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	organizationService, user := getServiceAndUser(c)
+
+	collection, err := organizationService.CreateCollection(request.ID, user.User.ID, json.Name)
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, collection)
+}
+
+// UpdateCollection renames a collection.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Update organization collection
+// @Description Renames the collection, admins, owners and managers who can write to it only
+// @Tags Organizations
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Organization ID"
+// @Param   collection       path     int                             true        "Collection ID"
+// @Param   body     body    CreateOrUpdateCollectionRequest     true        "Collection"
+// @Success 200 {object} OrganizationRequestAndResponse
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 403 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /organization/{id}/collections/update/{collection} [put]
+func UpdateCollection(c *gin.Context) {
+	var request CollectionRequest
+	var json CreateOrUpdateCollectionRequest
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := c.ShouldBindJSON(&json); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	organizationService, user := getServiceAndUser(c)
+
+	if err := organizationService.UpdateCollection(request.ID, request.CollectionID, user.User.ID, json.Name); err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, OrganizationRequestAndResponse{ID: request.ID})
+}
+
+// DeleteCollection deletes a collection.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Delete organization collection
+// @Description Deletes the collection, its entries stay in the vaults of their owners.
+// @Description Admins, owners and managers who can write to it only.
+// @Tags Organizations
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Organization ID"
+// @Param   collection       path     int                             true        "Collection ID"
+// @Success 200 {object} OrganizationRequestAndResponse
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 403 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /organization/{id}/collections/delete/{collection} [delete]
+func DeleteCollection(c *gin.Context) {
+	var request CollectionRequest
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	organizationService, user := getServiceAndUser(c)
+
+	if err := organizationService.DeleteCollection(request.ID, request.CollectionID, user.User.ID); err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, OrganizationRequestAndResponse{ID: request.ID})
+}
+
+// GetGrants retrieves the members and groups a collection is granted to.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Get collection grants
+// @Description Retrieves the members and groups the collection is granted to, for the users who manage it
+// @Tags Organizations
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Organization ID"
+// @Param   collection       path     int                             true        "Collection ID"
+// @Success 200 {object} services.Grants
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 403 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /organization/{id}/collections/{collection}/grants [get]
+func GetGrants(c *gin.Context) {
+	var request CollectionRequest
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	organizationService, user := getServiceAndUser(c)
+
+	grants, err := organizationService.GetGrants(request.ID, request.CollectionID, user.User.ID)
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, grants)
+}
+
+// GrantMember grants a collection to a member.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Grant collection to member
+// @Description Grants the collection to the member with the read or write permission, or changes the permission
+// @Tags Organizations
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Organization ID"
+// @Param   collection       path     int                             true        "Collection ID"
+// @Param   member       path     int                             true        "Member ID"
+// @Param   grant     body    GrantRequest     true        "Permission"
+// @Success 200 {object} OrganizationRequestAndResponse
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 403 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /organization/{id}/collections/{collection}/grants/member/{member} [put]
+func GrantMember(c *gin.Context) {
+	var request CollectionMemberRequest
+	var json GrantRequest
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := c.ShouldBindJSON(&json); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	organizationService, user := getServiceAndUser(c)
+
+	err := organizationService.GrantMember(request.ID, request.CollectionID, user.User.ID, request.MemberID, json.Permission)
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, OrganizationRequestAndResponse{ID: request.ID})
+}
+
+// RevokeMember removes the grant of a collection to a member.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Revoke collection from member
+// @Description Removes the grant of the collection to the member, access through groups is kept
+// @Tags Organizations
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Organization ID"
+// @Param   collection       path     int                             true        "Collection ID"
+// @Param   member       path     int                             true        "Member ID"
+// @Success 200 {object} OrganizationRequestAndResponse
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 403 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /organization/{id}/collections/{collection}/grants/member/{member} [delete]
+func RevokeMember(c *gin.Context) {
+	var request CollectionMemberRequest
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	organizationService, user := getServiceAndUser(c)
+
+	if err := organizationService.RevokeMember(request.ID, request.CollectionID, user.User.ID, request.MemberID); err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, OrganizationRequestAndResponse{ID: request.ID})
+}
+
+// GrantGroup grants a collection to a group.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Grant collection to group
+// @Description Grants the collection to the members of the group with the read or write permission, or changes the permission
+// @Tags Organizations
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Organization ID"
+// @Param   collection       path     int                             true        "Collection ID"
+// @Param   group       path     int                             true        "Group ID"
+// @Param   grant     body    GrantRequest     true        "Permission"
+// @Success 200 {object} OrganizationRequestAndResponse
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 403 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /organization/{id}/collections/{collection}/grants/group/{group} [put]
+func GrantGroup(c *gin.Context) {
+	var request CollectionGroupRequest
+	var json GrantRequest
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := c.ShouldBindJSON(&json); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	organizationService, user := getServiceAndUser(c)
+
+	err := organizationService.GrantGroup(request.ID, request.CollectionID, user.User.ID, request.GroupID, json.Permission)
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, OrganizationRequestAndResponse{ID: request.ID})
+}
+
+// RevokeGroup removes the grant of a collection to a group.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Revoke collection from group
+// @Description Removes the grant of the collection to the group
+// @Tags Organizations
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Organization ID"
+// @Param   collection       path     int                             true        "Collection ID"
+// @Param   group       path     int                             true        "Group ID"
+// @Success 200 {object} OrganizationRequestAndResponse
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 403 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /organization/{id}/collections/{collection}/grants/group/{group} [delete]
+func RevokeGroup(c *gin.Context) {
+	var request CollectionGroupRequest
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	organizationService, user := getServiceAndUser(c)
+
+	if err := organizationService.RevokeGroup(request.ID, request.CollectionID, user.User.ID, request.GroupID); err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, OrganizationRequestAndResponse{ID: request.ID})
+}
+
+// GetCollectionPasswords retrieves the entries of a collection.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Get list of collection entries
+// @Description Retrieves the entries of the collection. Secrets are masked in the list, use GET /password/organization/{id} to read them.
+// @Tags Organizations
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Organization ID"
+// @Param   collection       path     int                             true        "Collection ID"
+// @Success 200 {array} services3.Password
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /organization/{id}/collections/{collection}/passwords [get]
+func GetCollectionPasswords(c *gin.Context) {
+	var request CollectionRequest
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	user := services2.GetUserFromContext(c)
+	passwordService := services3.PasswordService{DB: services2.GetDBConnection()}
+
+	passwords, err := passwordService.GetCollectionPasswords(request.ID, request.CollectionID, user.User.ID)
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, passwords)
+}
+
+// AddCollectionPassword adds an entry of the logged-in user to a collection.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Add entry to collection
+// @Description Adds an entry of the logged-in user to a collection they can write to.
+// @Description The entry stays in their vault, encrypted with their key. Client-encrypted entries cannot be added.
+// @Tags Organizations
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Organization ID"
+// @Param   collection       path     int                             true        "Collection ID"
+// @Param   password     body    AddCollectionPasswordRequest     true        "Entry"
+// @Success 200 {object} OrganizationRequestAndResponse
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 403 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /organization/{id}/collections/{collection}/passwords/add [post]
+func AddCollectionPassword(c *gin.Context) {
+	var request CollectionRequest
+	var json AddCollectionPasswordRequest
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := c.ShouldBindJSON(&json); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	organizationService, user := getServiceAndUser(c)
+
+	if err := organizationService.AddPassword(request.ID, request.CollectionID, user.User.ID, json.PasswordID); err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, OrganizationRequestAndResponse{ID: request.ID})
+}
+
+// RemoveCollectionPassword removes an entry from a collection.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Remove entry from collection
+// @Description Removes the entry from the collection, the entry itself is kept.
+// @Description Members who can write to the collection and the owner of the entry can remove it.
+// @Tags Organizations
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Organization ID"
+// @Param   collection       path     int                             true        "Collection ID"
+// @Param   password       path     int                             true        "Password ID"
+// @Success 200 {object} OrganizationRequestAndResponse
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 403 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /organization/{id}/collections/{collection}/passwords/remove/{password} [delete]
+func RemoveCollectionPassword(c *gin.Context) {
+	var request CollectionPasswordRequest
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	organizationService, user := getServiceAndUser(c)
+
+	if err := organizationService.RemovePassword(request.ID, request.CollectionID, user.User.ID, request.PasswordID); err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, OrganizationRequestAndResponse{ID: request.ID})
+}
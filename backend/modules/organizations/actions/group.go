@@ -0,0 +1,170 @@
+package actions
+
+import (
+	services2 "backend/services"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type CreateOrUpdateGroupRequest struct {
+	Name      string `json:"name" binding:"required,max=255"`
+	MemberIDs []uint `json:"member_ids"`
+}
+
+type GroupRequest struct {
+	ID      uint `uri:"id" binding:"required"`
+	GroupID uint `uri:"group" binding:"required"`
+}
+
+// GetGroups retrieves the groups of an organization.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Get list of organization groups
+// @Description Retrieves the groups of the organization with the given ID with their members
+// @Tags Organizations
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Organization ID"
+// @Success 200 {array} services.Group
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /organization/{id}/groups [get]
+func GetGroups(c *gin.Context) {
+	var request OrganizationRequestAndResponse
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	organizationService, user := getServiceAndUser(c)
+
+	groups, err := organizationService.GetGroups(request.ID, user.User.ID)
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, groups)
+}
+
+// CreateGroup creates a group of organization members.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Create organization group
+// @Description Creates a group of members collections can be granted to, admins and owners only
+// @Tags Organizations
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Organization ID"
+// @Param   group     body    CreateOrUpdateGroupRequest     true        "Group"
+// @Success 200 {object} services.Group
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 403 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /organization/{id}/groups/create [post]
+func CreateGroup(c *gin.Context) {
+	var request OrganizationRequestAndResponse
+	var json CreateOrUpdateGroupRequest
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := c.ShouldBindJSON(&json); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	organizationService, user := getServiceAndUser(c)
+
+	group, err := organizationService.CreateGroup(request.ID, user.User.ID, json.Name, json.MemberIDs)
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, group)
+}
+
+// UpdateGroup updates a group of organization members.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Update organization group
+// @Description Renames the group and replaces its members, admins and owners only
+// @Tags Organizations
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Organization ID"
+// @Param   group       path     int                             true        "Group ID"
+// @Param   body     body    CreateOrUpdateGroupRequest     true        "Group"
+// @Success 200 {object} OrganizationRequestAndResponse
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 403 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /organization/{id}/groups/update/{group} [put]
+func UpdateGroup(c *gin.Context) {
+	var request GroupRequest
+	var json CreateOrUpdateGroupRequest
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := c.ShouldBindJSON(&json); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	organizationService, user := getServiceAndUser(c)
+
+	if err := organizationService.UpdateGroup(request.ID, request.GroupID, user.User.ID, json.Name, json.MemberIDs); err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, OrganizationRequestAndResponse{ID: request.ID})
+}
+
+// DeleteGroup deletes a group of organization members.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Delete organization group
+// @Description Deletes the group and the access granted to it, admins and owners only
+// @Tags Organizations
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Organization ID"
+// @Param   group       path     int                             true        "Group ID"
+// @Success 200 {object} OrganizationRequestAndResponse
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 403 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /organization/{id}/groups/delete/{group} [delete]
+func DeleteGroup(c *gin.Context) {
+	var request GroupRequest
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	organizationService, user := getServiceAndUser(c)
+
+	if err := organizationService.DeleteGroup(request.ID, request.GroupID, user.User.ID); err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, OrganizationRequestAndResponse{ID: request.ID})
+}
@@ -0,0 +1,444 @@
+package actions
+
+import (
+	"backend/modules/organizations/services"
+	"backend/modules/users/models"
+	services2 "backend/services"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"net/http"
+)
+
+type CreateOrUpdateOrganizationRequest struct {
+	Name string `json:"name" binding:"required,max=255"`
+}
+
+type OrganizationRequestAndResponse struct {
+	ID uint `json:"id" uri:"id" binding:"required"`
+}
+
+type InviteMemberRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	// owner, admin, manager or member, only owners can invite owners.
+	Role string `json:"role" binding:"required,oneof=owner admin manager member"`
+}
+
+type UpdateMemberRequest struct {
+	Role string `json:"role" binding:"required,oneof=owner admin manager member"`
+}
+
+type MemberRequest struct {
+	ID       uint `uri:"id" binding:"required"`
+	MemberID uint `uri:"member" binding:"required"`
+}
+
+// GetOrganizations retrieves the organizations of the logged-in user.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Get list of organizations
+// @Description Retrieves the organizations the logged-in user is a member of, with their role
+// @Tags Organizations
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Success 200 {array} services.Organization
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /organization/all [get]
+func GetOrganizations(c *gin.Context) {
+	organizationService, user := getServiceAndUser(c)
+
+	organizations, err := organizationService.GetOrganizations(user.User.ID)
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, organizations)
+}
+
+// CreateOrganization creates an organization.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Create organization
+// @Description Creates an organization owned by the logged-in user
+// @Tags Organizations
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   organization     body    CreateOrUpdateOrganizationRequest     true        "Organization"
+// @Success 200 {object} services.Organization
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /organization/create [post]
+func CreateOrganization(c *gin.Context) {
+	var json CreateOrUpdateOrganizationRequest
+	if err := c.ShouldBindJSON(&json); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	organizationService, user := getServiceAndUser(c)
+
+	organization, err := organizationService.CreateOrganization(user.User.ID, json.Name)
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, organization)
+}
+
+// UpdateOrganization renames an organization.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Update organization
+// @Description Renames the organization with the given ID, admins and owners only
+// @Tags Organizations
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Organization ID"
+// @Param   organization     body    CreateOrUpdateOrganizationRequest     true        "Organization"
+// @Success 200 {object} OrganizationRequestAndResponse
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 403 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /organization/update/{id} [put]
+func UpdateOrganization(c *gin.Context) {
+	var request OrganizationRequestAndResponse
+	var json CreateOrUpdateOrganizationRequest
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := c.ShouldBindJSON(&json); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	organizationService, user := getServiceAndUser(c)
+
+	if err := organizationService.UpdateOrganization(request.ID, user.User.ID, json.Name); err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, OrganizationRequestAndResponse{ID: request.ID})
+}
+
+// DeleteOrganization deletes an organization.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Delete organization
+// @Description Deletes the organization with the given ID with its members, groups and collections, owners only.
+// @Description The entries of its collections stay in the vaults of the members who added them.
+// @Tags Organizations
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Organization ID"
+// @Success 200 {object} OrganizationRequestAndResponse
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 403 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /organization/delete/{id} [delete]
+func DeleteOrganization(c *gin.Context) {
+	var request OrganizationRequestAndResponse
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	organizationService, user := getServiceAndUser(c)
+
+	if err := organizationService.DeleteOrganization(request.ID, user.User.ID); err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, OrganizationRequestAndResponse{ID: request.ID})
+}
+
+// GetMembers retrieves the members of an organization.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Get list of organization members
+// @Description Retrieves the members and pending invitations of the organization with the given ID
+// @Tags Organizations
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Organization ID"
+// @Success 200 {array} services.Member
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /organization/{id}/members [get]
+func GetMembers(c *gin.Context) {
+	var request OrganizationRequestAndResponse
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	organizationService, user := getServiceAndUser(c)
+
+	members, err := organizationService.GetMembers(request.ID, user.User.ID)
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, members)
+}
+
+// InviteMember invites an email to an organization.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Invite organization member
+// @Description Invites the given email to the organization with the given ID, admins and owners only.
+// @Description A registered invitee is notified, the invitation of an unknown email waits until it signs up.
+// @Tags Organizations
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Organization ID"
+// @Param   invitation     body    InviteMemberRequest     true        "Invitation"
+// @Success 200 {object} services.Member
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 403 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 409 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /organization/{id}/members/invite [post]
+func InviteMember(c *gin.Context) {
+	var request OrganizationRequestAndResponse
+	var json InviteMemberRequest
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := c.ShouldBindJSON(&json); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	organizationService, user := getServiceAndUser(c)
+
+	member, err := organizationService.InviteMember(request.ID, user.User.ID, json.Email, json.Role)
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, member)
+}
+
+// UpdateMember changes the role of an organization member.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Update organization member
+// @Description Changes the role of a member, admins and owners only. Only owners can make or change owners.
+// @Tags Organizations
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Organization ID"
+// @Param   member       path     int                             true        "Member ID"
+// @Param   role     body    UpdateMemberRequest     true        "Role"
+// @Success 200 {object} OrganizationRequestAndResponse
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 403 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /organization/{id}/members/update/{member} [put]
+func UpdateMember(c *gin.Context) {
+	var request MemberRequest
+	var json UpdateMemberRequest
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := c.ShouldBindJSON(&json); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	organizationService, user := getServiceAndUser(c)
+
+	if err := organizationService.UpdateMember(request.ID, request.MemberID, user.User.ID, json.Role); err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, OrganizationRequestAndResponse{ID: request.ID})
+}
+
+// RemoveMember removes a member from an organization.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Remove organization member
+// @Description Removes a member or cancels an invitation, admins and owners only. Members can remove themselves to leave.
+// @Tags Organizations
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Organization ID"
+// @Param   member       path     int                             true        "Member ID"
+// @Success 200 {object} OrganizationRequestAndResponse
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 403 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /organization/{id}/members/delete/{member} [delete]
+func RemoveMember(c *gin.Context) {
+	var request MemberRequest
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	organizationService, user := getServiceAndUser(c)
+
+	if err := organizationService.RemoveMember(request.ID, request.MemberID, user.User.ID); err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, OrganizationRequestAndResponse{ID: request.ID})
+}
+
+// GetInvitations retrieves the pending invitations of the logged-in user.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Get list of organization invitations
+// @Description Retrieves the pending invitations addressed to the logged-in user, newest first
+// @Tags Organizations
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Success 200 {array} services.Invitation
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /organization/invitations [get]
+func GetInvitations(c *gin.Context) {
+	organizationService, user := getServiceAndUser(c)
+
+	invitations, err := organizationService.GetInvitations(user.User.ID)
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, invitations)
+}
+
+// AcceptInvitation joins the organization of an invitation.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Accept organization invitation
+// @Description Accepts the invitation with the given ID and returns the joined organization
+// @Tags Organizations
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Invitation ID"
+// @Success 200 {object} services.Organization
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /organization/invitations/accept/{id} [post]
+func AcceptInvitation(c *gin.Context) {
+	var request OrganizationRequestAndResponse
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	organizationService, user := getServiceAndUser(c)
+
+	organization, err := organizationService.AcceptInvitation(request.ID, user.User.ID)
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, organization)
+}
+
+// DeclineInvitation declines an invitation.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Decline organization invitation
+// @Description Deletes the invitation with the given ID
+// @Tags Organizations
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Invitation ID"
+// @Success 200 {object} OrganizationRequestAndResponse
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /organization/invitations/decline/{id} [post]
+func DeclineInvitation(c *gin.Context) {
+	var request OrganizationRequestAndResponse
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	organizationService, user := getServiceAndUser(c)
+
+	if err := organizationService.DeclineInvitation(request.ID, user.User.ID); err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, OrganizationRequestAndResponse{ID: request.ID})
+}
+
+// errorStatus maps a service error to the HTTP status code of the response.
+//
+// It takes the error returned by the organization service.
+// It returns 404 for missing records, 403 for insufficient roles, 400 for invalid input,
+// 409 for existing members and 500 otherwise.
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrForbidden), errors.Is(err, services.ErrAccessDenied):
+		return http.StatusForbidden
+	case errors.Is(err, services.ErrLastOwner), errors.Is(err, services.ErrMemberNotFound),
+		errors.Is(err, services.ErrClientEncrypted):
+		return http.StatusBadRequest
+	case errors.Is(err, services.ErrMemberExists):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// getServiceAndUser returns the organization service and user token.
+//
+// It takes a Gin context as a parameter.
+// It returns an OrganizationService and a Token.
+func getServiceAndUser(c *gin.Context) (services.OrganizationService, models.Token) {
+	service := services.OrganizationService{DB: services2.GetDBConnection()}
+	user := services2.GetUserFromContext(c)
+
+	return service, user
+}
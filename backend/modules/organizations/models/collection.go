@@ -0,0 +1,345 @@
+package models
+
+import (
+	models2 "backend/modules/passwords/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	PermissionRead  = "read"
+	PermissionWrite = "write"
+)
+
+// Collection holds entries of an organization.
+//
+// The entries stay owned and encrypted by the members who added them, a collection only references them.
+type Collection struct {
+	gorm.Model
+	OrganizationID uint               `gorm:"not null;index"`
+	Organization   Organization       `gorm:"foreignKey:OrganizationID;constraint:OnDelete:CASCADE"`
+	Name           string             `gorm:"not null"`
+	Passwords      []models2.Password `gorm:"many2many:collection_passwords;constraint:OnDelete:CASCADE"`
+}
+
+// CollectionPassword is a row of the collection_passwords join table between collections and passwords.
+type CollectionPassword struct {
+	CollectionID uint `gorm:"primaryKey"`
+	PasswordID   uint `gorm:"primaryKey"`
+}
+
+// TableName returns the join table created for Collection.Passwords.
+func (CollectionPassword) TableName() string {
+	return "collection_passwords"
+}
+
+// CollectionMember grants a member access to a collection.
+type CollectionMember struct {
+	CollectionID         uint               `gorm:"primaryKey"`
+	Collection           Collection         `gorm:"foreignKey:CollectionID;constraint:OnDelete:CASCADE"`
+	OrganizationMemberID uint               `gorm:"primaryKey;index"`
+	Member               OrganizationMember `gorm:"foreignKey:OrganizationMemberID;constraint:OnDelete:CASCADE"`
+	Permission           string             `gorm:"not null"`
+}
+
+// CollectionGroup grants the members of a group access to a collection.
+type CollectionGroup struct {
+	CollectionID        uint              `gorm:"primaryKey"`
+	Collection          Collection        `gorm:"foreignKey:CollectionID;constraint:OnDelete:CASCADE"`
+	OrganizationGroupID uint              `gorm:"primaryKey;index"`
+	Group               OrganizationGroup `gorm:"foreignKey:OrganizationGroupID;constraint:OnDelete:CASCADE"`
+	Permission          string            `gorm:"not null"`
+}
+
+type CollectionModel struct {
+	DB *gorm.DB
+}
+
+// GetAll returns the collections of an organization.
+//
+// organizationId: the ID of the organization.
+// []Collection: the collections ordered by name.
+// error: any error that occurred during the retrieval process.
+func (m *CollectionModel) GetAll(organizationId uint) ([]Collection, error) {
+	var collections []Collection
+
+	err := m.DB.Where("organization_id = ?", organizationId).Order("name, id").Find(&collections).Error
+
+	return collections, err
+}
+
+// Get returns a collection of an organization.
+//
+// Parameters:
+// - id: the ID of the collection.
+// - organizationId: the ID of the organization.
+//
+// Returns:
+// - Collection: the found collection.
+// - error: gorm.ErrRecordNotFound if the collection does not belong to the organization.
+func (m *CollectionModel) Get(id, organizationId uint) (Collection, error) {
+	var collection Collection
+
+	err := m.DB.Where("id = ? AND organization_id = ?", id, organizationId).First(&collection).Error
+
+	return collection, err
+}
+
+// Create creates a collection, and grants it to a member if one is given.
+//
+// Parameters:
+// - collection: the collection to create.
+// - memberId: the member who gets the write permission, 0 for none.
+//
+// Returns the created collection or an error if the creation fails.
+func (m *CollectionModel) Create(collection Collection, memberId uint) (Collection, error) {
+	err := m.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(&collection).Error; err != nil {
+			return err
+		}
+
+		if memberId == 0 {
+			return nil
+		}
+
+		return tx.Omit(clause.Associations).Create(&CollectionMember{
+			CollectionID:         collection.ID,
+			OrganizationMemberID: memberId,
+			Permission:           PermissionWrite,
+		}).Error
+	})
+
+	return collection, err
+}
+
+// Update renames a collection.
+//
+// Parameters:
+// - id: the ID of the collection.
+// - organizationId: the ID of the organization.
+// - name: the new name.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound if the collection does not belong to the organization, or any update error.
+func (m *CollectionModel) Update(id, organizationId uint, name string) error {
+	result := m.DB.Model(&Collection{}).Where("id = ? AND organization_id = ?", id, organizationId).Update("name", name)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// Delete deletes a collection with its access grants, the entries stay with their owners.
+//
+// Parameters:
+// - id: the ID of the collection.
+// - organizationId: the ID of the organization.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound if the collection does not belong to the organization, or any deletion error.
+func (m *CollectionModel) Delete(id, organizationId uint) error {
+	result := m.DB.Unscoped().Where("id = ? AND organization_id = ?", id, organizationId).Delete(&Collection{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// GetMemberGrants returns the members a collection is granted to.
+//
+// collectionId: the ID of the collection.
+// []CollectionMember: the grants with their member, ordered by email.
+// error: any error that occurred during the retrieval process.
+func (m *CollectionModel) GetMemberGrants(collectionId uint) ([]CollectionMember, error) {
+	var grants []CollectionMember
+
+	err := m.DB.Joins("Member").
+		Where("collection_members.collection_id = ?", collectionId).
+		Order(`"Member"."email"`).
+		Find(&grants).Error
+
+	return grants, err
+}
+
+// GetGroupGrants returns the groups a collection is granted to.
+//
+// collectionId: the ID of the collection.
+// []CollectionGroup: the grants with their group, ordered by name.
+// error: any error that occurred during the retrieval process.
+func (m *CollectionModel) GetGroupGrants(collectionId uint) ([]CollectionGroup, error) {
+	var grants []CollectionGroup
+
+	err := m.DB.Joins("Group").
+		Where("collection_groups.collection_id = ?", collectionId).
+		Order(`"Group"."name"`).
+		Find(&grants).Error
+
+	return grants, err
+}
+
+// GrantMember grants a collection to a member, or changes the permission of an existing grant.
+//
+// grant: the collection, member and permission.
+// Returns an error if the grant cannot be saved.
+func (m *CollectionModel) GrantMember(grant CollectionMember) error {
+	return m.DB.Omit(clause.Associations).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "collection_id"}, {Name: "organization_member_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"permission"}),
+	}).Create(&grant).Error
+}
+
+// RevokeMember removes the grant of a collection to a member.
+//
+// Parameters:
+// - collectionId: the ID of the collection.
+// - memberId: the ID of the member.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound if the collection is not granted to the member, or any deletion error.
+func (m *CollectionModel) RevokeMember(collectionId, memberId uint) error {
+	return deleteOne(m.DB.Where("collection_id = ? AND organization_member_id = ?", collectionId, memberId).Delete(&CollectionMember{}))
+}
+
+// GrantGroup grants a collection to a group, or changes the permission of an existing grant.
+//
+// grant: the collection, group and permission.
+// Returns an error if the grant cannot be saved.
+func (m *CollectionModel) GrantGroup(grant CollectionGroup) error {
+	return m.DB.Omit(clause.Associations).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "collection_id"}, {Name: "organization_group_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"permission"}),
+	}).Create(&grant).Error
+}
+
+// RevokeGroup removes the grant of a collection to a group.
+//
+// Parameters:
+// - collectionId: the ID of the collection.
+// - groupId: the ID of the group.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound if the collection is not granted to the group, or any deletion error.
+func (m *CollectionModel) RevokeGroup(collectionId, groupId uint) error {
+	return deleteOne(m.DB.Where("collection_id = ? AND organization_group_id = ?", collectionId, groupId).Delete(&CollectionGroup{}))
+}
+
+// GetGrants returns the permissions of a user on collections, granted directly or through groups.
+//
+// Parameters:
+// - userId: the ID of the user.
+// - collections: a subquery selecting the IDs of the collections to check.
+//
+// Returns:
+// - map[uint]string: the permissions by collection ID, write when both are granted.
+// - error: any error that occurred during the retrieval process.
+func (m *CollectionModel) GetGrants(userId uint, collections *gorm.DB) (map[uint]string, error) {
+	var rows []struct {
+		CollectionID uint
+		Permission   string
+	}
+
+	err := m.DB.Raw(`SELECT collection_members.collection_id, collection_members.permission
+		FROM collection_members
+		JOIN organization_members ON organization_members.id = collection_members.organization_member_id
+		WHERE organization_members.user_id = ? AND organization_members.accepted_at IS NOT NULL
+			AND collection_members.collection_id IN (?)
+		UNION ALL
+		SELECT collection_groups.collection_id, collection_groups.permission
+		FROM collection_groups
+		JOIN organization_group_members ON organization_group_members.organization_group_id = collection_groups.organization_group_id
+		JOIN organization_members ON organization_members.id = organization_group_members.organization_member_id
+		WHERE organization_members.user_id = ? AND organization_members.accepted_at IS NOT NULL
+			AND collection_groups.collection_id IN (?)`,
+		userId, collections, userId, collections).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	grants := map[uint]string{}
+	for _, row := range rows {
+		if grants[row.CollectionID] != PermissionWrite {
+			grants[row.CollectionID] = row.Permission
+		}
+	}
+
+	return grants, nil
+}
+
+// AddPassword adds an entry to a collection, entries already in it are skipped.
+//
+// Parameters:
+// - collectionId: the ID of the collection.
+// - passwordId: the ID of the entry.
+//
+// Returns an error if the entry cannot be added.
+func (m *CollectionModel) AddPassword(collectionId, passwordId uint) error {
+	return m.DB.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&CollectionPassword{CollectionID: collectionId, PasswordID: passwordId}).Error
+}
+
+// RemovePassword removes an entry from a collection.
+//
+// Parameters:
+// - collectionId: the ID of the collection.
+// - passwordId: the ID of the entry.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound if the entry is not in the collection, or any deletion error.
+func (m *CollectionModel) RemovePassword(collectionId, passwordId uint) error {
+	return deleteOne(m.DB.Where("collection_id = ? AND password_id = ?", collectionId, passwordId).Delete(&CollectionPassword{}))
+}
+
+// GetPasswords returns the entries of a collection with their owner, trashed entries excluded.
+//
+// collectionId: the ID of the collection.
+// []models2.Password: the entries ordered by name, only their ID and owner are loaded.
+// error: any error that occurred during the retrieval process.
+func (m *CollectionModel) GetPasswords(collectionId uint) ([]models2.Password, error) {
+	var passwords []models2.Password
+
+	err := m.DB.Select("passwords.id", "passwords.user_id").
+		Joins("JOIN collection_passwords ON collection_passwords.password_id = passwords.id").
+		Where("collection_passwords.collection_id = ?", collectionId).
+		Order("passwords.name, passwords.id").
+		Find(&passwords).Error
+
+	return passwords, err
+}
+
+// GetPasswordCollections returns the collections holding an entry.
+//
+// passwordId: the ID of the entry.
+// []Collection: the collections.
+// error: any error that occurred during the retrieval process.
+func (m *CollectionModel) GetPasswordCollections(passwordId uint) ([]Collection, error) {
+	var collections []Collection
+
+	err := m.DB.Joins("JOIN collection_passwords ON collection_passwords.collection_id = collections.id").
+		Where("collection_passwords.password_id = ?", passwordId).
+		Find(&collections).Error
+
+	return collections, err
+}
+
+// deleteOne returns gorm.ErrRecordNotFound when a deletion did not match any row.
+func deleteOne(result *gorm.DB) error {
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
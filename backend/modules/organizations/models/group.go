@@ -0,0 +1,170 @@
+package models
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OrganizationGroup is a set of members collections can be granted to at once.
+type OrganizationGroup struct {
+	gorm.Model
+	OrganizationID uint                 `gorm:"not null;index"`
+	Organization   Organization         `gorm:"foreignKey:OrganizationID;constraint:OnDelete:CASCADE"`
+	Name           string               `gorm:"not null"`
+	Members        []OrganizationMember `gorm:"many2many:organization_group_members;constraint:OnDelete:CASCADE"`
+	// MemberIDs are the members saved by Create and Update, Members is only filled when reading.
+	MemberIDs []uint `gorm:"-"`
+}
+
+// GroupMember is a row of the organization_group_members join table between groups and members.
+type GroupMember struct {
+	OrganizationGroupID  uint `gorm:"primaryKey"`
+	OrganizationMemberID uint `gorm:"primaryKey"`
+}
+
+// TableName returns the join table created for OrganizationGroup.Members.
+func (GroupMember) TableName() string {
+	return "organization_group_members"
+}
+
+type GroupModel struct {
+	DB *gorm.DB
+}
+
+// GetAll returns the groups of an organization with their members.
+//
+// organizationId: the ID of the organization.
+// []OrganizationGroup: the groups ordered by name.
+// error: any error that occurred during the retrieval process.
+func (m *GroupModel) GetAll(organizationId uint) ([]OrganizationGroup, error) {
+	var groups []OrganizationGroup
+
+	err := m.DB.Preload("Members", orderMembers).
+		Where("organization_id = ?", organizationId).
+		Order("name, id").
+		Find(&groups).Error
+
+	return groups, err
+}
+
+// Get returns a group of an organization with its members.
+//
+// Parameters:
+// - id: the ID of the group.
+// - organizationId: the ID of the organization.
+//
+// Returns:
+// - OrganizationGroup: the found group.
+// - error: gorm.ErrRecordNotFound if the group does not belong to the organization.
+func (m *GroupModel) Get(id, organizationId uint) (OrganizationGroup, error) {
+	var group OrganizationGroup
+
+	err := m.DB.Preload("Members", orderMembers).
+		Where("id = ? AND organization_id = ?", id, organizationId).
+		First(&group).Error
+
+	return group, err
+}
+
+// Create creates a group with its members.
+//
+// group: the group to create, MemberIDs must belong to its organization.
+// Returns the created group or an error if the creation fails.
+func (m *GroupModel) Create(group OrganizationGroup) (OrganizationGroup, error) {
+	err := m.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(&group).Error; err != nil {
+			return err
+		}
+
+		return replaceMembers(tx, group.ID, group.MemberIDs)
+	})
+
+	return group, err
+}
+
+// Update renames a group and replaces its members.
+//
+// Parameters:
+// - id: the ID of the group.
+// - organizationId: the ID of the organization.
+// - group: the new values, MemberIDs must belong to the organization.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound if the group does not belong to the organization, or any update error.
+func (m *GroupModel) Update(id, organizationId uint, group OrganizationGroup) error {
+	return m.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&OrganizationGroup{}).Where("id = ? AND organization_id = ?", id, organizationId).Update("name", group.Name)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return replaceMembers(tx, id, group.MemberIDs)
+	})
+}
+
+// Delete deletes a group with the access granted to it.
+//
+// Parameters:
+// - id: the ID of the group.
+// - organizationId: the ID of the organization.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound if the group does not belong to the organization, or any deletion error.
+func (m *GroupModel) Delete(id, organizationId uint) error {
+	result := m.DB.Unscoped().Where("id = ? AND organization_id = ?", id, organizationId).Delete(&OrganizationGroup{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// CountMembers returns how many of the given members belong to an organization.
+//
+// Parameters:
+// - ids: the IDs of the members, without duplicates.
+// - organizationId: the ID of the organization.
+//
+// Returns:
+// - int64: the number of members of the organization.
+// - error: any error that occurred during the count.
+func (m *GroupModel) CountMembers(ids []uint, organizationId uint) (int64, error) {
+	var count int64
+
+	err := m.DB.Model(&OrganizationMember{}).
+		Where("id IN ? AND organization_id = ?", ids, organizationId).
+		Count(&count).Error
+
+	return count, err
+}
+
+// replaceMembers replaces the members of a group.
+func replaceMembers(tx *gorm.DB, groupId uint, memberIds []uint) error {
+	if err := tx.Where("organization_group_id = ?", groupId).Delete(&GroupMember{}).Error; err != nil {
+		return err
+	}
+
+	if len(memberIds) == 0 {
+		return nil
+	}
+
+	rows := make([]GroupMember, len(memberIds))
+	for i, memberId := range memberIds {
+		rows[i] = GroupMember{OrganizationGroupID: groupId, OrganizationMemberID: memberId}
+	}
+
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error
+}
+
+// orderMembers sorts preloaded members by email.
+func orderMembers(db *gorm.DB) *gorm.DB {
+	return db.Order("email")
+}
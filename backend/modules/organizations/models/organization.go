@@ -0,0 +1,291 @@
+package models
+
+import (
+	"backend/modules/users/models"
+	"gorm.io/gorm"
+	"time"
+)
+
+const (
+	RoleOwner   = "owner"
+	RoleAdmin   = "admin"
+	RoleManager = "manager"
+	RoleMember  = "member"
+)
+
+var Roles = []string{RoleOwner, RoleAdmin, RoleManager, RoleMember}
+
+type Organization struct {
+	gorm.Model
+	Name string `gorm:"not null"`
+}
+
+// OrganizationMember is a user of an organization, or an invitation until AcceptedAt is set.
+//
+// Invitations are addressed to an email, UserID is set when the email belongs to a user.
+type OrganizationMember struct {
+	gorm.Model
+	OrganizationID uint         `gorm:"not null;uniqueIndex:idx_organization_members_email"`
+	Organization   Organization `gorm:"foreignKey:OrganizationID;constraint:OnDelete:CASCADE"`
+	UserID         *uint        `gorm:"nullable;index"`
+	User           models.User  `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	Email          string       `gorm:"not null;uniqueIndex:idx_organization_members_email"`
+	Role           string       `gorm:"not null"`
+	InvitedBy      string       `gorm:"not null;default:''"`
+	AcceptedAt     *time.Time   `gorm:"nullable"`
+}
+
+type OrganizationModel struct {
+	DB *gorm.DB
+}
+
+// GetAll returns the memberships of a given user with their organization.
+//
+// userId: the ID of the user.
+// []OrganizationMember: the accepted memberships ordered by organization name.
+// error: any error that occurred during the retrieval process.
+func (m *OrganizationModel) GetAll(userId uint) ([]OrganizationMember, error) {
+	var members []OrganizationMember
+
+	err := m.DB.Preload("Organization").
+		Joins("JOIN organizations ON organizations.id = organization_members.organization_id AND organizations.deleted_at IS NULL").
+		Where("organization_members.user_id = ? AND organization_members.accepted_at IS NOT NULL", userId).
+		Order("organizations.name, organizations.id").
+		Find(&members).Error
+
+	return members, err
+}
+
+// GetMember returns the accepted membership of a user in an organization.
+//
+// Parameters:
+// - organizationId: the ID of the organization.
+// - userId: the ID of the user.
+//
+// Returns:
+// - OrganizationMember: the membership with its organization.
+// - error: gorm.ErrRecordNotFound if the user is not a member.
+func (m *OrganizationModel) GetMember(organizationId, userId uint) (OrganizationMember, error) {
+	var member OrganizationMember
+
+	err := m.DB.Preload("Organization").
+		Joins("JOIN organizations ON organizations.id = organization_members.organization_id AND organizations.deleted_at IS NULL").
+		Where("organization_members.organization_id = ? AND organization_members.user_id = ? AND organization_members.accepted_at IS NOT NULL", organizationId, userId).
+		First(&member).Error
+
+	return member, err
+}
+
+// Create creates an organization with its creator as owner.
+//
+// Parameters:
+// - name: the name of the organization.
+// - userId: the ID of the creator.
+// - email: the email of the creator.
+//
+// Returns:
+// - OrganizationMember: the membership of the owner with the created organization.
+// - error: any error that occurred during the creation process.
+func (m *OrganizationModel) Create(name string, userId uint, email string) (OrganizationMember, error) {
+	now := time.Now()
+	member := OrganizationMember{
+		Organization: Organization{Name: name},
+		UserID:       &userId,
+		Email:        email,
+		Role:         RoleOwner,
+		AcceptedAt:   &now,
+	}
+
+	err := m.DB.Omit("User").Create(&member).Error
+
+	return member, err
+}
+
+// Update renames an organization.
+//
+// Parameters:
+// - id: the ID of the organization.
+// - name: the new name.
+//
+// Returns an error if the update fails.
+func (m *OrganizationModel) Update(id uint, name string) error {
+	return m.DB.Model(&Organization{}).Where("id = ?", id).Update("name", name).Error
+}
+
+// Delete deletes an organization with its members, groups and collections, the entries stay with their owners.
+//
+// id: the ID of the organization.
+// Returns an error if the deletion fails.
+func (m *OrganizationModel) Delete(id uint) error {
+	return m.DB.Unscoped().Delete(&Organization{}, id).Error
+}
+
+// GetMembers returns the members and pending invitations of an organization.
+//
+// organizationId: the ID of the organization.
+// []OrganizationMember: the members ordered by email.
+// error: any error that occurred during the retrieval process.
+func (m *OrganizationModel) GetMembers(organizationId uint) ([]OrganizationMember, error) {
+	var members []OrganizationMember
+
+	err := m.DB.Preload("User", selectUser).
+		Where("organization_id = ?", organizationId).
+		Order("email").
+		Find(&members).Error
+
+	return members, err
+}
+
+// GetMemberByID returns a member or a pending invitation of an organization.
+//
+// Parameters:
+// - id: the ID of the member.
+// - organizationId: the ID of the organization.
+//
+// Returns:
+// - OrganizationMember: the member.
+// - error: gorm.ErrRecordNotFound if the member does not belong to the organization.
+func (m *OrganizationModel) GetMemberByID(id, organizationId uint) (OrganizationMember, error) {
+	var member OrganizationMember
+
+	err := m.DB.Where("id = ? AND organization_id = ?", id, organizationId).First(&member).Error
+
+	return member, err
+}
+
+// Invite saves an invitation, an email already invited or member of the organization is a conflict.
+//
+// member: the invitation, AcceptedAt must be nil.
+// Returns the saved invitation and whether it was created.
+func (m *OrganizationModel) Invite(member OrganizationMember) (OrganizationMember, bool, error) {
+	result := m.DB.Omit("Organization", "User").
+		Where(OrganizationMember{OrganizationID: member.OrganizationID, Email: member.Email}).
+		FirstOrCreate(&member)
+
+	return member, result.RowsAffected == 1, result.Error
+}
+
+// GetInvitations returns the pending invitations of a user.
+//
+// Parameters:
+// - userId: the ID of the user.
+// - email: the email of the user, invitations sent before they signed up only know it.
+//
+// Returns:
+// - []OrganizationMember: the invitations with their organization, newest first.
+// - error: any error that occurred during the retrieval process.
+func (m *OrganizationModel) GetInvitations(userId uint, email string) ([]OrganizationMember, error) {
+	var members []OrganizationMember
+
+	err := m.DB.Preload("Organization").
+		Joins("JOIN organizations ON organizations.id = organization_members.organization_id AND organizations.deleted_at IS NULL").
+		Scopes(invitationOf(userId, email)).
+		Order("organization_members.id DESC").
+		Find(&members).Error
+
+	return members, err
+}
+
+// Accept accepts an invitation of a user.
+//
+// Parameters:
+// - id: the ID of the invitation.
+// - userId: the ID of the user.
+// - email: the email of the user.
+//
+// Returns:
+// - OrganizationMember: the membership.
+// - error: gorm.ErrRecordNotFound if the invitation does not exist or is not addressed to the user.
+func (m *OrganizationModel) Accept(id, userId uint, email string) (OrganizationMember, error) {
+	result := m.DB.Model(&OrganizationMember{}).
+		Where("id = ?", id).
+		Scopes(invitationOf(userId, email)).
+		Updates(map[string]interface{}{"user_id": userId, "accepted_at": time.Now()})
+	if result.Error != nil {
+		return OrganizationMember{}, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return OrganizationMember{}, gorm.ErrRecordNotFound
+	}
+
+	var member OrganizationMember
+
+	err := m.DB.Preload("Organization").Where("id = ?", id).First(&member).Error
+
+	return member, err
+}
+
+// Decline deletes an invitation of a user.
+//
+// Parameters:
+// - id: the ID of the invitation.
+// - userId: the ID of the user.
+// - email: the email of the user.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound if the invitation does not exist or is not addressed to the user.
+func (m *OrganizationModel) Decline(id, userId uint, email string) error {
+	result := m.DB.Unscoped().Where("id = ?", id).Scopes(invitationOf(userId, email)).Delete(&OrganizationMember{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// UpdateRole changes the role of a member.
+//
+// Parameters:
+// - id: the ID of the member.
+// - organizationId: the ID of the organization.
+// - role: the new role.
+//
+// Returns an error if the update fails.
+func (m *OrganizationModel) UpdateRole(id, organizationId uint, role string) error {
+	return m.DB.Model(&OrganizationMember{}).
+		Where("id = ? AND organization_id = ?", id, organizationId).
+		Update("role", role).Error
+}
+
+// RemoveMember removes a member or cancels an invitation, with the access granted to the member.
+//
+// Parameters:
+// - id: the ID of the member.
+// - organizationId: the ID of the organization.
+//
+// Returns an error if the deletion fails.
+func (m *OrganizationModel) RemoveMember(id, organizationId uint) error {
+	return m.DB.Unscoped().Where("id = ? AND organization_id = ?", id, organizationId).Delete(&OrganizationMember{}).Error
+}
+
+// CountOwners returns the number of owners of an organization.
+//
+// organizationId: the ID of the organization.
+// Returns the number of accepted owners or an error if the count fails.
+func (m *OrganizationModel) CountOwners(organizationId uint) (int64, error) {
+	var count int64
+
+	err := m.DB.Model(&OrganizationMember{}).
+		Where("organization_id = ? AND role = ? AND accepted_at IS NOT NULL", organizationId, RoleOwner).
+		Count(&count).Error
+
+	return count, err
+}
+
+// invitationOf filters the pending invitations addressed to a user.
+func invitationOf(userId uint, email string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("organization_members.accepted_at IS NULL").
+			Where("organization_members.user_id = ? OR (organization_members.user_id IS NULL AND organization_members.email = ?)", userId, email)
+	}
+}
+
+// selectUser only loads the public profile of a preloaded user.
+func selectUser(db *gorm.DB) *gorm.DB {
+	return db.Select("id", "name", "email")
+}
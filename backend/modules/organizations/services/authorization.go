@@ -0,0 +1,218 @@
+package services
+
+import (
+	"backend/modules/organizations/models"
+	models2 "backend/modules/passwords/models"
+	"errors"
+	"gorm.io/gorm"
+)
+
+const (
+	SourcePersonal     = "personal"
+	SourceShared       = "shared"
+	SourceOrganization = "organization"
+)
+
+// Operations checked by Authorize.
+const (
+	AccessRead   = "read"
+	AccessReveal = "reveal"
+	AccessWrite  = "write"
+	AccessManage = "manage"
+)
+
+var ErrAccessDenied = errors.New("you are not allowed to do this with this entry")
+
+// AuthorizationService resolves what a user can do with an entry, whether it is theirs,
+// shared with them or in a collection of one of their organizations.
+type AuthorizationService struct {
+	DB *gorm.DB
+}
+
+// Access is the effective permission of a user on an entry, the union of everything granted to them.
+type Access struct {
+	// OwnerID is the user whose vault holds the entry and whose key encrypts it.
+	OwnerID uint `json:"-"`
+	// Sources are where the access comes from: personal, shared or organization.
+	Sources []string `json:"sources"`
+	Read    bool     `json:"read"`
+	// Reveal allows reading the secrets of the entry, a view_without_reveal share does not.
+	Reveal bool `json:"reveal"`
+	Write  bool `json:"write"`
+	// Manage allows deleting, sharing and moving the entry, only its owner can.
+	Manage bool `json:"manage"`
+}
+
+// Allows reports whether the access includes an operation.
+//
+// operation: AccessRead, AccessReveal, AccessWrite or AccessManage.
+// Returns true if the operation is allowed.
+func (a Access) Allows(operation string) bool {
+	switch operation {
+	case AccessRead:
+		return a.Read
+	case AccessReveal:
+		return a.Reveal
+	case AccessWrite:
+		return a.Write
+	case AccessManage:
+		return a.Manage
+	default:
+		return false
+	}
+}
+
+// GetAccess returns the effective permission of a user on an entry.
+//
+// Parameters:
+// - userId: the ID of the user.
+// - passwordId: the ID of the entry.
+//
+// Returns:
+// - Access: the permission.
+// - error: gorm.ErrRecordNotFound if the entry does not exist, is in the trash or is not accessible to the user.
+func (s *AuthorizationService) GetAccess(userId, passwordId uint) (Access, error) {
+	var password models2.Password
+
+	err := s.DB.Select("id", "user_id").Where("id = ?", passwordId).First(&password).Error
+	if err != nil {
+		return Access{}, err
+	}
+
+	access := Access{OwnerID: password.UserID, Sources: []string{}}
+
+	if password.UserID == userId {
+		access.Sources = append(access.Sources, SourcePersonal)
+		access.Read, access.Reveal, access.Write, access.Manage = true, true, true, true
+	}
+
+	var share models2.PasswordShare
+
+	err = s.DB.Select("id", "permission").Where("password_id = ? AND recipient_id = ?", passwordId, userId).Limit(1).Find(&share).Error
+	if err != nil {
+		return Access{}, err
+	}
+
+	if share.ID != 0 {
+		access.Sources = append(access.Sources, SourceShared)
+		access.Read = true
+		access.Reveal = access.Reveal || share.Permission != models2.PermissionViewWithoutReveal
+		access.Write = access.Write || share.Permission == models2.PermissionEdit
+	}
+
+	collectionModel := models.CollectionModel{DB: s.DB}
+
+	collections, err := collectionModel.GetPasswordCollections(passwordId)
+	if err != nil {
+		return Access{}, err
+	}
+
+	organization := false
+
+	for _, collection := range collections {
+		permission, err := s.getCollectionPermission(collection, userId)
+		if err != nil {
+			return Access{}, err
+		}
+
+		if permission == "" {
+			continue
+		}
+
+		organization = true
+		access.Read, access.Reveal = true, true
+		access.Write = access.Write || permission == models.PermissionWrite
+	}
+
+	if organization {
+		access.Sources = append(access.Sources, SourceOrganization)
+	}
+
+	if !access.Read {
+		return Access{}, gorm.ErrRecordNotFound
+	}
+
+	return access, nil
+}
+
+// Authorize checks that a user may do an operation on an entry.
+//
+// Parameters:
+// - userId: the ID of the user.
+// - passwordId: the ID of the entry.
+// - operation: AccessRead, AccessReveal, AccessWrite or AccessManage.
+//
+// Returns:
+// - Access: the effective permission of the user.
+// - error: gorm.ErrRecordNotFound if the entry is not accessible at all, or ErrAccessDenied.
+func (s *AuthorizationService) Authorize(userId, passwordId uint, operation string) (Access, error) {
+	access, err := s.GetAccess(userId, passwordId)
+	if err != nil {
+		return Access{}, err
+	}
+
+	if !access.Allows(operation) {
+		return access, ErrAccessDenied
+	}
+
+	return access, nil
+}
+
+// GetCollectionPermission returns the permission of a user on a collection.
+//
+// Owners and admins can write to every collection of their organization, the other members
+// to the collections granted to them or to one of their groups.
+//
+// Parameters:
+// - organizationId: the ID of the organization.
+// - collectionId: the ID of the collection.
+// - userId: the ID of the user.
+//
+// Returns:
+// - string: models.PermissionRead or models.PermissionWrite.
+// - error: gorm.ErrRecordNotFound if the collection does not exist or is not accessible to the user.
+func (s *AuthorizationService) GetCollectionPermission(organizationId, collectionId, userId uint) (string, error) {
+	collectionModel := models.CollectionModel{DB: s.DB}
+
+	collection, err := collectionModel.Get(collectionId, organizationId)
+	if err != nil {
+		return "", err
+	}
+
+	permission, err := s.getCollectionPermission(collection, userId)
+	if err != nil {
+		return "", err
+	}
+
+	if permission == "" {
+		return "", gorm.ErrRecordNotFound
+	}
+
+	return permission, nil
+}
+
+// getCollectionPermission returns the permission of a user on a collection, empty without access.
+func (s *AuthorizationService) getCollectionPermission(collection models.Collection, userId uint) (string, error) {
+	organizationModel := models.OrganizationModel{DB: s.DB}
+
+	member, err := organizationModel.GetMember(collection.OrganizationID, userId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if roleRanks[member.Role] >= roleRanks[models.RoleAdmin] {
+		return models.PermissionWrite, nil
+	}
+
+	collectionModel := models.CollectionModel{DB: s.DB}
+
+	grants, err := collectionModel.GetGrants(userId, s.DB.Model(&models.Collection{}).Select("id").Where("id = ?", collection.ID))
+	if err != nil {
+		return "", err
+	}
+
+	return grants[collection.ID], nil
+}
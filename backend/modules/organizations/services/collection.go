@@ -0,0 +1,402 @@
+package services
+
+import (
+	"backend/modules/organizations/models"
+	models2 "backend/modules/passwords/models"
+	"errors"
+)
+
+var ErrClientEncrypted = errors.New("client-encrypted entries cannot be added to a collection")
+
+type Collection struct {
+	ID         uint   `json:"id"`
+	Name       string `json:"name"`
+	Permission string `json:"permission"`
+}
+
+type Grants struct {
+	Members []MemberGrant `json:"members"`
+	Groups  []GroupGrant  `json:"groups"`
+}
+
+type MemberGrant struct {
+	MemberID   uint   `json:"member_id"`
+	Email      string `json:"email"`
+	Permission string `json:"permission"`
+}
+
+type GroupGrant struct {
+	GroupID    uint   `json:"group_id"`
+	Name       string `json:"name"`
+	Permission string `json:"permission"`
+}
+
+// getCollectionModel returns a CollectionModel.
+func (s *OrganizationService) getCollectionModel() models.CollectionModel {
+	return models.CollectionModel{DB: s.DB}
+}
+
+// GetCollections returns the collections of an organization a user can access.
+//
+// Owners and admins see every collection with the write permission.
+//
+// Parameters:
+// - id: the ID of the organization.
+// - userId: the ID of the user, who must be a member.
+//
+// Returns:
+// - []Collection: the collections with the permission of the user, ordered by name.
+// - error: gorm.ErrRecordNotFound if the user is not a member, or any retrieval error.
+func (s *OrganizationService) GetCollections(id, userId uint) ([]Collection, error) {
+	member, err := s.getMember(id, userId, models.RoleMember)
+	if err != nil {
+		return nil, err
+	}
+
+	collectionModel := s.getCollectionModel()
+
+	collections, err := collectionModel.GetAll(id)
+	if err != nil {
+		return nil, err
+	}
+
+	admin := roleRanks[member.Role] >= roleRanks[models.RoleAdmin]
+
+	var grants map[uint]string
+	if !admin {
+		grants, err = collectionModel.GetGrants(userId, s.DB.Model(&models.Collection{}).Select("id").Where("organization_id = ?", id))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	collectionsList := []Collection{}
+
+	for _, collection := range collections {
+		permission := models.PermissionWrite
+		if !admin {
+			permission = grants[collection.ID]
+		}
+
+		if permission != "" {
+			collectionsList = append(collectionsList, Collection{ID: collection.ID, Name: collection.Name, Permission: permission})
+		}
+	}
+
+	return collectionsList, nil
+}
+
+// CreateCollection creates a collection, managers, admins and owners only.
+//
+// A manager gets the write permission on the collections they create.
+//
+// Parameters:
+// - id: the ID of the organization.
+// - userId: the ID of the user.
+// - name: the name of the collection.
+//
+// Returns:
+// - Collection: the created collection.
+// - error: gorm.ErrRecordNotFound, ErrForbidden or any creation error.
+func (s *OrganizationService) CreateCollection(id, userId uint, name string) (Collection, error) {
+	member, err := s.getMember(id, userId, models.RoleManager)
+	if err != nil {
+		return Collection{}, err
+	}
+
+	var grantee uint
+	if member.Role == models.RoleManager {
+		grantee = member.ID
+	}
+
+	collectionModel := s.getCollectionModel()
+
+	collection, err := collectionModel.Create(models.Collection{OrganizationID: id, Name: name}, grantee)
+	if err != nil {
+		return Collection{}, err
+	}
+
+	return Collection{ID: collection.ID, Name: collection.Name, Permission: models.PermissionWrite}, nil
+}
+
+// UpdateCollection renames a collection.
+//
+// Parameters:
+// - id: the ID of the organization.
+// - collectionId: the ID of the collection.
+// - userId: the ID of the user, who must manage the collection.
+// - name: the new name.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound, ErrForbidden or any update error.
+func (s *OrganizationService) UpdateCollection(id, collectionId, userId uint, name string) error {
+	if err := s.checkManager(id, collectionId, userId); err != nil {
+		return err
+	}
+
+	collectionModel := s.getCollectionModel()
+
+	return collectionModel.Update(collectionId, id, name)
+}
+
+// DeleteCollection deletes a collection, its entries stay in the vaults of their owners.
+//
+// Parameters:
+// - id: the ID of the organization.
+// - collectionId: the ID of the collection.
+// - userId: the ID of the user, who must manage the collection.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound, ErrForbidden or any deletion error.
+func (s *OrganizationService) DeleteCollection(id, collectionId, userId uint) error {
+	if err := s.checkManager(id, collectionId, userId); err != nil {
+		return err
+	}
+
+	collectionModel := s.getCollectionModel()
+
+	return collectionModel.Delete(collectionId, id)
+}
+
+// GetGrants returns the members and groups a collection is granted to.
+//
+// Parameters:
+// - id: the ID of the organization.
+// - collectionId: the ID of the collection.
+// - userId: the ID of the user, who must manage the collection.
+//
+// Returns:
+// - Grants: the grants.
+// - error: gorm.ErrRecordNotFound, ErrForbidden or any retrieval error.
+func (s *OrganizationService) GetGrants(id, collectionId, userId uint) (Grants, error) {
+	if err := s.checkManager(id, collectionId, userId); err != nil {
+		return Grants{}, err
+	}
+
+	collectionModel := s.getCollectionModel()
+
+	members, err := collectionModel.GetMemberGrants(collectionId)
+	if err != nil {
+		return Grants{}, err
+	}
+
+	groups, err := collectionModel.GetGroupGrants(collectionId)
+	if err != nil {
+		return Grants{}, err
+	}
+
+	grants := Grants{Members: []MemberGrant{}, Groups: []GroupGrant{}}
+
+	for _, grant := range members {
+		grants.Members = append(grants.Members, MemberGrant{
+			MemberID:   grant.OrganizationMemberID,
+			Email:      grant.Member.Email,
+			Permission: grant.Permission,
+		})
+	}
+
+	for _, grant := range groups {
+		grants.Groups = append(grants.Groups, GroupGrant{
+			GroupID:    grant.OrganizationGroupID,
+			Name:       grant.Group.Name,
+			Permission: grant.Permission,
+		})
+	}
+
+	return grants, nil
+}
+
+// GrantMember grants a collection to a member with the read or write permission.
+//
+// Parameters:
+// - id: the ID of the organization.
+// - collectionId: the ID of the collection.
+// - userId: the ID of the user, who must manage the collection.
+// - memberId: the ID of the member.
+// - permission: models.PermissionRead or models.PermissionWrite.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound, ErrForbidden, ErrMemberNotFound or any update error.
+func (s *OrganizationService) GrantMember(id, collectionId, userId, memberId uint, permission string) error {
+	if err := s.checkManager(id, collectionId, userId); err != nil {
+		return err
+	}
+
+	if _, err := s.checkMembers(id, []uint{memberId}); err != nil {
+		return err
+	}
+
+	collectionModel := s.getCollectionModel()
+
+	return collectionModel.GrantMember(models.CollectionMember{CollectionID: collectionId, OrganizationMemberID: memberId, Permission: permission})
+}
+
+// RevokeMember removes the grant of a collection to a member.
+//
+// Parameters:
+// - id: the ID of the organization.
+// - collectionId: the ID of the collection.
+// - userId: the ID of the user, who must manage the collection.
+// - memberId: the ID of the member.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound, ErrForbidden or any deletion error.
+func (s *OrganizationService) RevokeMember(id, collectionId, userId, memberId uint) error {
+	if err := s.checkManager(id, collectionId, userId); err != nil {
+		return err
+	}
+
+	collectionModel := s.getCollectionModel()
+
+	return collectionModel.RevokeMember(collectionId, memberId)
+}
+
+// GrantGroup grants a collection to a group with the read or write permission.
+//
+// Parameters:
+// - id: the ID of the organization.
+// - collectionId: the ID of the collection.
+// - userId: the ID of the user, who must manage the collection.
+// - groupId: the ID of the group.
+// - permission: models.PermissionRead or models.PermissionWrite.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound, ErrForbidden or any update error.
+func (s *OrganizationService) GrantGroup(id, collectionId, userId, groupId uint, permission string) error {
+	if err := s.checkManager(id, collectionId, userId); err != nil {
+		return err
+	}
+
+	groupModel := s.getGroupModel()
+
+	if _, err := groupModel.Get(groupId, id); err != nil {
+		return err
+	}
+
+	collectionModel := s.getCollectionModel()
+
+	return collectionModel.GrantGroup(models.CollectionGroup{CollectionID: collectionId, OrganizationGroupID: groupId, Permission: permission})
+}
+
+// RevokeGroup removes the grant of a collection to a group.
+//
+// Parameters:
+// - id: the ID of the organization.
+// - collectionId: the ID of the collection.
+// - userId: the ID of the user, who must manage the collection.
+// - groupId: the ID of the group.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound, ErrForbidden or any deletion error.
+func (s *OrganizationService) RevokeGroup(id, collectionId, userId, groupId uint) error {
+	if err := s.checkManager(id, collectionId, userId); err != nil {
+		return err
+	}
+
+	collectionModel := s.getCollectionModel()
+
+	return collectionModel.RevokeGroup(collectionId, groupId)
+}
+
+// AddPassword adds an entry of a user to a collection they can write to.
+//
+// The entry stays in the vault of the user, encrypted with their key, and the members
+// of the collection read it through the organization.
+//
+// Parameters:
+// - id: the ID of the organization.
+// - collectionId: the ID of the collection.
+// - userId: the ID of the user.
+// - passwordId: the ID of the entry, owned by the user.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound, ErrForbidden, ErrClientEncrypted or any update error.
+func (s *OrganizationService) AddPassword(id, collectionId, userId, passwordId uint) error {
+	if err := s.checkWriter(id, collectionId, userId); err != nil {
+		return err
+	}
+
+	var password models2.Password
+
+	err := s.DB.Select("id", "encryption").Where("id = ? AND user_id = ?", passwordId, userId).First(&password).Error
+	if err != nil {
+		return err
+	}
+
+	if password.Encryption == models2.EncryptionClient {
+		return ErrClientEncrypted
+	}
+
+	collectionModel := s.getCollectionModel()
+
+	return collectionModel.AddPassword(collectionId, passwordId)
+}
+
+// RemovePassword removes an entry from a collection, the entry itself is kept.
+//
+// Members who can write to the collection and the owner of the entry can remove it.
+//
+// Parameters:
+// - id: the ID of the organization.
+// - collectionId: the ID of the collection.
+// - userId: the ID of the user.
+// - passwordId: the ID of the entry.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound, ErrForbidden or any deletion error.
+func (s *OrganizationService) RemovePassword(id, collectionId, userId, passwordId uint) error {
+	var owned int64
+
+	err := s.DB.Model(&models2.Password{}).Unscoped().Where("id = ? AND user_id = ?", passwordId, userId).Count(&owned).Error
+	if err != nil {
+		return err
+	}
+
+	collectionModel := s.getCollectionModel()
+
+	if owned == 0 {
+		err = s.checkWriter(id, collectionId, userId)
+	} else {
+		_, err = collectionModel.Get(collectionId, id)
+	}
+	if err != nil {
+		return err
+	}
+
+	return collectionModel.RemovePassword(collectionId, passwordId)
+}
+
+// checkManager makes sure a user manages a collection: owners and admins manage every collection,
+// managers those they can write to.
+func (s *OrganizationService) checkManager(id, collectionId, userId uint) error {
+	member, err := s.getMember(id, userId, models.RoleManager)
+	if err != nil {
+		return err
+	}
+
+	if member.Role == models.RoleManager {
+		return s.checkWriter(id, collectionId, userId)
+	}
+
+	collectionModel := s.getCollectionModel()
+
+	_, err = collectionModel.Get(collectionId, id)
+
+	return err
+}
+
+// checkWriter makes sure a user has the write permission on a collection.
+func (s *OrganizationService) checkWriter(id, collectionId, userId uint) error {
+	authorizationService := AuthorizationService{DB: s.DB}
+
+	permission, err := authorizationService.GetCollectionPermission(id, collectionId, userId)
+	if err != nil {
+		return err
+	}
+
+	if permission != models.PermissionWrite {
+		return ErrForbidden
+	}
+
+	return nil
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"backend/modules/organizations/models"
+	"errors"
+)
+
+var ErrMemberNotFound = errors.New("member not found in the organization")
+
+type Group struct {
+	ID        uint   `json:"id"`
+	Name      string `json:"name"`
+	MemberIDs []uint `json:"member_ids"`
+}
+
+// getGroupModel returns a GroupModel.
+func (s *OrganizationService) getGroupModel() models.GroupModel {
+	return models.GroupModel{DB: s.DB}
+}
+
+// GetGroups returns the groups of an organization.
+//
+// Parameters:
+// - id: the ID of the organization.
+// - userId: the ID of the user, who must be a member.
+//
+// Returns:
+// - []Group: the groups ordered by name.
+// - error: gorm.ErrRecordNotFound if the user is not a member, or any retrieval error.
+func (s *OrganizationService) GetGroups(id, userId uint) ([]Group, error) {
+	if _, err := s.getMember(id, userId, models.RoleMember); err != nil {
+		return nil, err
+	}
+
+	groupModel := s.getGroupModel()
+
+	groups, err := groupModel.GetAll(id)
+
+	groupsList := []Group{}
+
+	for _, group := range groups {
+		groupsList = append(groupsList, toGroup(group))
+	}
+
+	return groupsList, err
+}
+
+// CreateGroup creates a group of members, admins and owners only.
+//
+// Parameters:
+// - id: the ID of the organization.
+// - userId: the ID of the user.
+// - name: the name of the group.
+// - memberIds: the members of the group.
+//
+// Returns:
+// - Group: the created group.
+// - error: gorm.ErrRecordNotFound, ErrForbidden, ErrMemberNotFound or any creation error.
+func (s *OrganizationService) CreateGroup(id, userId uint, name string, memberIds []uint) (Group, error) {
+	if _, err := s.getMember(id, userId, models.RoleAdmin); err != nil {
+		return Group{}, err
+	}
+
+	memberIds, err := s.checkMembers(id, memberIds)
+	if err != nil {
+		return Group{}, err
+	}
+
+	groupModel := s.getGroupModel()
+
+	group, err := groupModel.Create(models.OrganizationGroup{OrganizationID: id, Name: name, MemberIDs: memberIds})
+	if err != nil {
+		return Group{}, err
+	}
+
+	return Group{ID: group.ID, Name: group.Name, MemberIDs: memberIds}, nil
+}
+
+// UpdateGroup renames a group and replaces its members, admins and owners only.
+//
+// Parameters:
+// - id: the ID of the organization.
+// - groupId: the ID of the group.
+// - userId: the ID of the user.
+// - name: the new name of the group.
+// - memberIds: the new members of the group.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound, ErrForbidden, ErrMemberNotFound or any update error.
+func (s *OrganizationService) UpdateGroup(id, groupId, userId uint, name string, memberIds []uint) error {
+	if _, err := s.getMember(id, userId, models.RoleAdmin); err != nil {
+		return err
+	}
+
+	memberIds, err := s.checkMembers(id, memberIds)
+	if err != nil {
+		return err
+	}
+
+	groupModel := s.getGroupModel()
+
+	return groupModel.Update(groupId, id, models.OrganizationGroup{Name: name, MemberIDs: memberIds})
+}
+
+// DeleteGroup deletes a group, admins and owners only.
+//
+// Parameters:
+// - id: the ID of the organization.
+// - groupId: the ID of the group.
+// - userId: the ID of the user.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound, ErrForbidden or any deletion error.
+func (s *OrganizationService) DeleteGroup(id, groupId, userId uint) error {
+	if _, err := s.getMember(id, userId, models.RoleAdmin); err != nil {
+		return err
+	}
+
+	groupModel := s.getGroupModel()
+
+	return groupModel.Delete(groupId, id)
+}
+
+// checkMembers makes sure the members belong to the organization.
+//
+// It returns the IDs without duplicates, or ErrMemberNotFound when one is missing.
+func (s *OrganizationService) checkMembers(id uint, memberIds []uint) ([]uint, error) {
+	unique := []uint{}
+	seen := map[uint]bool{}
+
+	for _, memberId := range memberIds {
+		if !seen[memberId] {
+			seen[memberId] = true
+			unique = append(unique, memberId)
+		}
+	}
+
+	if len(unique) == 0 {
+		return unique, nil
+	}
+
+	groupModel := s.getGroupModel()
+
+	count, err := groupModel.CountMembers(unique, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if count != int64(len(unique)) {
+		return nil, ErrMemberNotFound
+	}
+
+	return unique, nil
+}
+
+// toGroup converts a group model to its response representation.
+func toGroup(group models.OrganizationGroup) Group {
+	memberIds := []uint{}
+	for _, member := range group.Members {
+		memberIds = append(memberIds, member.ID)
+	}
+
+	return Group{ID: group.ID, Name: group.Name, MemberIDs: memberIds}
+}
@@ -0,0 +1,474 @@
+package services
+
+import (
+	models3 "backend/modules/notifications/models"
+	services2 "backend/modules/notifications/services"
+	"backend/modules/organizations/models"
+	models2 "backend/modules/users/models"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"time"
+)
+
+const (
+	StatusInvited  = "invited"
+	StatusAccepted = "accepted"
+)
+
+var (
+	ErrForbidden    = errors.New("your role in the organization does not allow this operation")
+	ErrMemberExists = errors.New("this email is already a member of the organization or invited")
+	ErrLastOwner    = errors.New("an organization needs at least one owner")
+)
+
+// roleRanks orders the roles, a role can do everything the roles below it can.
+var roleRanks = map[string]int{
+	models.RoleMember:  1,
+	models.RoleManager: 2,
+	models.RoleAdmin:   3,
+	models.RoleOwner:   4,
+}
+
+type OrganizationService struct {
+	DB *gorm.DB
+}
+
+type Organization struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Member struct {
+	ID         uint       `json:"id"`
+	UserID     *uint      `json:"user_id"`
+	Email      string     `json:"email"`
+	Name       string     `json:"name"`
+	Role       string     `json:"role"`
+	Status     string     `json:"status"`
+	InvitedBy  string     `json:"invited_by"`
+	AcceptedAt *time.Time `json:"accepted_at"`
+}
+
+type Invitation struct {
+	ID             uint      `json:"id"`
+	OrganizationID uint      `json:"organization_id"`
+	Organization   string    `json:"organization"`
+	Role           string    `json:"role"`
+	InvitedBy      string    `json:"invited_by"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// getModel returns an OrganizationModel.
+//
+// No parameters.
+// Returns a models.OrganizationModel.
+func (s *OrganizationService) getModel() models.OrganizationModel {
+	return models.OrganizationModel{DB: s.DB}
+}
+
+// GetOrganizations returns the organizations a user is a member of.
+//
+// userId: the ID of the user.
+// Returns the organizations with the role of the user, and any retrieval error.
+func (s *OrganizationService) GetOrganizations(userId uint) ([]Organization, error) {
+	organizationModel := s.getModel()
+
+	members, err := organizationModel.GetAll(userId)
+
+	organizationsList := []Organization{}
+
+	for _, member := range members {
+		organizationsList = append(organizationsList, toOrganization(member))
+	}
+
+	return organizationsList, err
+}
+
+// CreateOrganization creates an organization owned by a user.
+//
+// Parameters:
+// - userId: the ID of the user.
+// - name: the name of the organization.
+//
+// Returns:
+// - Organization: the created organization.
+// - error: any error that occurred during the creation process.
+func (s *OrganizationService) CreateOrganization(userId uint, name string) (Organization, error) {
+	user, err := s.getUser(userId)
+	if err != nil {
+		return Organization{}, err
+	}
+
+	organizationModel := s.getModel()
+
+	member, err := organizationModel.Create(name, userId, user.Email)
+	if err != nil {
+		return Organization{}, err
+	}
+
+	return toOrganization(member), nil
+}
+
+// UpdateOrganization renames an organization, admins and owners only.
+//
+// Parameters:
+// - id: the ID of the organization.
+// - userId: the ID of the user.
+// - name: the new name.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound if the user is not a member, ErrForbidden, or any update error.
+func (s *OrganizationService) UpdateOrganization(id, userId uint, name string) error {
+	if _, err := s.getMember(id, userId, models.RoleAdmin); err != nil {
+		return err
+	}
+
+	organizationModel := s.getModel()
+
+	return organizationModel.Update(id, name)
+}
+
+// DeleteOrganization deletes an organization, owners only.
+//
+// The entries of its collections are not deleted, they stay in the vaults of the members who added them.
+//
+// Parameters:
+// - id: the ID of the organization.
+// - userId: the ID of the user.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound if the user is not a member, ErrForbidden, or any deletion error.
+func (s *OrganizationService) DeleteOrganization(id, userId uint) error {
+	if _, err := s.getMember(id, userId, models.RoleOwner); err != nil {
+		return err
+	}
+
+	organizationModel := s.getModel()
+
+	return organizationModel.Delete(id)
+}
+
+// GetMembers returns the members and pending invitations of an organization.
+//
+// Parameters:
+// - id: the ID of the organization.
+// - userId: the ID of the user, who must be a member.
+//
+// Returns:
+// - []Member: the members ordered by email.
+// - error: gorm.ErrRecordNotFound if the user is not a member, or any retrieval error.
+func (s *OrganizationService) GetMembers(id, userId uint) ([]Member, error) {
+	if _, err := s.getMember(id, userId, models.RoleMember); err != nil {
+		return nil, err
+	}
+
+	organizationModel := s.getModel()
+
+	members, err := organizationModel.GetMembers(id)
+
+	membersList := []Member{}
+
+	for _, member := range members {
+		membersList = append(membersList, toMember(member))
+	}
+
+	return membersList, err
+}
+
+// InviteMember invites an email to an organization, admins and owners only.
+//
+// Only owners can invite owners. The invitee gets a notification if the email belongs to a user,
+// otherwise the invitation waits until someone signs up with it.
+//
+// Parameters:
+// - id: the ID of the organization.
+// - userId: the ID of the user.
+// - email: the email to invite.
+// - role: the role of the invitee.
+//
+// Returns:
+// - Member: the invitation.
+// - error: gorm.ErrRecordNotFound if the user is not a member, ErrForbidden, ErrMemberExists, or any creation error.
+func (s *OrganizationService) InviteMember(id, userId uint, email, role string) (Member, error) {
+	inviter, err := s.getMember(id, userId, models.RoleAdmin)
+	if err != nil {
+		return Member{}, err
+	}
+
+	if role == models.RoleOwner && inviter.Role != models.RoleOwner {
+		return Member{}, ErrForbidden
+	}
+
+	invitation := models.OrganizationMember{OrganizationID: id, Email: email, Role: role, InvitedBy: inviter.Email}
+
+	var invitee models2.User
+
+	err = s.DB.Select("id").Where("email = ?", email).Limit(1).Find(&invitee).Error
+	if err != nil {
+		return Member{}, err
+	}
+
+	if invitee.ID != 0 {
+		invitation.UserID = &invitee.ID
+	}
+
+	organizationModel := s.getModel()
+
+	invitation, created, err := organizationModel.Invite(invitation)
+	if err != nil {
+		return Member{}, err
+	}
+
+	if !created {
+		return Member{}, ErrMemberExists
+	}
+
+	if invitee.ID != 0 {
+		notificationService := services2.NotificationService{DB: s.DB}
+
+		message := fmt.Sprintf("%s invited you to join %q as %s", inviter.Email, inviter.Organization.Name, role)
+
+		if err := notificationService.Notify(invitee.ID, models3.TypeOrganizationInvite, message); err != nil {
+			return Member{}, err
+		}
+	}
+
+	return toMember(invitation), nil
+}
+
+// GetInvitations returns the pending invitations of a user.
+//
+// userId: the ID of the user.
+// Returns the invitations, newest first, and any retrieval error.
+func (s *OrganizationService) GetInvitations(userId uint) ([]Invitation, error) {
+	user, err := s.getUser(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	organizationModel := s.getModel()
+
+	members, err := organizationModel.GetInvitations(userId, user.Email)
+
+	invitationsList := []Invitation{}
+
+	for _, member := range members {
+		invitationsList = append(invitationsList, Invitation{
+			ID:             member.ID,
+			OrganizationID: member.OrganizationID,
+			Organization:   member.Organization.Name,
+			Role:           member.Role,
+			InvitedBy:      member.InvitedBy,
+			CreatedAt:      member.CreatedAt,
+		})
+	}
+
+	return invitationsList, err
+}
+
+// AcceptInvitation makes a user a member of the organization that invited them.
+//
+// Parameters:
+// - id: the ID of the invitation.
+// - userId: the ID of the user.
+//
+// Returns:
+// - Organization: the joined organization.
+// - error: gorm.ErrRecordNotFound if the invitation is not addressed to the user, or any update error.
+func (s *OrganizationService) AcceptInvitation(id, userId uint) (Organization, error) {
+	user, err := s.getUser(userId)
+	if err != nil {
+		return Organization{}, err
+	}
+
+	organizationModel := s.getModel()
+
+	member, err := organizationModel.Accept(id, userId, user.Email)
+	if err != nil {
+		return Organization{}, err
+	}
+
+	return toOrganization(member), nil
+}
+
+// DeclineInvitation deletes an invitation addressed to a user.
+//
+// Parameters:
+// - id: the ID of the invitation.
+// - userId: the ID of the user.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound if the invitation is not addressed to the user, or any deletion error.
+func (s *OrganizationService) DeclineInvitation(id, userId uint) error {
+	user, err := s.getUser(userId)
+	if err != nil {
+		return err
+	}
+
+	organizationModel := s.getModel()
+
+	return organizationModel.Decline(id, userId, user.Email)
+}
+
+// UpdateMember changes the role of a member, admins and owners only.
+//
+// Only owners can make or change owners, and the last owner keeps their role.
+//
+// Parameters:
+// - id: the ID of the organization.
+// - memberId: the ID of the member.
+// - userId: the ID of the user.
+// - role: the new role.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound, ErrForbidden, ErrLastOwner or any update error.
+func (s *OrganizationService) UpdateMember(id, memberId, userId uint, role string) error {
+	manager, err := s.getMember(id, userId, models.RoleAdmin)
+	if err != nil {
+		return err
+	}
+
+	organizationModel := s.getModel()
+
+	member, err := organizationModel.GetMemberByID(memberId, id)
+	if err != nil {
+		return err
+	}
+
+	if (member.Role == models.RoleOwner || role == models.RoleOwner) && manager.Role != models.RoleOwner {
+		return ErrForbidden
+	}
+
+	if member.Role == models.RoleOwner && role != models.RoleOwner {
+		if err := s.checkLastOwner(member); err != nil {
+			return err
+		}
+	}
+
+	return organizationModel.UpdateRole(memberId, id, role)
+}
+
+// RemoveMember removes a member or cancels an invitation.
+//
+// Admins and owners can remove members, owners being removed by owners only,
+// and every member can leave. The last owner cannot leave.
+//
+// Parameters:
+// - id: the ID of the organization.
+// - memberId: the ID of the member.
+// - userId: the ID of the user.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound, ErrForbidden, ErrLastOwner or any deletion error.
+func (s *OrganizationService) RemoveMember(id, memberId, userId uint) error {
+	current, err := s.getMember(id, userId, models.RoleMember)
+	if err != nil {
+		return err
+	}
+
+	organizationModel := s.getModel()
+
+	member, err := organizationModel.GetMemberByID(memberId, id)
+	if err != nil {
+		return err
+	}
+
+	if member.ID != current.ID && (roleRanks[current.Role] < roleRanks[models.RoleAdmin] ||
+		member.Role == models.RoleOwner && current.Role != models.RoleOwner) {
+		return ErrForbidden
+	}
+
+	if member.Role == models.RoleOwner {
+		if err := s.checkLastOwner(member); err != nil {
+			return err
+		}
+	}
+
+	return organizationModel.RemoveMember(memberId, id)
+}
+
+// getMember returns the membership of a user, who must hold at least the given role.
+//
+// Parameters:
+// - id: the ID of the organization.
+// - userId: the ID of the user.
+// - role: the lowest role allowed.
+//
+// Returns:
+// - models.OrganizationMember: the membership with its organization.
+// - error: gorm.ErrRecordNotFound if the user is not a member, or ErrForbidden if their role is lower.
+func (s *OrganizationService) getMember(id, userId uint, role string) (models.OrganizationMember, error) {
+	organizationModel := s.getModel()
+
+	member, err := organizationModel.GetMember(id, userId)
+	if err != nil {
+		return models.OrganizationMember{}, err
+	}
+
+	if roleRanks[member.Role] < roleRanks[role] {
+		return models.OrganizationMember{}, ErrForbidden
+	}
+
+	return member, nil
+}
+
+// checkLastOwner returns ErrLastOwner if an accepted owner is the only one of their organization.
+func (s *OrganizationService) checkLastOwner(member models.OrganizationMember) error {
+	if member.AcceptedAt == nil {
+		return nil
+	}
+
+	organizationModel := s.getModel()
+
+	owners, err := organizationModel.CountOwners(member.OrganizationID)
+	if err != nil {
+		return err
+	}
+
+	if owners <= 1 {
+		return ErrLastOwner
+	}
+
+	return nil
+}
+
+// getUser returns the email of a user.
+func (s *OrganizationService) getUser(userId uint) (models2.User, error) {
+	var user models2.User
+
+	err := s.DB.Select("id", "email").Where("id = ?", userId).First(&user).Error
+
+	return user, err
+}
+
+// toOrganization converts a membership to the response representation of its organization.
+func toOrganization(member models.OrganizationMember) Organization {
+	return Organization{
+		ID:        member.Organization.ID,
+		Name:      member.Organization.Name,
+		Role:      member.Role,
+		CreatedAt: member.Organization.CreatedAt,
+	}
+}
+
+// toMember converts a member model to its response representation.
+func toMember(member models.OrganizationMember) Member {
+	status := StatusInvited
+	if member.AcceptedAt != nil {
+		status = StatusAccepted
+	}
+
+	return Member{
+		ID:         member.ID,
+		UserID:     member.UserID,
+		Email:      member.Email,
+		Name:       member.User.Name,
+		Role:       member.Role,
+		Status:     status,
+		InvitedBy:  member.InvitedBy,
+		AcceptedAt: member.AcceptedAt,
+	}
+}
@@ -0,0 +1,159 @@
+package actions
+
+import (
+	services4 "backend/modules/audit/services"
+	"backend/modules/passwords/services"
+	"backend/modules/users/models"
+	services2 "backend/services"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"time"
+)
+
+type CopySecretRequest struct {
+	Secret string `json:"secret" binding:"required,oneof=password totp card_number card_cvv passport_number license_number national_id wifi_key note field"`
+	// Label of the custom field to copy with the field secret.
+	Label string `json:"label" binding:"required_if=Secret field"`
+}
+
+type CopySecretResponse struct {
+	Value string `json:"value"`
+}
+
+type GetAccessLogRequest struct {
+	Since *time.Time `form:"since"`
+	Until *time.Time `form:"until"`
+}
+
+// RevealPassword reveals the secrets of a password.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Reveal password
+// @Description Retrieves the password with the given ID with its secrets and records the access in its access log.
+// @Description The owner, users it is shared with and members of its collections can reveal it,
+// @Description unless their access is view_without_reveal.
+// @Tags Passwords
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Password ID"
+// @Success 200 {object} services.Password
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 403 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /password/{id}/reveal [post]
+func RevealPassword(c *gin.Context) {
+	var request PasswordRequestAndResponse
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	passwordService, user := getServiceAndUser(c)
+
+	password, err := passwordService.RevealPassword(request.ID, user.User.ID, getClient(c, user))
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, password)
+}
+
+// CopySecret returns a single secret of a password.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Copy secret
+// @Description Retrieves one secret of the password with the given ID and records the access in its access log.
+// @Description totp returns the current one-time code, field the value of the custom field with the given label.
+// @Tags Passwords
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Password ID"
+// @Param   secret     body    CopySecretRequest     true        "Secret"
+// @Success 200 {object} CopySecretResponse
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 403 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /password/{id}/copy [post]
+func CopySecret(c *gin.Context) {
+	var request PasswordRequestAndResponse
+	var json CopySecretRequest
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := c.ShouldBindJSON(&json); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	passwordService, user := getServiceAndUser(c)
+
+	value, err := passwordService.CopySecret(request.ID, user.User.ID, json.Secret, json.Label, getClient(c, user))
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, CopySecretResponse{Value: value})
+}
+
+// GetAccessLog retrieves who read the secrets of a password.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Get access log of password
+// @Description Retrieves the reveals and copies of the secrets of the password with the given ID, newest first,
+// @Description with the user, the access token and the IP address of each access. Only the owner can read it.
+// @Tags Passwords
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Password ID"
+// @Param   since    query    string                          false       "Only accesses at or after this RFC 3339 time"
+// @Param   until    query    string                          false       "Only accesses before this RFC 3339 time"
+// @Success 200 {array} services.AccessLogEntry
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /password/{id}/access/log [get]
+func GetAccessLog(c *gin.Context) {
+	var request PasswordRequestAndResponse
+	var query GetAccessLogRequest
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	passwordService, user := getServiceAndUser(c)
+
+	entries, err := passwordService.GetAccessLog(request.ID, user.User.ID, services.AccessLogFilter{
+		Since: query.Since,
+		Until: query.Until,
+	})
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+// getClient returns the token, IP address and user agent of the request for the access log.
+func getClient(c *gin.Context, user models.Token) services4.Client {
+	return services4.Client{TokenID: &user.ID, IP: c.ClientIP(), UserAgent: c.Request.UserAgent()}
+}
@@ -0,0 +1,180 @@
+package actions
+
+import (
+	"backend/modules/passwords/services"
+	services2 "backend/services"
+	"github.com/gin-gonic/gin"
+	"mime"
+	"net/http"
+)
+
+type AttachmentRequest struct {
+	ID           uint `uri:"id" binding:"required"`
+	AttachmentID uint `uri:"attachment" binding:"required"`
+}
+
+type UploadAttachmentRequest struct {
+	Name string `form:"name" binding:"required,max=255"`
+}
+
+// GetAttachments retrieves the attachments of a password.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Get list of attachments
+// @Description Retrieves the files attached to the password with the given ID
+// @Tags Attachments
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Password ID"
+// @Success 200 {array} services.Attachment
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /password/{id}/attachments [get]
+func GetAttachments(c *gin.Context) {
+	var request PasswordRequestAndResponse
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	passwordService, user := getServiceAndUser(c)
+
+	attachments, err := passwordService.GetAttachments(request.ID, user.User.ID)
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, attachments)
+}
+
+// UploadAttachment attaches a file to a password.
+//
+// The request body is the raw file content, it is encrypted and streamed to the blob storage
+// without being buffered, so the Content-Length header is required.
+// @Summary Upload attachment
+// @Description Attaches the file sent as the raw request body to the password with the given ID.
+// @Description The Content-Type header is stored as the type of the file.
+// @Tags Attachments
+// @Accept  octet-stream
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Password ID"
+// @Param   name     query    string                          true        "File name"
+// @Success 200 {object} services.Attachment
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 411 {object} services2.ErrorResponse
+// @Failure 413 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /password/{id}/attachments/upload [post]
+func UploadAttachment(c *gin.Context) {
+	var request PasswordRequestAndResponse
+	var query UploadAttachmentRequest
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if c.Request.ContentLength < 0 {
+		c.AbortWithStatusJSON(http.StatusLengthRequired, services2.ErrorResponse{Error: "Content-Length is required"})
+		return
+	}
+
+	passwordService, user := getServiceAndUser(c)
+
+	attachment, err := passwordService.UploadAttachment(c.Request.Context(), request.ID, user.User.ID, services.AttachmentUpload{
+		Name:        query.Name,
+		ContentType: c.ContentType(),
+		Size:        c.Request.ContentLength,
+		Content:     c.Request.Body,
+	})
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, attachment)
+}
+
+// DownloadAttachment streams the decrypted content of an attachment.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Download attachment
+// @Description Returns the decrypted file of the attachment with the given ID and records the download in the access log of the password
+// @Tags Attachments
+// @Produce  octet-stream
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Password ID"
+// @Param   attachment       path     int                             true        "Attachment ID"
+// @Success 200 {file} file
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /password/{id}/attachments/{attachment} [get]
+func DownloadAttachment(c *gin.Context) {
+	var request AttachmentRequest
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	passwordService, user := getServiceAndUser(c)
+
+	attachment, content, err := passwordService.DownloadAttachment(c.Request.Context(), request.AttachmentID, request.ID, user.User.ID, getClient(c, user))
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+	defer content.Close()
+
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, content, map[string]string{
+		"Content-Disposition":    mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Name}),
+		"X-Content-Type-Options": "nosniff",
+	})
+}
+
+// DeleteAttachment deletes an attachment.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Delete attachment
+// @Description Deletes the attachment with the given ID and its file
+// @Tags Attachments
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Password ID"
+// @Param   attachment       path     int                             true        "Attachment ID"
+// @Success 200 {object} PasswordRequestAndResponse
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /password/{id}/attachments/{attachment} [delete]
+func DeleteAttachment(c *gin.Context) {
+	var request AttachmentRequest
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	passwordService, user := getServiceAndUser(c)
+
+	err := passwordService.DeleteAttachment(request.AttachmentID, request.ID, user.User.ID)
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, PasswordRequestAndResponse{ID: request.AttachmentID})
+}
@@ -0,0 +1,229 @@
+package actions
+
+import (
+	"backend/modules/passwords/services"
+	services2 "backend/services"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type BulkRequest struct {
+	IDs []uint `json:"ids" binding:"required,min=1,max=500"`
+}
+
+type BulkMoveRequest struct {
+	IDs []uint `json:"ids" binding:"required,min=1,max=500"`
+	// Category to move the passwords to, null to remove their category.
+	CategoryID *uint `json:"category_id"`
+}
+
+type BulkTagRequest struct {
+	IDs    []uint `json:"ids" binding:"required,min=1,max=500"`
+	TagIDs []uint `json:"tag_ids" binding:"required,min=1,max=500"`
+}
+
+type BulkShareRequest struct {
+	IDs []uint `json:"ids" binding:"required,min=1,max=500"`
+	// Email of the user to share the passwords with.
+	Email string `json:"email" binding:"required,email"`
+	// view, edit or view_without_reveal to show the entries with their secrets masked.
+	Permission string `json:"permission" binding:"required,oneof=view edit view_without_reveal"`
+}
+
+type BulkResultResponse struct {
+	ID uint `json:"id"`
+	// HTTP status code the operation would have returned for this password alone.
+	Status int    `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// MovePasswords moves several passwords to a category.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Move passwords
+// @Description Moves the passwords with the given IDs to a category in a single transaction and returns the result of each password.
+// @Description Passwords that fail are left unchanged, the others are moved.
+// @Tags Passwords
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   passwords     body    BulkMoveRequest     true        "Passwords"
+// @Success 200 {array} BulkResultResponse
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /password/bulk/move [post]
+func MovePasswords(c *gin.Context) {
+	var json BulkMoveRequest
+
+	if err := c.ShouldBindJSON(&json); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	passwordService, user := getServiceAndUser(c)
+
+	results, err := passwordService.MovePasswords(json.IDs, user.User.ID, json.CategoryID)
+	bulkResponse(c, results, err)
+}
+
+// DeletePasswords moves several passwords to the trash.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Delete passwords
+// @Description Moves the passwords with the given IDs to the trash in a single transaction and returns the result of each password.
+// @Tags Passwords
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   passwords     body    BulkRequest     true        "Passwords"
+// @Success 200 {array} BulkResultResponse
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /password/bulk/delete [post]
+func DeletePasswords(c *gin.Context) {
+	var json BulkRequest
+
+	if err := c.ShouldBindJSON(&json); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	passwordService, user := getServiceAndUser(c)
+
+	results, err := passwordService.DeletePasswords(json.IDs, user.User.ID)
+	bulkResponse(c, results, err)
+}
+
+// RestorePasswords restores several passwords from the trash.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Restore passwords
+// @Description Restores the passwords with the given IDs from the trash in a single transaction and returns the result of each password.
+// @Description Deleted categories of the passwords are restored as well.
+// @Tags Passwords
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   passwords     body    BulkRequest     true        "Passwords"
+// @Success 200 {array} BulkResultResponse
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /password/bulk/restore [post]
+func RestorePasswords(c *gin.Context) {
+	var json BulkRequest
+
+	if err := c.ShouldBindJSON(&json); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	passwordService, user := getServiceAndUser(c)
+
+	results, err := passwordService.RestorePasswords(json.IDs, user.User.ID)
+	bulkResponse(c, results, err)
+}
+
+// TagPasswords adds tags to several passwords.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Tag passwords
+// @Description Adds the tags to the passwords with the given IDs in a single transaction and returns the result of each password.
+// @Tags Passwords
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   passwords     body    BulkTagRequest     true        "Passwords and tags"
+// @Success 200 {array} BulkResultResponse
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /password/bulk/tag [post]
+func TagPasswords(c *gin.Context) {
+	var json BulkTagRequest
+
+	if err := c.ShouldBindJSON(&json); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	passwordService, user := getServiceAndUser(c)
+
+	results, err := passwordService.TagPasswords(json.IDs, user.User.ID, json.TagIDs)
+	bulkResponse(c, results, err)
+}
+
+// UntagPasswords removes tags from several passwords.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Untag passwords
+// @Description Removes the tags from the passwords with the given IDs in a single transaction and returns the result of each password.
+// @Tags Passwords
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   passwords     body    BulkTagRequest     true        "Passwords and tags"
+// @Success 200 {array} BulkResultResponse
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /password/bulk/untag [post]
+func UntagPasswords(c *gin.Context) {
+	var json BulkTagRequest
+
+	if err := c.ShouldBindJSON(&json); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	passwordService, user := getServiceAndUser(c)
+
+	results, err := passwordService.UntagPasswords(json.IDs, user.User.ID, json.TagIDs)
+	bulkResponse(c, results, err)
+}
+
+// SharePasswords shares several passwords with another user.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Share passwords
+// @Description Shares the passwords with the given IDs with the user with the given email in a single transaction
+// @Description and returns the result of each password. Existing shares get the new permission.
+// @Tags Sharing
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   passwords     body    BulkShareRequest     true        "Passwords and recipient"
+// @Success 200 {array} BulkResultResponse
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /password/bulk/share [post]
+func SharePasswords(c *gin.Context) {
+	var json BulkShareRequest
+
+	if err := c.ShouldBindJSON(&json); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	passwordService, user := getServiceAndUser(c)
+
+	results, err := passwordService.SharePasswords(json.IDs, user.User.ID, json.Email, json.Permission)
+	bulkResponse(c, results, err)
+}
+
+// bulkResponse writes the results of a bulk operation, or the error that prevented it.
+func bulkResponse(c *gin.Context, results []services.BulkResult, err error) {
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	response := make([]BulkResultResponse, len(results))
+	for i, result := range results {
+		response[i] = BulkResultResponse{ID: result.ID, Status: http.StatusOK}
+
+		if result.Error != nil {
+			response[i].Status = errorStatus(result.Error)
+			response[i].Error = result.Error.Error()
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
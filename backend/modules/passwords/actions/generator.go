@@ -0,0 +1,199 @@
+package actions
+
+import (
+	"backend/modules/passwords/services"
+	"backend/modules/users/models"
+	services2 "backend/services"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type GeneratePasswordRequest struct {
+	PresetID *uint `json:"preset_id"`
+	services.GeneratorOptions
+}
+
+type CreateOrUpdateGeneratorPresetRequest struct {
+	Name string `json:"name" binding:"required"`
+	services.GeneratorOptions
+}
+
+// GeneratePassword generates a random password or a diceware-style passphrase.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Generate password
+// @Description Generates a password (length, character classes, minimum counts, ambiguous characters)
+// @Description or a passphrase (words, separator, capitalisation), optionally from a saved preset
+// @Tags Generator
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   options     body    GeneratePasswordRequest     true        "Generator options"
+// @Success 200 {object} services.GeneratedPassword
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /password/generate [post]
+func GeneratePassword(c *gin.Context) {
+	var request GeneratePasswordRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	generatorService, user := getGeneratorServiceAndUser(c)
+
+	password, err := generatorService.Generate(user.User.ID, request.PresetID, request.GeneratorOptions)
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, password)
+}
+
+// GetGeneratorPresets retrieves the generator presets of the logged-in user.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Get list of generator presets
+// @Description Retrieves the saved generator presets for the logged-in user
+// @Tags Generator
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Success 200 {array} services.GeneratorPreset
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /password/generator/presets [get]
+func GetGeneratorPresets(c *gin.Context) {
+	generatorService, user := getGeneratorServiceAndUser(c)
+
+	presets, err := generatorService.GetPresets(user.User.ID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, presets)
+}
+
+// CreateGeneratorPreset saves a named generator preset.
+//
+// It expects a gin.Context parameter to access the HTTP request and response.
+// It does not have any return values.
+// @Summary Create a generator preset
+// @Description Saves generator options under a name that is unique per user
+// @Tags Generator
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   preset     body    CreateOrUpdateGeneratorPresetRequest     true        "Generator preset"
+// @Success 200 {object} services.GeneratorPreset
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 409 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /password/generator/presets/create [post]
+func CreateGeneratorPreset(c *gin.Context) {
+	var request CreateOrUpdateGeneratorPresetRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	generatorService, user := getGeneratorServiceAndUser(c)
+
+	preset, err := generatorService.CreatePreset(user.User.ID, request.Name, request.GeneratorOptions)
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, preset)
+}
+
+// UpdateGeneratorPreset updates a generator preset.
+//
+// It binds the preset ID from the URI and the new name and options from the JSON body.
+// @Summary Update generator preset
+// @Description Updates the generator preset with the given ID
+// @Tags Generator
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Preset ID"
+// @Param   preset     body    CreateOrUpdateGeneratorPresetRequest     true        "Generator preset"
+// @Success 200 {object} PasswordRequestAndResponse
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 409 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /password/generator/presets/update/{id} [put]
+func UpdateGeneratorPreset(c *gin.Context) {
+	var request PasswordRequestAndResponse
+	var json CreateOrUpdateGeneratorPresetRequest
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := c.ShouldBindJSON(&json); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	generatorService, user := getGeneratorServiceAndUser(c)
+
+	id, err := generatorService.UpdatePreset(request.ID, user.User.ID, json.Name, json.GeneratorOptions)
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, PasswordRequestAndResponse{ID: id})
+}
+
+// DeleteGeneratorPreset deletes a generator preset.
+//
+// The function takes a gin.Context pointer as a parameter.
+// It returns nothing.
+// @Summary Delete generator preset
+// @Description Deletes the generator preset with the given ID
+// @Tags Generator
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Preset ID"
+// @Success 200 {object} PasswordRequestAndResponse
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /password/generator/presets/delete/{id} [delete]
+func DeleteGeneratorPreset(c *gin.Context) {
+	var request PasswordRequestAndResponse
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	generatorService, user := getGeneratorServiceAndUser(c)
+
+	err := generatorService.DeletePreset(request.ID, user.User.ID)
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, PasswordRequestAndResponse{ID: request.ID})
+}
+
+// getGeneratorServiceAndUser returns the generator service and user token.
+//
+// It takes a Gin context as a parameter.
+// It returns a GeneratorService and a Token.
+func getGeneratorServiceAndUser(c *gin.Context) (services.GeneratorService, models.Token) {
+	service := services.GeneratorService{DB: services2.GetDBConnection()}
+	user := services2.GetUserFromContext(c)
+
+	return service, user
+}
@@ -0,0 +1,286 @@
+package actions
+
+import (
+	"backend/modules/passwords/models"
+	"backend/modules/passwords/services"
+	services2 "backend/services"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// ItemRequest holds the values shared by every item type.
+type ItemRequest struct {
+	CategoryID *uint                  `json:"category_id"`
+	Name       string                 `json:"name" binding:"required"`
+	Fields     []PasswordFieldRequest `json:"fields" binding:"dive"`
+	TagIDs     []uint                 `json:"tag_ids"`
+	// The item must be rotated every rotation_days days, null for the interval of its category.
+	RotationDays *int `json:"rotation_days" binding:"omitempty,min=1,max=3650"`
+}
+
+type CreateOrUpdateNoteRequest struct {
+	ItemRequest
+	services.Note
+}
+
+type CreateOrUpdateCardRequest struct {
+	ItemRequest
+	services.Card
+}
+
+type CreateOrUpdateIdentityRequest struct {
+	ItemRequest
+	services.Identity
+}
+
+type CreateOrUpdateWifiRequest struct {
+	ItemRequest
+	services.Wifi
+}
+
+// itemRequest is a create or update request body of an item type.
+type itemRequest interface {
+	toData() services.PasswordData
+}
+
+// CreateNote creates a secure note.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Create secure note
+// @Description Creates a secure note for the logged-in user
+// @Tags Passwords
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   note     body    CreateOrUpdateNoteRequest     true        "Note"
+// @Success 200 {object} services.Password
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /password/note/create [post]
+func CreateNote(c *gin.Context) {
+	createItem(c, &CreateOrUpdateNoteRequest{})
+}
+
+// UpdateNote updates a secure note.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Update secure note
+// @Description Updates the secure note with the given ID
+// @Tags Passwords
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Password ID"
+// @Param   note     body    CreateOrUpdateNoteRequest     true        "Note"
+// @Success 200 {object} PasswordRequestAndResponse
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 409 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /password/note/update/{id} [put]
+func UpdateNote(c *gin.Context) {
+	updateItem(c, &CreateOrUpdateNoteRequest{})
+}
+
+// CreateCard creates a payment card.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Create payment card
+// @Description Creates a payment card for the logged-in user, the number is checked with the Luhn algorithm
+// @Tags Passwords
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   card     body    CreateOrUpdateCardRequest     true        "Card"
+// @Success 200 {object} services.Password
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /password/card/create [post]
+func CreateCard(c *gin.Context) {
+	createItem(c, &CreateOrUpdateCardRequest{})
+}
+
+// UpdateCard updates a payment card.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Update payment card
+// @Description Updates the payment card with the given ID
+// @Tags Passwords
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Password ID"
+// @Param   card     body    CreateOrUpdateCardRequest     true        "Card"
+// @Success 200 {object} PasswordRequestAndResponse
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 409 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /password/card/update/{id} [put]
+func UpdateCard(c *gin.Context) {
+	updateItem(c, &CreateOrUpdateCardRequest{})
+}
+
+// CreateIdentity creates an identity.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Create identity
+// @Description Creates an identity with address and document numbers for the logged-in user
+// @Tags Passwords
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   identity     body    CreateOrUpdateIdentityRequest     true        "Identity"
+// @Success 200 {object} services.Password
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /password/identity/create [post]
+func CreateIdentity(c *gin.Context) {
+	createItem(c, &CreateOrUpdateIdentityRequest{})
+}
+
+// UpdateIdentity updates an identity.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Update identity
+// @Description Updates the identity with the given ID
+// @Tags Passwords
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Password ID"
+// @Param   identity     body    CreateOrUpdateIdentityRequest     true        "Identity"
+// @Success 200 {object} PasswordRequestAndResponse
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 409 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /password/identity/update/{id} [put]
+func UpdateIdentity(c *gin.Context) {
+	updateItem(c, &CreateOrUpdateIdentityRequest{})
+}
+
+// CreateWifi creates a WiFi network.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Create WiFi network
+// @Description Creates a WiFi network for the logged-in user.
+// @Description Security is one of open, wep, wpa, wpa2, wpa3 or wpa2_enterprise.
+// @Tags Passwords
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   wifi     body    CreateOrUpdateWifiRequest     true        "WiFi network"
+// @Success 200 {object} services.Password
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /password/wifi/create [post]
+func CreateWifi(c *gin.Context) {
+	createItem(c, &CreateOrUpdateWifiRequest{})
+}
+
+// UpdateWifi updates a WiFi network.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Update WiFi network
+// @Description Updates the WiFi network with the given ID
+// @Tags Passwords
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Password ID"
+// @Param   wifi     body    CreateOrUpdateWifiRequest     true        "WiFi network"
+// @Success 200 {object} PasswordRequestAndResponse
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 409 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /password/wifi/update/{id} [put]
+func UpdateWifi(c *gin.Context) {
+	updateItem(c, &CreateOrUpdateWifiRequest{})
+}
+
+// createItem binds the request body and creates the item.
+func createItem(c *gin.Context, request itemRequest) {
+	if err := c.ShouldBindJSON(request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	passwordService, user := getServiceAndUser(c)
+
+	password, err := passwordService.CreatePassword(user.User.ID, request.toData())
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, password)
+}
+
+// updateItem binds the item ID and the request body and updates the item.
+func updateItem(c *gin.Context, json itemRequest) {
+	var request PasswordRequestAndResponse
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := c.ShouldBindJSON(json); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	passwordService, user := getServiceAndUser(c)
+
+	id, err := passwordService.UpdatePassword(request.ID, user.User.ID, json.toData())
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, PasswordRequestAndResponse{ID: id})
+}
+
+// toData converts the request body to the service input.
+func (r *CreateOrUpdateNoteRequest) toData() services.PasswordData {
+	return r.ItemRequest.toData(services.Item{Type: models.TypeNote, Note: &r.Note})
+}
+
+// toData converts the request body to the service input.
+func (r *CreateOrUpdateCardRequest) toData() services.PasswordData {
+	return r.ItemRequest.toData(services.Item{Type: models.TypeCard, Card: &r.Card})
+}
+
+// toData converts the request body to the service input.
+func (r *CreateOrUpdateIdentityRequest) toData() services.PasswordData {
+	return r.ItemRequest.toData(services.Item{Type: models.TypeIdentity, Identity: &r.Identity})
+}
+
+// toData converts the request body to the service input.
+func (r *CreateOrUpdateWifiRequest) toData() services.PasswordData {
+	return r.ItemRequest.toData(services.Item{Type: models.TypeWifi, Wifi: &r.Wifi})
+}
+
+// toData converts the shared values and the type-specific item to the service input.
+func (r ItemRequest) toData(item services.Item) services.PasswordData {
+	return services.PasswordData{
+		CategoryID:   r.CategoryID,
+		Item:         item,
+		Name:         r.Name,
+		Fields:       toFields(r.Fields),
+		TagIDs:       r.TagIDs,
+		RotationDays: r.RotationDays,
+	}
+}
+
+// toFields converts the custom fields of a request body to the service input.
+func toFields(requestFields []PasswordFieldRequest) []services.Field {
+	fields := make([]services.Field, len(requestFields))
+	for i, field := range requestFields {
+		fields[i] = services.Field{Label: field.Label, Type: field.Type, Value: field.Value}
+	}
+
+	return fields
+}
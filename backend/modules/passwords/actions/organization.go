@@ -0,0 +1,124 @@
+package actions
+
+import (
+	services3 "backend/modules/organizations/services"
+	services2 "backend/services"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// GetAccess retrieves what the logged-in user can do with a password.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Get effective access to password
+// @Description Retrieves the effective permission of the logged-in user on the password with the given ID,
+// @Description combining ownership, direct shares and organization collections.
+// @Tags Organizations
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Password ID"
+// @Success 200 {object} services3.Access
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /password/{id}/access [get]
+func GetAccess(c *gin.Context) {
+	var request PasswordRequestAndResponse
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	passwordService, user := getServiceAndUser(c)
+
+	var access services3.Access
+
+	access, err := passwordService.GetAccess(request.ID, user.User.ID)
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, access)
+}
+
+// GetOrganizationPassword retrieves a password the logged-in user can read through an organization.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Get organization password
+// @Description Retrieves the password with the given ID from a collection or a share of the logged-in user.
+// @Description Its secrets are masked, use POST /password/{id}/reveal or /password/{id}/copy to read them. The category and tags of the owner are hidden.
+// @Tags Organizations
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Password ID"
+// @Success 200 {object} services.Password
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /password/organization/{id} [get]
+func GetOrganizationPassword(c *gin.Context) {
+	var request PasswordRequestAndResponse
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	passwordService, user := getServiceAndUser(c)
+
+	password, err := passwordService.GetOrganizationPassword(request.ID, user.User.ID)
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, password)
+}
+
+// UpdateOrganizationPassword updates a password the logged-in user can write to through an organization.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Update organization password
+// @Description Updates the password with the given ID from a collection the logged-in user can write to, or shared with the edit permission.
+// @Description The category, tags and rotation interval of the owner are kept, category_id, tag_ids and rotation_days are ignored.
+// @Tags Organizations
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Password ID"
+// @Param   password     body    CreateOrUpdatePasswordRequest     true        "Password"
+// @Success 200 {object} PasswordRequestAndResponse
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 403 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 409 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /password/organization/{id} [put]
+func UpdateOrganizationPassword(c *gin.Context) {
+	var request PasswordRequestAndResponse
+	var json CreateOrUpdatePasswordRequest
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := c.ShouldBindJSON(&json); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	passwordService, user := getServiceAndUser(c)
+
+	id, err := passwordService.UpdateOrganizationPassword(request.ID, user.User.ID, json.toData())
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, PasswordRequestAndResponse{ID: id})
+}
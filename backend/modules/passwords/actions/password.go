@@ -0,0 +1,345 @@
+package actions
+
+import (
+	services3 "backend/modules/organizations/services"
+	models2 "backend/modules/passwords/models"
+	"backend/modules/passwords/services"
+	"backend/modules/users/models"
+	services2 "backend/services"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"net/http"
+)
+
+type CreateOrUpdatePasswordRequest struct {
+	CategoryID *uint                  `json:"category_id"`
+	Name       string                 `json:"name" binding:"required"`
+	Login      string                 `json:"login"`
+	Password   string                 `json:"password"`
+	Fields     []PasswordFieldRequest `json:"fields" binding:"dive"`
+	URIs       []PasswordURIRequest   `json:"uris" binding:"dive"`
+	TagIDs     []uint                 `json:"tag_ids"`
+	// The password must be rotated every rotation_days days, null for the interval of its category.
+	RotationDays *int `json:"rotation_days" binding:"omitempty,min=1,max=3650"`
+	// otpauth://totp/ URI from a QR code or a manually entered base32 secret.
+	Totp string `json:"totp"`
+}
+
+type PasswordFieldRequest struct {
+	Label string `json:"label" binding:"required"`
+	Type  string `json:"type" binding:"required,oneof=text hidden url email date boolean"`
+	Value string `json:"value"`
+}
+
+type PasswordURIRequest struct {
+	URI string `json:"uri" binding:"required,max=2048"`
+	// Match strategy, domain by default.
+	Match string `json:"match" binding:"omitempty,oneof=domain host starts_with regex never"`
+}
+
+type PasswordRequestAndResponse struct {
+	ID uint `json:"id" uri:"id" binding:"required"`
+}
+
+type GetPasswordsRequest struct {
+	CategoryID *uint  `form:"category_id"`
+	Type       string `form:"type" binding:"omitempty,oneof=login note card identity wifi"`
+	Score      *int   `form:"score" binding:"omitempty,min=0,max=4"`
+	MaxScore   *int   `form:"max_score" binding:"omitempty,min=0,max=4"`
+	Breached   *bool  `form:"breached"`
+	TagIDs     []uint `form:"tag_id"`
+}
+
+type EstimateStrengthRequest struct {
+	Password   string   `json:"password" binding:"required"`
+	UserInputs []string `json:"user_inputs"`
+}
+
+// GetPasswords retrieves the passwords of the logged-in user.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Get list of passwords
+// @Description Retrieves the passwords for the logged-in user, optionally filtered by category, item type, strength score, breach status or tags.
+// @Description Secrets are masked in the list, use POST /password/{id}/reveal or POST /password/{id}/copy to read them.
+// @Description Use max_score=1 to find weak credentials and breached=true to find passwords found in data breaches.
+// @Tags Passwords
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   category_id  query    int  false  "Category ID"
+// @Param   type  query    string  false  "Item type: login, note, card, identity or wifi"
+// @Param   score  query    int  false  "Exact strength score from 0 to 4"
+// @Param   max_score  query    int  false  "Maximal strength score from 0 to 4"
+// @Param   breached  query    bool  false  "Only breached (true) or not breached (false) passwords"
+// @Param   tag_id  query    []int  false  "Tag IDs, passwords must have all of them" collectionFormat(multi)
+// @Success 200 {array} services.Password
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /password/all [get]
+func GetPasswords(c *gin.Context) {
+	var request GetPasswordsRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	passwordService, user := getServiceAndUser(c)
+
+	passwords, err := passwordService.GetPasswords(user.User.ID, services.PasswordFilter{
+		CategoryID: request.CategoryID,
+		Type:       request.Type,
+		Score:      request.Score,
+		MaxScore:   request.MaxScore,
+		Breached:   request.Breached,
+		TagIDs:     request.TagIDs,
+	})
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, passwords)
+}
+
+// GetPassword retrieves a single password of the logged-in user.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Get password
+// @Description Retrieves the password with the given ID, its secrets are masked.
+// @Description Use POST /password/{id}/reveal or /password/{id}/copy to read them.
+// @Tags Passwords
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Password ID"
+// @Success 200 {object} services.Password
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /password/{id} [get]
+func GetPassword(c *gin.Context) {
+	var request PasswordRequestAndResponse
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	passwordService, user := getServiceAndUser(c)
+
+	password, err := passwordService.GetPassword(request.ID, user.User.ID)
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, password)
+}
+
+// CreatePassword handles the creation of a new password.
+//
+// It expects a gin.Context parameter to access the HTTP request and response.
+// It does not have any return values.
+// @Summary Create a new password
+// @Description Creates a new password, the category must belong to the logged-in user.
+// @Description In the zero-knowledge vault mode login, password and the values of the custom fields must be client ciphertext.
+// @Tags Passwords
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   password     body    CreateOrUpdatePasswordRequest     true        "Password"
+// @Success 200 {object} services.Password
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /password/create [post]
+func CreatePassword(c *gin.Context) {
+	var request CreateOrUpdatePasswordRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	passwordService, user := getServiceAndUser(c)
+
+	password, err := passwordService.CreatePassword(user.User.ID, request.toData())
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, password)
+}
+
+// UpdatePassword updates a password.
+//
+// It binds the password ID from the URI and the new values from the JSON body,
+// then updates the password owned by the logged-in user.
+// @Summary Update password
+// @Description Updates the login with the given ID, the other types are updated with the route of their type
+// @Tags Passwords
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Password ID"
+// @Param   password     body    CreateOrUpdatePasswordRequest     true        "Password"
+// @Success 200 {object} PasswordRequestAndResponse
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 409 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /password/update/{id} [put]
+func UpdatePassword(c *gin.Context) {
+	var request PasswordRequestAndResponse
+	var json CreateOrUpdatePasswordRequest
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := c.ShouldBindJSON(&json); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	passwordService, user := getServiceAndUser(c)
+
+	id, err := passwordService.UpdatePassword(request.ID, user.User.ID, json.toData())
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, PasswordRequestAndResponse{ID: id})
+}
+
+// DeletePassword deletes a password.
+//
+// The function takes a gin.Context pointer as a parameter.
+// It returns nothing.
+// @Summary Delete password
+// @Description Deletes the password with the given ID
+// @Tags Passwords
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Password ID"
+// @Success 200 {object} PasswordRequestAndResponse
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /password/delete/{id} [delete]
+func DeletePassword(c *gin.Context) {
+	var request PasswordRequestAndResponse
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	passwordService, user := getServiceAndUser(c)
+
+	err := passwordService.DeletePassword(request.ID, user.User.ID)
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, PasswordRequestAndResponse{ID: request.ID})
+}
+
+// EstimateStrength estimates the strength of a password without saving it.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Estimate password strength
+// @Description Returns a zxcvbn-style estimate: score, guesses, entropy, crack time and feedback
+// @Tags Passwords
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   password     body    EstimateStrengthRequest     true        "Password"
+// @Success 200 {object} services.Strength
+// @Failure 400 {object} services2.ErrorResponse
+// @Router /password/strength [post]
+func EstimateStrength(c *gin.Context) {
+	var request EstimateStrengthRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	passwordService, user := getServiceAndUser(c)
+
+	userInputs := append(request.UserInputs, user.User.Name, user.User.Email)
+
+	c.JSON(http.StatusOK, passwordService.EstimateStrength(request.Password, userInputs))
+}
+
+// toData converts the request body to the service input.
+func (r CreateOrUpdatePasswordRequest) toData() services.PasswordData {
+	return services.PasswordData{
+		CategoryID:   r.CategoryID,
+		Item:         services.Item{Type: models2.TypeLogin},
+		Name:         r.Name,
+		Login:        r.Login,
+		Password:     r.Password,
+		Fields:       toFields(r.Fields),
+		URIs:         toURIs(r.URIs),
+		Totp:         r.Totp,
+		TagIDs:       r.TagIDs,
+		RotationDays: r.RotationDays,
+	}
+}
+
+// toURIs converts the URIs of a request body to the service input.
+func toURIs(requestURIs []PasswordURIRequest) []services.URI {
+	uris := make([]services.URI, len(requestURIs))
+	for i, uri := range requestURIs {
+		uris[i] = services.URI{URI: uri.URI, Match: uri.Match}
+	}
+
+	return uris
+}
+
+// errorStatus maps a service error to the HTTP status code of the response.
+//
+// It takes the error returned by the password service.
+// It returns 404 for missing records, 400 for invalid input, 403 for forbidden share operations, 409 for conflicts,
+// 413 for files over the size limits and 500 otherwise.
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrCategoryNotFound), errors.Is(err, services.ErrTagNotFound),
+		errors.Is(err, services.ErrInvalidPolicy),
+		errors.Is(err, services.ErrInvalidTotp), errors.Is(err, services.ErrTotpNotSet), errors.Is(err, services.ErrSecretNotSet),
+		errors.Is(err, services.ErrClientEncrypted), errors.Is(err, services.ErrInvalidField),
+		errors.Is(err, services.ErrInvalidItem), errors.Is(err, services.ErrInvalidQuery),
+		errors.Is(err, services.ErrInvalidURI), errors.Is(err, services.ErrShareWithSelf):
+		return http.StatusBadRequest
+	case errors.Is(err, services.ErrRecipientNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrShareForbidden), errors.Is(err, services3.ErrAccessDenied):
+		return http.StatusForbidden
+	case errors.Is(err, services.ErrIncompleteUpload):
+		return http.StatusBadRequest
+	case errors.Is(err, services.ErrPresetExists), errors.Is(err, services.ErrTypeMismatch):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrAttachmentTooLarge), errors.Is(err, services.ErrQuotaExceeded):
+		return http.StatusRequestEntityTooLarge
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// getServiceAndUser returns the password service and user token.
+//
+// It takes a Gin context as a parameter.
+// It returns a PasswordService and a Token.
+func getServiceAndUser(c *gin.Context) (services.PasswordService, models.Token) {
+	service := services.PasswordService{DB: services2.GetDBConnection()}
+	user := services2.GetUserFromContext(c)
+
+	return service, user
+}
@@ -0,0 +1,154 @@
+package actions
+
+import (
+	services2 "backend/services"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type RevisionRequest struct {
+	ID         uint `uri:"id" binding:"required"`
+	RevisionID uint `uri:"revision" binding:"required"`
+}
+
+// GetRevisions retrieves the previous values of a password.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Get list of password revisions
+// @Description Retrieves the revisions of the password with the given ID, newest first, without secrets
+// @Tags Passwords
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Password ID"
+// @Success 200 {array} services.PasswordRevision
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /password/{id}/revisions [get]
+func GetRevisions(c *gin.Context) {
+	var request PasswordRequestAndResponse
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	passwordService, user := getServiceAndUser(c)
+
+	revisions, err := passwordService.GetRevisions(request.ID, user.User.ID)
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, revisions)
+}
+
+// GetRevision retrieves a single previous value of a password.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Get password revision
+// @Description Retrieves the revision with the given ID, its secrets are masked.
+// @Description Use POST /password/{id}/revisions/{revision}/reveal to read them.
+// @Tags Passwords
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Password ID"
+// @Param   revision       path     int                             true        "Revision ID"
+// @Success 200 {object} services.PasswordRevisionDetails
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /password/{id}/revisions/{revision} [get]
+func GetRevision(c *gin.Context) {
+	var request RevisionRequest
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	passwordService, user := getServiceAndUser(c)
+
+	revision, err := passwordService.GetRevision(request.RevisionID, request.ID, user.User.ID)
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, revision)
+}
+
+// RevealRevision reveals the secrets of a previous value of a password.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Reveal password revision
+// @Description Retrieves the revision with the given ID with its secrets and records the access in the access log
+// @Description of the password.
+// @Tags Passwords
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Password ID"
+// @Param   revision       path     int                             true        "Revision ID"
+// @Success 200 {object} services.PasswordRevisionDetails
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /password/{id}/revisions/{revision}/reveal [post]
+func RevealRevision(c *gin.Context) {
+	var request RevisionRequest
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	passwordService, user := getServiceAndUser(c)
+
+	revision, err := passwordService.RevealRevision(request.RevisionID, request.ID, user.User.ID, getClient(c, user))
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, revision)
+}
+
+// RestoreRevision restores a previous value of a password.
+//
+// The current value is kept as a new revision.
+// @Summary Restore password revision
+// @Description Makes the revision with the given ID the current value of the password
+// @Tags Passwords
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Password ID"
+// @Param   revision       path     int                             true        "Revision ID"
+// @Success 200 {object} PasswordRequestAndResponse
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /password/{id}/revisions/{revision}/restore [post]
+func RestoreRevision(c *gin.Context) {
+	var request RevisionRequest
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	passwordService, user := getServiceAndUser(c)
+
+	err := passwordService.RestoreRevision(request.RevisionID, request.ID, user.User.ID)
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, PasswordRequestAndResponse{ID: request.ID})
+}
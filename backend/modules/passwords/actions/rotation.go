@@ -0,0 +1,44 @@
+package actions
+
+import (
+	services2 "backend/services"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type GetDuePasswordsRequest struct {
+	WithinDays int `form:"within_days" binding:"omitempty,min=0,max=365"`
+}
+
+// GetDuePasswords retrieves the passwords of the logged-in user that must be rotated.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Get passwords due for rotation
+// @Description Retrieves the passwords whose rotation interval, set on the password or on its category, has elapsed since their secret last changed.
+// @Description Updating the secret of a password marks it as rotated.
+// @Tags Passwords
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   within_days  query    int  false  "Also include the passwords due in the next days, 0 by default"
+// @Success 200 {array} services.DuePassword
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /password/due [get]
+func GetDuePasswords(c *gin.Context) {
+	var request GetDuePasswordsRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	passwordService, user := getServiceAndUser(c)
+
+	passwords, err := passwordService.GetDuePasswords(user.User.ID, request.WithinDays)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, passwords)
+}
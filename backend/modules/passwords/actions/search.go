@@ -0,0 +1,100 @@
+package actions
+
+import (
+	"backend/modules/passwords/services"
+	services2 "backend/services"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type SearchPasswordsRequest struct {
+	Query      string `form:"q" binding:"required,max=255"`
+	CategoryID *uint  `form:"category_id"`
+	Type       string `form:"type" binding:"omitempty,oneof=login note card identity wifi"`
+	TagIDs     []uint `form:"tag_id"`
+	Limit      int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+type UpdateSearchSettingsRequest struct {
+	SearchLogins *bool `json:"search_logins" binding:"required"`
+}
+
+type SearchSettingsResponse struct {
+	SearchLogins bool `json:"search_logins"`
+}
+
+// SearchPasswords searches the items of the logged-in user.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Search passwords
+// @Description Searches the name, category, tags and URI hosts of the items of the logged-in user, and their login when login search is enabled.
+// @Description Only the host names of the URIs are indexed, paths and queries are not, and client-encrypted items have no hosts.
+// @Description Words match the beginning of words and small typos are tolerated, the best matches come first.
+// @Description Highlights are HTML-escaped with the matches wrapped in <mark> tags. Secrets are never searched.
+// @Tags Passwords
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   q  query    string  true  "Search query"
+// @Param   category_id  query    int  false  "Category ID"
+// @Param   type  query    string  false  "Item type: login, note, card, identity or wifi"
+// @Param   tag_id  query    []int  false  "Tag IDs, items must have all of them" collectionFormat(multi)
+// @Param   limit  query    int  false  "Maximal number of results, 20 by default and at most 100"
+// @Success 200 {array} services.SearchResult
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /password/search [get]
+func SearchPasswords(c *gin.Context) {
+	var request SearchPasswordsRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	passwordService, user := getServiceAndUser(c)
+
+	results, err := passwordService.Search(user.User.ID, request.Query, services.SearchFilter{
+		CategoryID: request.CategoryID,
+		Type:       request.Type,
+		TagIDs:     request.TagIDs,
+		Limit:      request.Limit,
+	})
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, results)
+}
+
+// UpdateSearchSettings enables or disables login search for the logged-in user.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Update search settings
+// @Description Enables or disables login search. When enabled, the logins of server-encrypted items are stored
+// @Description in plaintext in the search index, disabling it removes them. Client-encrypted items are never indexed.
+// @Tags Passwords
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   settings     body    UpdateSearchSettingsRequest     true        "Search settings"
+// @Success 200 {object} SearchSettingsResponse
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /password/search/settings [put]
+func UpdateSearchSettings(c *gin.Context) {
+	var request UpdateSearchSettingsRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	passwordService, user := getServiceAndUser(c)
+
+	if err := passwordService.SetSearchLogins(user.User.ID, *request.SearchLogins); err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, SearchSettingsResponse{SearchLogins: *request.SearchLogins})
+}
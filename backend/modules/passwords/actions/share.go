@@ -0,0 +1,235 @@
+package actions
+
+import (
+	services2 "backend/services"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type SharePasswordRequest struct {
+	// Email of the user to share the password with.
+	Email string `json:"email" binding:"required,email"`
+	// view, edit or view_without_reveal to show the entry with its secrets masked.
+	Permission string `json:"permission" binding:"required,oneof=view edit view_without_reveal"`
+}
+
+type ShareRequest struct {
+	ID      uint `uri:"id" binding:"required"`
+	ShareID uint `uri:"share" binding:"required"`
+}
+
+// SharePassword shares a password with another user.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Share password
+// @Description Shares the password with the user with the given email, or changes the permission of an existing share.
+// @Description The key of the password is sealed to the public key of the recipient, client-encrypted passwords cannot be shared.
+// @Tags Sharing
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Password ID"
+// @Param   share     body    SharePasswordRequest     true        "Share"
+// @Success 200 {object} services.Share
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /password/{id}/shares [post]
+func SharePassword(c *gin.Context) {
+	var request PasswordRequestAndResponse
+	var json SharePasswordRequest
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := c.ShouldBindJSON(&json); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	passwordService, user := getServiceAndUser(c)
+
+	share, err := passwordService.SharePassword(request.ID, user.User.ID, json.Email, json.Permission)
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, share)
+}
+
+// GetShares retrieves the users a password is shared with.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Get list of password shares
+// @Description Retrieves the users the password with the given ID is shared with and their permissions
+// @Tags Sharing
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Password ID"
+// @Success 200 {array} services.Share
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /password/{id}/shares [get]
+func GetShares(c *gin.Context) {
+	var request PasswordRequestAndResponse
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	passwordService, user := getServiceAndUser(c)
+
+	shares, err := passwordService.GetShares(request.ID, user.User.ID)
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, shares)
+}
+
+// RevokeShare stops sharing a password with a user.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Revoke password share
+// @Description Stops sharing the password with the user of the given share. The key of the password is rotated,
+// @Description so the user cannot read later changes even with a copy of the old key.
+// @Tags Sharing
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Password ID"
+// @Param   share       path     int                             true        "Share ID"
+// @Success 200 {object} PasswordRequestAndResponse
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /password/{id}/shares/{share} [delete]
+func RevokeShare(c *gin.Context) {
+	var request ShareRequest
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	passwordService, user := getServiceAndUser(c)
+
+	err := passwordService.RevokeShare(request.ShareID, request.ID, user.User.ID)
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, PasswordRequestAndResponse{ID: request.ID})
+}
+
+// GetReceivedPasswords retrieves the passwords shared with the logged-in user.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Get list of passwords shared with me
+// @Description Retrieves the passwords other users shared with the logged-in user.
+// @Description Secrets are masked in the list, use POST /password/{id}/reveal or /password/{id}/copy to read them.
+// @Tags Sharing
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Success 200 {array} services.ReceivedPassword
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /password/shared [get]
+func GetReceivedPasswords(c *gin.Context) {
+	passwordService, user := getServiceAndUser(c)
+
+	passwords, err := passwordService.GetReceivedPasswords(user.User.ID)
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, passwords)
+}
+
+// GetReceivedPassword retrieves a password shared with the logged-in user.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Get password shared with me
+// @Description Retrieves the password with the given ID shared with the logged-in user, its secrets are masked.
+// @Description Use POST /password/{id}/reveal or /password/{id}/copy to read them unless the permission is view_without_reveal.
+// @Tags Sharing
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Password ID"
+// @Success 200 {object} services.ReceivedPassword
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /password/shared/{id} [get]
+func GetReceivedPassword(c *gin.Context) {
+	var request PasswordRequestAndResponse
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	passwordService, user := getServiceAndUser(c)
+
+	password, err := passwordService.GetReceivedPassword(request.ID, user.User.ID)
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, password)
+}
+
+// UpdateReceivedPassword updates a password shared with the logged-in user.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Update password shared with me
+// @Description Updates the password with the given ID shared with the edit permission.
+// @Description The category, tags and rotation interval of the owner are kept, category_id, tag_ids and rotation_days are ignored.
+// @Tags Sharing
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Password ID"
+// @Param   password     body    CreateOrUpdatePasswordRequest     true        "Password"
+// @Success 200 {object} PasswordRequestAndResponse
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 403 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 409 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /password/shared/{id} [put]
+func UpdateReceivedPassword(c *gin.Context) {
+	var request PasswordRequestAndResponse
+	var json CreateOrUpdatePasswordRequest
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := c.ShouldBindJSON(&json); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	passwordService, user := getServiceAndUser(c)
+
+	id, err := passwordService.UpdateReceivedPassword(request.ID, user.User.ID, json.toData())
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, PasswordRequestAndResponse{ID: id})
+}
@@ -0,0 +1,43 @@
+package actions
+
+import (
+	services2 "backend/services"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// GetTotpCode returns the current one-time code of a password.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Get TOTP code
+// @Description Generates the current RFC 6238 code from the otpauth URI of the password with the given ID.
+// @Description Not available for entries encrypted by the client in the zero-knowledge vault mode.
+// @Description Each code is recorded in the access log of the password as a copy of the totp secret.
+// @Tags Passwords
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Password ID"
+// @Success 200 {object} services.TotpCode
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /password/{id}/totp [get]
+func GetTotpCode(c *gin.Context) {
+	var request PasswordRequestAndResponse
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	passwordService, user := getServiceAndUser(c)
+
+	code, err := passwordService.GetTotpCode(request.ID, user.User.ID, getClient(c, user))
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, code)
+}
@@ -0,0 +1,80 @@
+package actions
+
+import (
+	services2 "backend/services"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type MatchPasswordsRequest struct {
+	URL string `form:"url" binding:"required,max=2048"`
+}
+
+// MatchPasswords retrieves the logins of the logged-in user matching a page, for autofill.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Match passwords
+// @Description Retrieves the logins whose URIs match the page with their match strategy: domain compares the registrable domain
+// @Description from the public suffix list, host the host and port, starts_with the scheme, host and port with a prefix of the path,
+// @Description and regex a regular expression.
+// @Description The most specific matches come first, then the most recently used logins. Secrets are not included,
+// @Description use POST /password/{id}/reveal or POST /password/{id}/copy to read them and POST /password/{id}/used after filling them in.
+// @Tags Passwords
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   url  query    string  true  "Absolute URL of the page"
+// @Success 200 {array} services.MatchResult
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /password/match [get]
+func MatchPasswords(c *gin.Context) {
+	var request MatchPasswordsRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	passwordService, user := getServiceAndUser(c)
+
+	results, err := passwordService.Match(user.User.ID, request.URL)
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, results)
+}
+
+// MarkPasswordUsed records that a password was used.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Mark password as used
+// @Description Records that the password with the given ID was filled in, recently used logins rank higher in /password/match
+// @Tags Passwords
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Password ID"
+// @Success 200 {object} PasswordRequestAndResponse
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /password/{id}/used [post]
+func MarkPasswordUsed(c *gin.Context) {
+	var request PasswordRequestAndResponse
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	passwordService, user := getServiceAndUser(c)
+
+	if err := passwordService.MarkUsed(request.ID, user.User.ID); err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, PasswordRequestAndResponse{ID: request.ID})
+}
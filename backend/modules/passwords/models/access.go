@@ -0,0 +1,74 @@
+package models
+
+import (
+	"backend/modules/users/models"
+	"gorm.io/gorm"
+	"time"
+)
+
+const (
+	AccessReveal = "reveal"
+	AccessCopy   = "copy"
+)
+
+// AccessLog records that a user read the secrets of a password, by revealing the whole entry or copying one secret.
+//
+// TokenID is the access token of the request, IP and UserAgent describe the client.
+type AccessLog struct {
+	ID         uint      `gorm:"primaryKey"`
+	CreatedAt  time.Time `gorm:"index"`
+	PasswordID uint      `gorm:"not null;index"`
+	Entry      Password  `gorm:"foreignKey:PasswordID;constraint:OnDelete:CASCADE"`
+	// UserID is the user who read the secrets, the owner or someone the password is shared with.
+	UserID  uint        `gorm:"not null;index"`
+	User    models.User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	TokenID *uint       `gorm:"nullable"`
+	Action  string      `gorm:"not null"`
+	// Field is the copied secret, the revealed revision as revision:{id} or the downloaded attachment as
+	// attachment:{id}, empty for reveals of the entry.
+	Field     string `gorm:"not null;default:''"`
+	IP        string `gorm:"not null;default:''"`
+	UserAgent string `gorm:"not null;default:''"`
+}
+
+type AccessLogFilter struct {
+	Since *time.Time
+	Until *time.Time
+}
+
+type AccessLogModel struct {
+	DB *gorm.DB
+}
+
+// Create saves an access log entry.
+//
+// entry: the entry to save.
+// Returns an error if the insert fails.
+func (m *AccessLogModel) Create(entry AccessLog) error {
+	return m.DB.Omit("Entry", "User").Create(&entry).Error
+}
+
+// GetAll returns the accesses to the secrets of a password with the users who read them, newest first.
+//
+// Parameters:
+// - passwordId: the ID of the password.
+// - filter: the optional time range.
+//
+// Returns:
+// - []AccessLog: the entries.
+// - error: any error that occurred during the retrieval process.
+func (m *AccessLogModel) GetAll(passwordId uint, filter AccessLogFilter) ([]AccessLog, error) {
+	var entries []AccessLog
+
+	query := m.DB.Preload("User", selectUser).Where("password_id = ?", passwordId)
+	if filter.Since != nil {
+		query = query.Where("created_at >= ?", *filter.Since)
+	}
+	if filter.Until != nil {
+		query = query.Where("created_at < ?", *filter.Until)
+	}
+
+	err := query.Order("created_at DESC, id DESC").Find(&entries).Error
+
+	return entries, err
+}
@@ -0,0 +1,329 @@
+package models
+
+import (
+	"backend/modules/users/models"
+	"backend/services/encryption"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// pendingUploadTimeout is how long an unfinished upload counts against the quota of its owner.
+const pendingUploadTimeout = 24 * time.Hour
+
+var ErrQuotaExceeded = errors.New("attachment storage quota exceeded")
+
+// Attachment is a file attached to a password.
+//
+// The content is encrypted with its own key, which is stored wrapped with the key of the owner.
+// The name and the wrapped key are bound to the row with rowAAD, so they cannot be swapped between attachments.
+// Ready is set once the upload to the blob storage has completed.
+type Attachment struct {
+	gorm.Model
+	PasswordID  uint     `gorm:"not null;index"`
+	Entry       Password `gorm:"foreignKey:PasswordID;constraint:OnDelete:CASCADE"`
+	UserID      uint     `gorm:"not null;index"`
+	Name        string   `gorm:"not null"`
+	ContentType string   `gorm:"not null"`
+	Size        int64    `gorm:"not null"`
+	StorageKey  string   `gorm:"not null;uniqueIndex"`
+	FileKey     string   `gorm:"not null"`
+	Ready       bool     `gorm:"not null;default:false"`
+	// Bound is false for rows whose name and key were encrypted before they were bound to the row, see BindLegacy.
+	Bound bool `gorm:"not null;default:false"`
+}
+
+type AttachmentModel struct {
+	DB *gorm.DB
+}
+
+// GetAll returns the uploaded attachments of a password with decrypted names.
+//
+// Parameters:
+// - passwordId: the ID of the password.
+// - userId: the ID of the user who owns the password.
+//
+// Returns:
+// - []Attachment: the attachments, oldest first.
+// - error: any error that occurred during the retrieval process.
+func (m *AttachmentModel) GetAll(passwordId, userId uint) ([]Attachment, error) {
+	var attachments []Attachment
+
+	err := m.DB.Where("password_id = ? AND user_id = ? AND ready", passwordId, userId).
+		Order("id").
+		Find(&attachments).Error
+	if err != nil || len(attachments) == 0 {
+		return attachments, err
+	}
+
+	cipher, err := m.getCipher(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range attachments {
+		if attachments[i].Name, err = cipher.Decrypt(attachments[i].Name, attachments[i].aad("name")); err != nil {
+			return nil, err
+		}
+	}
+
+	return attachments, nil
+}
+
+// Get returns an uploaded attachment with its decrypted name and the cipher of its content.
+//
+// Parameters:
+// - id: the ID of the attachment.
+// - passwordId: the ID of the password.
+// - userId: the ID of the user who owns the password.
+//
+// Returns:
+// - Attachment: the attachment.
+// - encryption.Cipher: the cipher of the file content.
+// - error: gorm.ErrRecordNotFound if the attachment does not exist.
+func (m *AttachmentModel) Get(id, passwordId, userId uint) (Attachment, encryption.Cipher, error) {
+	var attachment Attachment
+
+	err := m.DB.Where("id = ? AND password_id = ? AND user_id = ? AND ready", id, passwordId, userId).
+		First(&attachment).Error
+	if err != nil {
+		return Attachment{}, encryption.Cipher{}, err
+	}
+
+	cipher, err := m.getCipher(userId)
+	if err != nil {
+		return Attachment{}, encryption.Cipher{}, err
+	}
+
+	if attachment.Name, err = cipher.Decrypt(attachment.Name, attachment.aad("name")); err != nil {
+		return Attachment{}, encryption.Cipher{}, err
+	}
+
+	fileKey, err := cipher.DecryptBytes(attachment.FileKey, attachment.aad("file_key"))
+	if err != nil {
+		return Attachment{}, encryption.Cipher{}, err
+	}
+
+	fileCipher, err := encryption.NewCipher(fileKey)
+
+	return attachment, fileCipher, err
+}
+
+// Reserve stores a pending attachment before its content is uploaded.
+//
+// The user row is locked while the quota is checked, so concurrent uploads cannot exceed it.
+// Pending uploads count against the quota until they finish or time out.
+//
+// Parameters:
+// - attachment: the attachment with PasswordID, UserID, Name, ContentType and Size set.
+// - quota: the maximal total size of the attachments of the user in bytes, 0 disables the limit.
+//
+// Returns:
+// - Attachment: the stored attachment with its plaintext name and storage key.
+// - encryption.Cipher: the cipher for the file content, with a new random key.
+// - error: ErrQuotaExceeded or any creation error.
+func (m *AttachmentModel) Reserve(attachment Attachment, quota int64) (Attachment, encryption.Cipher, error) {
+	cipher, err := m.getCipher(attachment.UserID)
+	if err != nil {
+		return Attachment{}, encryption.Cipher{}, err
+	}
+
+	fileKey, err := encryption.GenerateKey()
+	if err != nil {
+		return Attachment{}, encryption.Cipher{}, err
+	}
+
+	fileCipher, err := encryption.NewCipher(fileKey)
+	if err != nil {
+		return Attachment{}, encryption.Cipher{}, err
+	}
+
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return Attachment{}, encryption.Cipher{}, err
+	}
+
+	name := attachment.Name
+	stored := attachment
+	stored.StorageKey = fmt.Sprintf("attachments/%d/%s", attachment.UserID, hex.EncodeToString(random))
+	stored.Ready = false
+	stored.Bound = true
+
+	err = m.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			Where("id = ?", attachment.UserID).
+			First(&models.User{}).Error
+		if err != nil {
+			return err
+		}
+
+		if quota > 0 {
+			var used int64
+
+			err = tx.Model(&Attachment{}).
+				Select("COALESCE(SUM(size), 0)").
+				Where("user_id = ? AND (ready OR created_at > ?)", attachment.UserID, time.Now().Add(-pendingUploadTimeout)).
+				Scan(&used).Error
+			if err != nil {
+				return err
+			}
+
+			if used+attachment.Size > quota {
+				return ErrQuotaExceeded
+			}
+		}
+
+		// The values are bound to the ID of the row, so they are encrypted once it is inserted.
+		stored.Name, stored.FileKey = "", ""
+		if err := tx.Create(&stored).Error; err != nil {
+			return err
+		}
+
+		aad := rowAAD("attachments", stored.ID)
+
+		if stored.Name, err = cipher.Encrypt(name, aad("name")); err != nil {
+			return err
+		}
+
+		if stored.FileKey, err = cipher.EncryptBytes(fileKey, aad("file_key")); err != nil {
+			return err
+		}
+
+		return tx.Model(&Attachment{}).Where("id = ?", stored.ID).UpdateColumns(map[string]interface{}{
+			"name":     stored.Name,
+			"file_key": stored.FileKey,
+		}).Error
+	})
+	if err != nil {
+		return Attachment{}, encryption.Cipher{}, err
+	}
+
+	stored.Name = name
+
+	return stored, fileCipher, nil
+}
+
+// MarkReady marks the upload of an attachment as completed.
+//
+// id: the ID of the attachment.
+// Returns an error if the update fails.
+func (m *AttachmentModel) MarkReady(id uint) error {
+	return m.DB.Model(&Attachment{}).Where("id = ?", id).Update("ready", true).Error
+}
+
+// Delete deletes an attachment.
+//
+// Parameters:
+// - id: the ID of the attachment.
+// - passwordId: the ID of the password.
+// - userId: the ID of the user who owns the password.
+//
+// Returns:
+// - string: the storage key of the deleted content.
+// - error: gorm.ErrRecordNotFound if the attachment does not exist, or any deletion error.
+func (m *AttachmentModel) Delete(id, passwordId, userId uint) (string, error) {
+	var attachment Attachment
+
+	err := m.DB.Where("id = ? AND password_id = ? AND user_id = ?", id, passwordId, userId).First(&attachment).Error
+	if err != nil {
+		return "", err
+	}
+
+	return attachment.StorageKey, m.DB.Unscoped().Delete(&attachment).Error
+}
+
+// DeleteAll deletes every attachment of a password, including unfinished uploads.
+//
+// Parameters:
+// - passwordId: the ID of the password.
+//
+// Returns:
+// - []string: the storage keys of the deleted contents.
+// - error: any deletion error.
+func (m *AttachmentModel) DeleteAll(passwordId uint) ([]string, error) {
+	var attachments []Attachment
+
+	err := m.DB.Clauses(clause.Returning{Columns: []clause.Column{{Name: "storage_key"}}}).
+		Unscoped().
+		Where("password_id = ?", passwordId).
+		Delete(&attachments).Error
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, len(attachments))
+	for i, attachment := range attachments {
+		keys[i] = attachment.StorageKey
+	}
+
+	return keys, nil
+}
+
+// BindLegacy binds the names and keys of attachments stored before they were bound to their row.
+//
+// It does not take any parameters.
+// It returns an error if a row cannot be decrypted, encrypted or saved.
+func (m *AttachmentModel) BindLegacy() error {
+	var attachments []Attachment
+
+	return m.DB.Unscoped().Where("NOT bound").FindInBatches(&attachments, 100, func(tx *gorm.DB, batch int) error {
+		for _, attachment := range attachments {
+			cipher, err := m.getCipher(attachment.UserID)
+			if err != nil {
+				return err
+			}
+
+			name, err := cipher.Decrypt(attachment.Name, legacyAAD("name"))
+			if err != nil {
+				return err
+			}
+
+			fileKey, err := cipher.DecryptBytes(attachment.FileKey, legacyAAD("file_key"))
+			if err != nil {
+				return err
+			}
+
+			aad := rowAAD("attachments", attachment.ID)
+
+			if attachment.Name, err = cipher.Encrypt(name, aad("name")); err != nil {
+				return err
+			}
+
+			if attachment.FileKey, err = cipher.EncryptBytes(fileKey, aad("file_key")); err != nil {
+				return err
+			}
+
+			err = m.DB.Unscoped().Model(&Attachment{}).Where("id = ?", attachment.ID).UpdateColumns(map[string]interface{}{
+				"name":     attachment.Name,
+				"file_key": attachment.FileKey,
+				"bound":    true,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}).Error
+}
+
+// aad returns the additional authenticated data of an encrypted column of the attachment.
+func (a Attachment) aad(column string) string {
+	if a.Bound {
+		return rowAAD("attachments", a.ID)(column)
+	}
+
+	return legacyAAD(column)
+}
+
+// getCipher returns the cipher of the user owning the attachments.
+func (m *AttachmentModel) getCipher(userId uint) (encryption.Cipher, error) {
+	userModel := models.UserModel{DB: m.DB}
+
+	return userModel.GetCipher(userId)
+}
@@ -0,0 +1,214 @@
+package models
+
+import (
+	"backend/services/encryption"
+	"encoding/json"
+	"gorm.io/gorm"
+)
+
+const (
+	FieldText    = "text"
+	FieldHidden  = "hidden"
+	FieldURL     = "url"
+	FieldEmail   = "email"
+	FieldDate    = "date"
+	FieldBoolean = "boolean"
+)
+
+// FieldTypes lists the supported custom field types.
+var FieldTypes = []string{FieldText, FieldHidden, FieldURL, FieldEmail, FieldDate, FieldBoolean}
+
+// PasswordField is a typed custom field of a password.
+//
+// Every value is encrypted on its own with the key of the owner, hidden values are additionally
+// masked by clients until they are revealed.
+type PasswordField struct {
+	gorm.Model
+	PasswordID uint   `gorm:"not null;index" json:"-"`
+	Position   int    `gorm:"not null" json:"position"`
+	Label      string `gorm:"not null" json:"label"`
+	Type       string `gorm:"not null;default:text" json:"type"`
+	Value      string `gorm:"not null" json:"value"`
+}
+
+// encryptFields returns a copy of the fields with encrypted values.
+//
+// A copy is returned so the plaintext fields of the caller stay untouched.
+func encryptFields(fields []PasswordField, cipher encryption.Cipher, aad string) ([]PasswordField, error) {
+	encrypted := make([]PasswordField, len(fields))
+
+	for i, field := range fields {
+		value, err := cipher.Encrypt(field.Value, aad)
+		if err != nil {
+			return nil, err
+		}
+
+		field.Value = value
+		encrypted[i] = field
+	}
+
+	return encrypted, nil
+}
+
+// decryptFields decrypts the values of the fields in place.
+func decryptFields(fields []PasswordField, cipher encryption.Cipher, aad string) error {
+	for i := range fields {
+		if !encryption.IsEncrypted(fields[i].Value) {
+			continue
+		}
+
+		value, err := cipher.Decrypt(fields[i].Value, aad)
+		if err != nil {
+			return err
+		}
+
+		fields[i].Value = value
+	}
+
+	return nil
+}
+
+// replaceFields deletes the fields of a password and stores the given ones in their place.
+func replaceFields(tx *gorm.DB, passwordId uint, fields []PasswordField) error {
+	err := tx.Unscoped().Where("password_id = ?", passwordId).Delete(&PasswordField{}).Error
+	if err != nil {
+		return err
+	}
+
+	if len(fields) == 0 {
+		return nil
+	}
+
+	saved := make([]PasswordField, len(fields))
+	for i, field := range fields {
+		saved[i] = PasswordField{
+			PasswordID: passwordId,
+			Position:   field.Position,
+			Label:      field.Label,
+			Type:       field.Type,
+			Value:      field.Value,
+		}
+	}
+
+	return tx.Create(&saved).Error
+}
+
+// MigrateAdditional moves the free-form Additional text of passwords and revisions into a text field.
+//
+// The legacy column is dropped afterwards, so the migration runs only once.
+//
+// It does not take any parameters.
+// It returns an error if a value cannot be decrypted or saved.
+func (m *PasswordModel) MigrateAdditional() error {
+	migrator := m.DB.Migrator()
+
+	if migrator.HasColumn(&Password{}, "additional") {
+		err := m.DB.Transaction(func(tx *gorm.DB) error {
+			var rows []struct {
+				ID         uint
+				UserID     uint
+				Additional string
+				Encryption string
+			}
+
+			err := tx.Table("passwords").Select("id", "user_id", "additional", "encryption").
+				Where("additional <> ''").
+				Find(&rows).Error
+			if err != nil {
+				return err
+			}
+
+			for _, row := range rows {
+				value, err := m.migrateValue(row.UserID, row.Additional, row.Encryption)
+				if err != nil {
+					return err
+				}
+
+				if value == "" {
+					continue
+				}
+
+				field := PasswordField{PasswordID: row.ID, Label: "Additional", Type: FieldText, Value: value}
+				if err := tx.Create(&field).Error; err != nil {
+					return err
+				}
+			}
+
+			return tx.Migrator().DropColumn(&Password{}, "additional")
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if !migrator.HasColumn(&PasswordRevision{}, "additional") {
+		return nil
+	}
+
+	return m.DB.Transaction(func(tx *gorm.DB) error {
+		var rows []struct {
+			ID         uint
+			UserID     uint
+			Additional string
+			Encryption string
+		}
+
+		err := tx.Table("password_revisions").Select("id", "user_id", "additional", "encryption").
+			Where("additional <> ''").
+			Find(&rows).Error
+		if err != nil {
+			return err
+		}
+
+		for _, row := range rows {
+			value, err := m.migrateValue(row.UserID, row.Additional, row.Encryption)
+			if err != nil {
+				return err
+			}
+
+			if value == "" {
+				continue
+			}
+
+			fields, err := json.Marshal([]PasswordField{{Label: "Additional", Type: FieldText, Value: value}})
+			if err != nil {
+				return err
+			}
+
+			err = tx.Model(&PasswordRevision{}).Where("id = ?", row.ID).UpdateColumn("fields", string(fields)).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		return tx.Migrator().DropColumn(&PasswordRevision{}, "additional")
+	})
+}
+
+// migrateValue re-encrypts a legacy Additional value for the value column of a field.
+//
+// It returns an empty string for empty values, which are not migrated.
+// Client-encrypted values are moved as they are, the client has to migrate them itself.
+func (m *PasswordModel) migrateValue(userId uint, additional, mode string) (string, error) {
+	if mode == EncryptionClient {
+		return additional, nil
+	}
+
+	cipher, err := m.getCipher(userId)
+	if err != nil {
+		return "", err
+	}
+
+	if encryption.IsEncrypted(additional) {
+		additional, err = cipher.Decrypt(additional, "additional")
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if additional == "" {
+		return "", nil
+	}
+
+	return cipher.Encrypt(additional, "value")
+}
@@ -0,0 +1,134 @@
+package models
+
+import (
+	"backend/modules/users/models"
+	"gorm.io/gorm"
+)
+
+const (
+	GeneratorTypePassword   = "password"
+	GeneratorTypePassphrase = "passphrase"
+)
+
+type GeneratorPreset struct {
+	gorm.Model
+	UserID           uint        `gorm:"not null;uniqueIndex:idx_generator_presets_user_name"`
+	User             models.User `gorm:"foreignKey:UserID"`
+	Name             string      `gorm:"not null;uniqueIndex:idx_generator_presets_user_name"`
+	Type             string      `gorm:"not null;default:password"`
+	Length           int
+	Lowercase        bool
+	Uppercase        bool
+	Digits           bool
+	Symbols          bool
+	ExcludeAmbiguous bool
+	MinLowercase     int
+	MinUppercase     int
+	MinDigits        int
+	MinSymbols       int
+	Words            int
+	Separator        string
+	Capitalize       bool
+	IncludeNumber    bool
+}
+
+type GeneratorPresetModel struct {
+	DB *gorm.DB
+}
+
+// GetAll returns all generator presets of a given user ID.
+//
+// userId: the ID of the user to retrieve presets for.
+// []GeneratorPreset: the presets ordered by name.
+// error: any error that occurred during the retrieval process.
+func (m *GeneratorPresetModel) GetAll(userId uint) ([]GeneratorPreset, error) {
+	var presets []GeneratorPreset
+
+	err := m.DB.Where("user_id = ?", userId).Order("name").Find(&presets).Error
+
+	return presets, err
+}
+
+// Get returns a single generator preset by its ID for a given user ID.
+//
+// Parameters:
+// - id: the ID of the preset.
+// - userId: the ID of the user who owns the preset.
+//
+// Returns:
+// - GeneratorPreset: the found preset.
+// - error: gorm.ErrRecordNotFound if the preset does not exist or belongs to another user.
+func (m *GeneratorPresetModel) Get(id, userId uint) (GeneratorPreset, error) {
+	var preset GeneratorPreset
+
+	err := m.DB.Where("id = ? AND user_id = ?", id, userId).First(&preset).Error
+
+	return preset, err
+}
+
+// Create stores a new generator preset.
+//
+// Parameters:
+// - preset: the preset to create, UserID must be set.
+//
+// Returns:
+// - GeneratorPreset: the created preset with its ID.
+// - error: an error if there was a problem creating the preset, e.g. a duplicate name.
+func (m *GeneratorPresetModel) Create(preset GeneratorPreset) (GeneratorPreset, error) {
+	err := m.DB.Create(&preset).Error
+	if err != nil {
+		return GeneratorPreset{}, err
+	}
+
+	return preset, nil
+}
+
+// Update overwrites all settings of a generator preset with the given ID and user ID.
+//
+// Parameters:
+// - id: the ID of the preset to update.
+// - userId: the ID of the user who owns the preset.
+// - preset: the new values.
+//
+// Returns:
+// - uint: the ID of the preset that was updated.
+// - error: gorm.ErrRecordNotFound if nothing was updated, or an error if the update operation fails.
+func (m *GeneratorPresetModel) Update(id, userId uint, preset GeneratorPreset) (uint, error) {
+	result := m.DB.Model(&GeneratorPreset{}).
+		Where("id = ? AND user_id = ?", id, userId).
+		Select("Name", "Type", "Length", "Lowercase", "Uppercase", "Digits", "Symbols", "ExcludeAmbiguous",
+			"MinLowercase", "MinUppercase", "MinDigits", "MinSymbols", "Words", "Separator", "Capitalize", "IncludeNumber").
+		Updates(preset)
+	if result.Error != nil {
+		return id, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return id, gorm.ErrRecordNotFound
+	}
+
+	return id, nil
+}
+
+// Delete deletes a generator preset from the database.
+//
+// Presets are deleted permanently, so their names can be reused.
+//
+// Parameters:
+// - id: the ID of the preset to delete.
+// - userId: the ID of the user who owns the preset.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound if nothing was deleted, or an error if the deletion fails.
+func (m *GeneratorPresetModel) Delete(id, userId uint) error {
+	result := m.DB.Unscoped().Where("id = ? AND user_id = ?", id, userId).Delete(&GeneratorPreset{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,23 @@
+package models
+
+// GetHealthMetadata returns the metadata used by the health report for the passwords of a given user.
+//
+// Only the metadata, the strength and breach results and the fingerprint are loaded, nothing is decrypted.
+//
+// Parameters:
+// - userId: the ID of the user.
+//
+// Returns:
+// - []Password: the passwords ordered by name.
+// - error: any error that occurred during the retrieval process.
+func (m *PasswordModel) GetHealthMetadata(userId uint) ([]Password, error) {
+	var passwords []Password
+
+	err := m.DB.
+		Select("id", "updated_at", "user_id", "category_id", "type", "name", "encryption", "strength_score", "breached", "fingerprint").
+		Where("user_id = ?", userId).
+		Order("name, id").
+		Find(&passwords).Error
+
+	return passwords, err
+}
@@ -2,18 +2,659 @@ package models
 
 import (
 	models2 "backend/modules/categories/models"
+	models3 "backend/modules/tags/models"
 	"backend/modules/users/models"
+	"backend/services/encryption"
+	"fmt"
 	"gorm.io/gorm"
+	"time"
+)
+
+const (
+	EncryptionServer = "server"
+	EncryptionClient = "client"
+)
+
+const (
+	TypeLogin    = "login"
+	TypeNote     = "note"
+	TypeCard     = "card"
+	TypeIdentity = "identity"
+	TypeWifi     = "wifi"
 )
 
 type Password struct {
 	gorm.Model
 	UserID     uint             `gorm:"not null"`
 	User       models.User      `gorm:"foreignKey:UserID"`
-	CategoryID uint             `gorm:"nullable"`
+	CategoryID *uint            `gorm:"nullable"`
 	Category   models2.Category `gorm:"foreignKey:CategoryID"`
+	Type       string           `gorm:"not null;default:login;index"`
 	Name       string           `gorm:"not null"`
 	Login      string           `gorm:"not null"`
 	Password   string           `gorm:"not null"`
-	Additional string           `gorm:"not null"`
+	Totp       string           `gorm:"not null;default:''"`
+	// Data holds the encrypted JSON payload of the non-login item types.
+	Data       string `gorm:"not null;default:''"`
+	Encryption string `gorm:"not null;default:server"`
+	// Strength estimate computed from the plaintext before encryption, nil for client-encrypted entries.
+	StrengthScore *int   `gorm:"nullable;index"`
+	StrengthFlags string `gorm:"not null;default:''"`
+	// Number of times the password appears in the local breach dataset, nil when not checked.
+	Breached *int `gorm:"nullable"`
+	// Keyed fingerprint of the secret used to find reused passwords, empty without a secret
+	// and nil when not computed.
+	Fingerprint *string `gorm:"nullable;index"`
+	// Number of days after which the secret must be rotated, overrides the interval of the category.
+	RotationDays *int `gorm:"nullable"`
+	// Time the secret last changed, nil for passwords saved before rotation tracking, see rotated.
+	RotatedAt *time.Time `gorm:"nullable"`
+	// Time the password was last used for autofill, see MarkUsed.
+	LastUsedAt *time.Time `gorm:"nullable"`
+	// Bound is set once the encrypted values are bound to the row, see rowAAD. EncryptLegacy migrates older rows.
+	Bound bool `gorm:"not null;default:false"`
+	// Secret is the plaintext password or key fingerprinted by Create and Update.
+	Secret string          `gorm:"-"`
+	Fields []PasswordField `gorm:"foreignKey:PasswordID;constraint:OnDelete:CASCADE"`
+	URIs   []PasswordURI   `gorm:"foreignKey:PasswordID;constraint:OnDelete:CASCADE"`
+	Tags   []models3.Tag   `gorm:"many2many:password_tags;constraint:OnDelete:CASCADE"`
+	// TagIDs are the tags saved by Create and Update, Tags is only filled when reading.
+	TagIDs []uint `gorm:"-"`
+}
+
+type PasswordFilter struct {
+	CategoryID *uint
+	Type       string
+	Score      *int
+	MaxScore   *int
+	Breached   *bool
+	TagIDs     []uint
+}
+
+type PasswordModel struct {
+	DB *gorm.DB
+	// Cipher replaces the cipher of the owner when set, e.g. with a data key unwrapped for an emergency contact.
+	Cipher *encryption.Cipher
+}
+
+// secretFields returns pointers to the fields that are stored encrypted, keyed by column name.
+//
+// The column name is part of the additional authenticated data, see rowAAD.
+func (p *Password) secretFields() map[string]*string {
+	return map[string]*string{
+		"login":    &p.Login,
+		"password": &p.Password,
+		"totp":     &p.Totp,
+		"data":     &p.Data,
+	}
+}
+
+// GetAll returns all passwords for a given user ID.
+//
+// Parameters:
+// - userId: the ID of the user to retrieve passwords for.
+// - filter: optional category, type, strength score, breach and tag filters, empty fields are ignored.
+//
+// Returns:
+// - []Password: a slice of Password structs.
+// - error: any error that occurred during the retrieval process.
+func (m *PasswordModel) GetAll(userId uint, filter PasswordFilter) ([]Password, error) {
+	var passwords []Password
+
+	query := m.DB.Where("user_id = ?", userId)
+	if filter.CategoryID != nil {
+		query = query.Where("category_id = ?", *filter.CategoryID)
+	}
+
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+
+	if filter.Score != nil {
+		query = query.Where("strength_score = ?", *filter.Score)
+	}
+
+	if filter.MaxScore != nil {
+		query = query.Where("strength_score <= ?", *filter.MaxScore)
+	}
+
+	if filter.Breached != nil {
+		if *filter.Breached {
+			query = query.Where("breached > 0")
+		} else {
+			query = query.Where("breached = 0")
+		}
+	}
+
+	if len(filter.TagIDs) > 0 {
+		query = query.Where("id IN (?)", taggedWithAll(m.DB, filter.TagIDs))
+	}
+
+	err := query.Preload("Fields", orderFields).Preload("URIs", orderURIs).Preload("Tags", orderTags).Order("name").Find(&passwords).Error
+	if err != nil || len(passwords) == 0 {
+		return passwords, err
+	}
+
+	cipher, err := m.getCipher(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range passwords {
+		if err := decryptPassword(&passwords[i], cipher); err != nil {
+			return nil, err
+		}
+	}
+
+	return passwords, nil
+}
+
+// Get returns a single password by its ID for a given user ID.
+//
+// Parameters:
+// - id: the ID of the password.
+// - userId: the ID of the user who owns the password.
+//
+// Returns:
+// - Password: the found password.
+// - error: gorm.ErrRecordNotFound if the password does not exist or belongs to another user.
+func (m *PasswordModel) Get(id, userId uint) (Password, error) {
+	var password Password
+
+	err := m.DB.Preload("Fields", orderFields).Preload("URIs", orderURIs).Preload("Tags", orderTags).
+		Where("id = ? AND user_id = ?", id, userId).
+		First(&password).Error
+	if err != nil {
+		return Password{}, err
+	}
+
+	cipher, err := m.getCipher(userId)
+	if err != nil {
+		return Password{}, err
+	}
+
+	return password, decryptPassword(&password, cipher)
+}
+
+// Create stores a new password.
+//
+// Parameters:
+// - password: the password to create, UserID must be set and TagIDs must belong to the user.
+//
+// Returns:
+// - Password: the created password with its ID.
+// - error: an error if there was a problem creating the password.
+func (m *PasswordModel) Create(password Password) (Password, error) {
+	cipher, err := m.getCipher(password.UserID)
+	if err != nil {
+		return Password{}, err
+	}
+
+	now := time.Now()
+	password.RotatedAt = &now
+
+	plain := password
+
+	err = m.DB.Transaction(func(tx *gorm.DB) error {
+		// The row is inserted without its secrets first, they are bound to its ID.
+		stored := password
+		stored.Fields, stored.URIs = nil, nil
+		for _, value := range stored.secretFields() {
+			*value = ""
+		}
+
+		if err := tx.Omit("Tags").Create(&stored).Error; err != nil {
+			return err
+		}
+
+		password.Model = stored.Model
+		if err := encryptPassword(&password, cipher); err != nil {
+			return err
+		}
+
+		columns := map[string]interface{}{"fingerprint": password.Fingerprint, "bound": password.Bound}
+		for column, value := range password.secretFields() {
+			columns[column] = *value
+		}
+
+		if err := tx.Model(&Password{}).Where("id = ?", password.ID).UpdateColumns(columns).Error; err != nil {
+			return err
+		}
+
+		if err := replaceFields(tx, password.ID, password.Fields); err != nil {
+			return err
+		}
+
+		if err := replaceURIs(tx, password.ID, password.URIs); err != nil {
+			return err
+		}
+
+		if err := replaceTags(tx, password.ID, password.TagIDs); err != nil {
+			return err
+		}
+
+		return indexPassword(tx, password.ID, plain.Login, plain.URIs)
+	})
+	if err != nil {
+		return Password{}, err
+	}
+
+	plain.Model = password.Model
+
+	return plain, nil
+}
+
+// Update overwrites the editable fields of a password with the given ID and user ID.
+//
+// The previous value is kept as a revision, see PasswordRevisionModel.
+// RotatedAt is set when the secret changes.
+//
+// Parameters:
+// - id: the ID of the password to update.
+// - userId: the ID of the user who owns the password.
+// - password: the new values, TagIDs must belong to the user.
+//
+// Returns:
+// - uint: the ID of the password that was updated.
+// - error: gorm.ErrRecordNotFound if nothing was updated, or an error if the update operation fails.
+func (m *PasswordModel) Update(id, userId uint, password Password) (uint, error) {
+	cipher, err := m.getCipher(userId)
+	if err != nil {
+		return id, err
+	}
+
+	login, uris := password.Login, password.URIs
+	password.ID = id
+	if err := encryptPassword(&password, cipher); err != nil {
+		return id, err
+	}
+
+	err = m.DB.Transaction(func(tx *gorm.DB) error {
+		current, err := saveRevision(tx, id, userId, cipher)
+		if err != nil {
+			return err
+		}
+
+		columns := []interface{}{"Type", "Name", "Login", "Password", "Totp", "Data", "Encryption", "StrengthScore", "StrengthFlags", "Breached", "Fingerprint", "RotationDays", "Bound"}
+		if rotated(current, password) {
+			now := time.Now()
+			password.RotatedAt = &now
+			columns = append(columns, "RotatedAt")
+		}
+
+		err = tx.Model(&Password{}).
+			Where("id = ? AND user_id = ?", id, userId).
+			Select("CategoryID", columns...).
+			Updates(password).Error
+		if err != nil {
+			return err
+		}
+
+		if err := replaceFields(tx, id, password.Fields); err != nil {
+			return err
+		}
+
+		if err := replaceURIs(tx, id, password.URIs); err != nil {
+			return err
+		}
+
+		if err := replaceTags(tx, id, password.TagIDs); err != nil {
+			return err
+		}
+
+		return indexPassword(tx, id, login, uris)
+	})
+	if err != nil {
+		return id, err
+	}
+
+	revisionModel := PasswordRevisionModel{DB: m.DB}
+
+	return id, revisionModel.Prune(id)
+}
+
+// Delete moves a password to the trash, see Restore and Purge.
+//
+// Parameters:
+// - id: the ID of the password to delete.
+// - userId: the ID of the user who owns the password.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound if nothing was deleted, or an error if the deletion fails.
+func (m *PasswordModel) Delete(id, userId uint) error {
+	return m.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND user_id = ?", id, userId).Delete(&Password{})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return unindexPassword(tx, id)
+	})
+}
+
+// Move moves a password to another category without saving a revision.
+//
+// Parameters:
+// - id: the ID of the password.
+// - userId: the ID of the user who owns the password.
+// - categoryId: the ID of the category, nil to remove the category.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound if the password does not exist, or any update or index error.
+func (m *PasswordModel) Move(id, userId uint, categoryId *uint) error {
+	return m.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&Password{}).Where("id = ? AND user_id = ?", id, userId).UpdateColumn("category_id", categoryId)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		passwordModel := PasswordModel{DB: tx, Cipher: m.Cipher}
+
+		return passwordModel.Reindex(id, userId)
+	})
+}
+
+// SetFingerprint stores the fingerprint of the secret of a password saved before fingerprints were introduced.
+//
+// Parameters:
+// - id: the ID of the password.
+// - userId: the ID of the user who owns the password.
+// - secret: the plaintext secret, empty when the password has none.
+//
+// Returns an error if the fingerprint cannot be computed or saved.
+func (m *PasswordModel) SetFingerprint(id, userId uint, secret string) error {
+	fingerprint := ""
+
+	if secret != "" {
+		cipher, err := m.getCipher(userId)
+		if err != nil {
+			return err
+		}
+
+		if fingerprint, err = cipher.Fingerprint(secret, "password"); err != nil {
+			return err
+		}
+	}
+
+	return m.DB.Model(&Password{}).Where("id = ?", id).UpdateColumn("fingerprint", fingerprint).Error
+}
+
+// Reindex refreshes the search index entry of a password from its stored values.
+//
+// Parameters:
+// - id: the ID of the password.
+// - userId: the ID of the user who owns the password.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound if the password does not exist, or any decryption or index error.
+func (m *PasswordModel) Reindex(id, userId uint) error {
+	password, err := m.Get(id, userId)
+	if err != nil {
+		return err
+	}
+
+	return indexPassword(m.DB, id, password.Login, password.URIs)
+}
+
+// EncryptLegacy encrypts and binds the values of passwords and revisions stored before their values were bound to their row.
+//
+// Plaintext values saved before encryption was introduced are encrypted, ciphertexts bound to their column name only
+// are re-encrypted with rowAAD. Bound rows whose item data was stored before it was encrypted are encrypted as well.
+// Entries encrypted by the client in the zero-knowledge vault mode are skipped.
+//
+// It does not take any parameters.
+// It returns an error if a row cannot be decrypted, encrypted or saved.
+func (m *PasswordModel) EncryptLegacy() error {
+	var passwords []Password
+
+	legacy := "encryption = ? AND (NOT bound OR (data <> '' AND data NOT LIKE ?))"
+
+	err := m.DB.Unscoped().Where(legacy, EncryptionServer, encryption.Prefix+"%").FindInBatches(&passwords, 100, func(tx *gorm.DB, batch int) error {
+		for _, password := range passwords {
+			if err := m.bindLegacy(password); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}).Error
+	if err != nil {
+		return err
+	}
+
+	var revisions []PasswordRevision
+
+	return m.DB.Unscoped().Where(legacy, EncryptionServer, encryption.Prefix+"%").FindInBatches(&revisions, 100, func(tx *gorm.DB, batch int) error {
+		for _, revision := range revisions {
+			if err := m.bindLegacyRevision(revision); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}).Error
+}
+
+// bindLegacy binds the values of a password and of its custom fields and URIs to its row.
+//
+// Rows that are already bound are opened with rowAAD, their plaintext item data is encrypted.
+func (m *PasswordModel) bindLegacy(password Password) error {
+	cipher, err := m.getCipher(password.UserID)
+	if err != nil {
+		return err
+	}
+
+	return m.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("password_id = ?", password.ID).Find(&password.Fields).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("password_id = ?", password.ID).Find(&password.URIs).Error; err != nil {
+			return err
+		}
+
+		aad := legacyAAD
+		if password.Bound {
+			aad = rowAAD("passwords", password.ID)
+		}
+
+		if err := openValues(&password, cipher, aad); err != nil {
+			return err
+		}
+
+		if err := sealValues(&password, cipher, rowAAD("passwords", password.ID)); err != nil {
+			return err
+		}
+
+		columns := map[string]interface{}{"bound": true}
+		for column, value := range password.secretFields() {
+			columns[column] = *value
+		}
+
+		if err := tx.Unscoped().Model(&Password{}).Where("id = ?", password.ID).UpdateColumns(columns).Error; err != nil {
+			return err
+		}
+
+		for _, field := range password.Fields {
+			if err := tx.Model(&PasswordField{}).Where("id = ?", field.ID).UpdateColumn("value", field.Value).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, uri := range password.URIs {
+			if err := tx.Model(&PasswordURI{}).Where("id = ?", uri.ID).UpdateColumn("uri", uri.URI).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// bindLegacyRevision binds the values of a revision to its row.
+func (m *PasswordModel) bindLegacyRevision(revision PasswordRevision) error {
+	cipher, err := m.getCipher(revision.UserID)
+	if err != nil {
+		return err
+	}
+
+	password, err := revision.toPassword()
+	if err != nil {
+		return err
+	}
+
+	aad := legacyAAD
+	if revision.Bound {
+		aad = rowAAD("password_revisions", revision.ID)
+	}
+
+	if err := openValues(&password, cipher, aad); err != nil {
+		return err
+	}
+
+	if err := sealValues(&password, cipher, rowAAD("password_revisions", revision.ID)); err != nil {
+		return err
+	}
+
+	columns, err := revisionValues(password)
+	if err != nil {
+		return err
+	}
+
+	return m.DB.Unscoped().Model(&PasswordRevision{}).Where("id = ?", revision.ID).UpdateColumns(columns).Error
+}
+
+// getCipher returns the cipher of the user owning the passwords.
+func (m *PasswordModel) getCipher(userId uint) (encryption.Cipher, error) {
+	if m.Cipher != nil {
+		return *m.Cipher, nil
+	}
+
+	userModel := models.UserModel{DB: m.DB}
+
+	return userModel.GetCipher(userId)
+}
+
+// rowAAD returns the additional authenticated data of the encrypted values of a row, keyed by column name.
+//
+// The data is table:column:id, so a ciphertext cannot be moved to another column, another entry or a revision.
+// The custom fields and URIs of a password are bound to the password with the fields and uris columns.
+func rowAAD(table string, id uint) func(column string) string {
+	return func(column string) string {
+		return fmt.Sprintf("%s:%s:%d", table, column, id)
+	}
+}
+
+// legacyAAD returns the additional authenticated data used before the values were bound to their row.
+func legacyAAD(column string) string {
+	switch column {
+	case "fields":
+		return "value"
+	case "uris":
+		return "uri"
+	}
+
+	return column
+}
+
+// encryptPassword encrypts the secret fields, the custom field values and the URIs of a password in place.
+//
+// The values are bound to the ID of the password, which must be set. The custom fields and URIs are replaced
+// with an encrypted copy and the fingerprint of Secret is set.
+// Client-encrypted entries already hold ciphertext and are stored as they are.
+func encryptPassword(password *Password, cipher encryption.Cipher) error {
+	if password.Encryption == EncryptionClient {
+		return nil
+	}
+
+	fingerprint := ""
+	if password.Secret != "" {
+		var err error
+		if fingerprint, err = cipher.Fingerprint(password.Secret, "password"); err != nil {
+			return err
+		}
+	}
+	password.Fingerprint = &fingerprint
+	password.Bound = true
+
+	return sealValues(password, cipher, rowAAD("passwords", password.ID))
+}
+
+// decryptPassword decrypts the secret fields, the custom field values and the URIs of a password in place.
+//
+// Legacy plaintext values that have not been migrated yet and client-encrypted entries are returned as they are.
+func decryptPassword(password *Password, cipher encryption.Cipher) error {
+	return openValues(password, cipher, rowAAD("passwords", password.ID))
+}
+
+// sealValues encrypts the secret fields, the custom field values and the URIs of a server-encrypted password in place.
+//
+// aad returns the additional authenticated data of a column. The custom fields and URIs are replaced with an
+// encrypted copy, so the slices of the caller stay untouched.
+func sealValues(password *Password, cipher encryption.Cipher, aad func(column string) string) error {
+	if password.Encryption == EncryptionClient {
+		return nil
+	}
+
+	for column, value := range password.secretFields() {
+		encrypted, err := cipher.Encrypt(*value, aad(column))
+		if err != nil {
+			return err
+		}
+
+		*value = encrypted
+	}
+
+	fields, err := encryptFields(password.Fields, cipher, aad("fields"))
+	if err != nil {
+		return err
+	}
+	password.Fields = fields
+
+	uris, err := encryptURIs(password.URIs, cipher, aad("uris"))
+	if err != nil {
+		return err
+	}
+	password.URIs = uris
+
+	return nil
+}
+
+// openValues decrypts the values encrypted by sealValues in place, plaintext values are left as they are.
+func openValues(password *Password, cipher encryption.Cipher, aad func(column string) string) error {
+	if password.Encryption == EncryptionClient {
+		return nil
+	}
+
+	for column, value := range password.secretFields() {
+		if !encryption.IsEncrypted(*value) {
+			continue
+		}
+
+		decrypted, err := cipher.Decrypt(*value, aad(column))
+		if err != nil {
+			return err
+		}
+
+		*value = decrypted
+	}
+
+	if err := decryptFields(password.Fields, cipher, aad("fields")); err != nil {
+		return err
+	}
+
+	return decryptURIs(password.URIs, cipher, aad("uris"))
+}
+
+// orderFields sorts preloaded custom fields by their position.
+func orderFields(db *gorm.DB) *gorm.DB {
+	return db.Order("position")
 }
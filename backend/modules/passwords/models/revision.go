@@ -0,0 +1,356 @@
+package models
+
+import (
+	"backend/services/config"
+	"backend/services/encryption"
+	"encoding/json"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// PasswordRevision is a previous value of a password, stored with the same encryption as the entry.
+//
+// The encrypted values are bound to the revision, see rowAAD, so they are re-encrypted when they are copied.
+type PasswordRevision struct {
+	gorm.Model
+	PasswordID uint     `gorm:"not null;index"`
+	Entry      Password `gorm:"foreignKey:PasswordID;constraint:OnDelete:CASCADE"`
+	UserID     uint     `gorm:"not null;index"`
+	CategoryID *uint    `gorm:"nullable"`
+	Type       string   `gorm:"not null;default:login"`
+	Name       string   `gorm:"not null"`
+	Login      string   `gorm:"not null"`
+	Password   string   `gorm:"not null"`
+	// Fields is a JSON snapshot of the custom fields with their values encrypted as stored.
+	Fields string `gorm:"not null;default:'[]'"`
+	// URIs is a JSON snapshot of the URIs encrypted as stored.
+	URIs          string  `gorm:"not null;default:'[]'"`
+	Totp          string  `gorm:"not null;default:''"`
+	Data          string  `gorm:"not null;default:''"`
+	Encryption    string  `gorm:"not null;default:server"`
+	StrengthScore *int    `gorm:"nullable"`
+	StrengthFlags string  `gorm:"not null;default:''"`
+	Breached      *int    `gorm:"nullable"`
+	Fingerprint   *string `gorm:"nullable"`
+	// Bound is set once the encrypted values are bound to the row, see PasswordModel.EncryptLegacy.
+	Bound bool `gorm:"not null;default:false"`
+	// Decrypted holds the decrypted custom fields returned by Get.
+	Decrypted []PasswordField `gorm:"-"`
+	// DecryptedURIs holds the decrypted URIs returned by Get.
+	DecryptedURIs []PasswordURI `gorm:"-"`
+}
+
+type PasswordRevisionModel struct {
+	DB *gorm.DB
+}
+
+// GetAll returns the revisions of a password, newest first.
+//
+// Parameters:
+// - passwordId: the ID of the password.
+// - userId: the ID of the user who owns the password.
+//
+// Returns:
+// - []PasswordRevision: the revisions, secret fields stay encrypted.
+// - error: any error that occurred during the retrieval process.
+func (m *PasswordRevisionModel) GetAll(passwordId, userId uint) ([]PasswordRevision, error) {
+	var revisions []PasswordRevision
+
+	err := m.DB.Scopes(retained).
+		Where("password_id = ? AND user_id = ?", passwordId, userId).
+		Order("id DESC").
+		Find(&revisions).Error
+
+	return revisions, err
+}
+
+// Get returns a single decrypted revision of a password.
+//
+// Parameters:
+// - id: the ID of the revision.
+// - passwordId: the ID of the password.
+// - userId: the ID of the user who owns the password.
+//
+// Returns:
+// - PasswordRevision: the revision with decrypted secret fields.
+// - error: gorm.ErrRecordNotFound if the revision does not exist.
+func (m *PasswordRevisionModel) Get(id, passwordId, userId uint) (PasswordRevision, error) {
+	var revision PasswordRevision
+
+	err := m.DB.Scopes(retained).Where("id = ? AND password_id = ? AND user_id = ?", id, passwordId, userId).First(&revision).Error
+	if err != nil {
+		return PasswordRevision{}, err
+	}
+
+	passwordModel := PasswordModel{DB: m.DB}
+
+	cipher, err := passwordModel.getCipher(userId)
+	if err != nil {
+		return PasswordRevision{}, err
+	}
+
+	password, err := revision.toPassword()
+	if err != nil {
+		return PasswordRevision{}, err
+	}
+
+	if err := openValues(&password, cipher, rowAAD("password_revisions", revision.ID)); err != nil {
+		return PasswordRevision{}, err
+	}
+
+	revision.Login, revision.Password, revision.Totp = password.Login, password.Password, password.Totp
+	revision.Data = password.Data
+	revision.Decrypted = password.Fields
+	revision.DecryptedURIs = password.URIs
+
+	return revision, nil
+}
+
+// Restore makes a revision the current value of its password.
+//
+// The current value is saved as a new revision first, so a restore can be undone.
+//
+// Parameters:
+// - id: the ID of the revision.
+// - passwordId: the ID of the password.
+// - userId: the ID of the user who owns the password.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound if the revision does not exist, or any update error.
+func (m *PasswordRevisionModel) Restore(id, passwordId, userId uint) error {
+	passwordModel := PasswordModel{DB: m.DB}
+
+	cipher, err := passwordModel.getCipher(userId)
+	if err != nil {
+		return err
+	}
+
+	err = m.DB.Transaction(func(tx *gorm.DB) error {
+		var revision PasswordRevision
+
+		err := tx.Scopes(retained).Where("id = ? AND password_id = ? AND user_id = ?", id, passwordId, userId).First(&revision).Error
+		if err != nil {
+			return err
+		}
+
+		if _, err := saveRevision(tx, passwordId, userId, cipher); err != nil {
+			return err
+		}
+
+		password, err := revision.toPassword()
+		if err != nil {
+			return err
+		}
+
+		if err := openValues(&password, cipher, rowAAD("password_revisions", revision.ID)); err != nil {
+			return err
+		}
+
+		if err := sealValues(&password, cipher, rowAAD("passwords", passwordId)); err != nil {
+			return err
+		}
+		password.Bound = true
+
+		err = tx.Model(&Password{}).
+			Where("id = ? AND user_id = ?", passwordId, userId).
+			Select("CategoryID", "Type", "Name", "Login", "Password", "Totp", "Data", "Encryption", "StrengthScore", "StrengthFlags", "Breached", "Fingerprint", "Bound").
+			Updates(password).Error
+		if err != nil {
+			return err
+		}
+
+		if err := replaceFields(tx, passwordId, password.Fields); err != nil {
+			return err
+		}
+
+		return replaceURIs(tx, passwordId, password.URIs)
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := passwordModel.Reindex(passwordId, userId); err != nil {
+		return err
+	}
+
+	return m.Prune(passwordId)
+}
+
+// Prune deletes the revisions of a password that exceed the configured retention, after a new revision is saved.
+//
+// REVISION_MAX_COUNT limits the number of kept revisions (20 by default) and REVISION_MAX_AGE_DAYS
+// their age (365 by default), 0 disables a limit. Revisions of passwords that are not edited anymore
+// are deleted by PruneExpired.
+//
+// Parameters:
+// - passwordId: the ID of the password.
+//
+// Returns an error if the deletion fails.
+func (m *PasswordRevisionModel) Prune(passwordId uint) error {
+	maxCount := config.GetInt("REVISION_MAX_COUNT", 20)
+	maxAge := config.GetDays("REVISION_MAX_AGE_DAYS", 365)
+
+	if maxAge > 0 {
+		err := m.DB.Unscoped().
+			Where("password_id = ? AND created_at < ?", passwordId, time.Now().Add(-maxAge)).
+			Delete(&PasswordRevision{}).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	if maxCount > 0 {
+		kept := m.DB.Model(&PasswordRevision{}).
+			Select("id").
+			Where("password_id = ?", passwordId).
+			Order("id DESC").
+			Limit(maxCount)
+
+		err := m.DB.Unscoped().
+			Where("password_id = ? AND id NOT IN (?)", passwordId, kept).
+			Delete(&PasswordRevision{}).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PruneExpired deletes the revisions of all passwords that are older than REVISION_MAX_AGE_DAYS.
+//
+// It does not take any parameters.
+// It returns the number of deleted revisions and an error if the deletion fails.
+func (m *PasswordRevisionModel) PruneExpired() (int64, error) {
+	maxAge := config.GetDays("REVISION_MAX_AGE_DAYS", 365)
+	if maxAge <= 0 {
+		return 0, nil
+	}
+
+	result := m.DB.Unscoped().Where("created_at < ?", time.Now().Add(-maxAge)).Delete(&PasswordRevision{})
+
+	return result.RowsAffected, result.Error
+}
+
+// retained hides the revisions older than REVISION_MAX_AGE_DAYS that PruneExpired has not deleted yet.
+func retained(db *gorm.DB) *gorm.DB {
+	maxAge := config.GetDays("REVISION_MAX_AGE_DAYS", 365)
+	if maxAge <= 0 {
+		return db
+	}
+
+	return db.Where("created_at >= ?", time.Now().Add(-maxAge))
+}
+
+// toPassword copies the versioned fields of a revision to a password.
+func (r PasswordRevision) toPassword() (Password, error) {
+	var fields []PasswordField
+	if err := json.Unmarshal([]byte(r.Fields), &fields); err != nil {
+		return Password{}, err
+	}
+
+	var uris []PasswordURI
+	if err := json.Unmarshal([]byte(r.URIs), &uris); err != nil {
+		return Password{}, err
+	}
+
+	return Password{
+		UserID:        r.UserID,
+		CategoryID:    r.CategoryID,
+		Type:          r.Type,
+		Name:          r.Name,
+		Login:         r.Login,
+		Password:      r.Password,
+		Fields:        fields,
+		URIs:          uris,
+		Totp:          r.Totp,
+		Data:          r.Data,
+		Encryption:    r.Encryption,
+		StrengthScore: r.StrengthScore,
+		StrengthFlags: r.StrengthFlags,
+		Breached:      r.Breached,
+		Fingerprint:   r.Fingerprint,
+	}, nil
+}
+
+// saveRevision copies the current stored value of a password to a new revision and returns that value.
+//
+// The secret fields are re-encrypted for the revision, so the revision stays encrypted and its values cannot be
+// swapped with those of the entry. The password row is locked until the surrounding transaction ends.
+func saveRevision(tx *gorm.DB, passwordId, userId uint, cipher encryption.Cipher) (Password, error) {
+	var current Password
+
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND user_id = ?", passwordId, userId).
+		First(&current).Error
+	if err != nil {
+		return current, err
+	}
+
+	err = tx.Where("password_id = ?", passwordId).Order("position").Find(&current.Fields).Error
+	if err != nil {
+		return current, err
+	}
+
+	err = tx.Where("password_id = ?", passwordId).Order("position").Find(&current.URIs).Error
+	if err != nil {
+		return current, err
+	}
+
+	revision := PasswordRevision{
+		PasswordID:    current.ID,
+		UserID:        current.UserID,
+		CategoryID:    current.CategoryID,
+		Type:          current.Type,
+		Name:          current.Name,
+		Encryption:    current.Encryption,
+		StrengthScore: current.StrengthScore,
+		StrengthFlags: current.StrengthFlags,
+		Breached:      current.Breached,
+		Fingerprint:   current.Fingerprint,
+	}
+
+	if err := tx.Create(&revision).Error; err != nil {
+		return current, err
+	}
+
+	values := current
+	values.Fields = append([]PasswordField{}, current.Fields...)
+	values.URIs = append([]PasswordURI{}, current.URIs...)
+
+	if err := openValues(&values, cipher, rowAAD("passwords", current.ID)); err != nil {
+		return current, err
+	}
+
+	if err := sealValues(&values, cipher, rowAAD("password_revisions", revision.ID)); err != nil {
+		return current, err
+	}
+
+	columns, err := revisionValues(values)
+	if err != nil {
+		return current, err
+	}
+
+	return current, tx.Model(&PasswordRevision{}).Where("id = ?", revision.ID).UpdateColumns(columns).Error
+}
+
+// revisionValues returns the columns of a revision holding the encrypted values of a password.
+func revisionValues(password Password) (map[string]interface{}, error) {
+	fields, err := json.Marshal(password.Fields)
+	if err != nil {
+		return nil, err
+	}
+
+	uris, err := json.Marshal(password.URIs)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := map[string]interface{}{"fields": string(fields), "uris": string(uris), "bound": true}
+	for column, value := range password.secretFields() {
+		columns[column] = *value
+	}
+
+	return columns, nil
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+const (
+	// rotationInterval is the rotation interval of a password in days, the one of its category by default.
+	rotationInterval = "COALESCE(passwords.rotation_days, categories.rotation_days)"
+	// lastRotation falls back to the creation time for passwords saved before rotation tracking.
+	lastRotation = "COALESCE(passwords.rotated_at, passwords.created_at)"
+	rotationDue  = "(" + lastRotation + " + " + rotationInterval + " * INTERVAL '1 day')"
+)
+
+type RotationDue struct {
+	ID           uint
+	UserID       uint
+	CategoryID   *uint
+	Type         string
+	Name         string
+	RotationDays int
+	RotatedAt    time.Time
+	DueAt        time.Time
+}
+
+// GetDueRotations returns the passwords of a given user that must be rotated before a given time.
+//
+// Parameters:
+// - userId: the ID of the user.
+// - before: the due date limit, the current time for overdue passwords only.
+//
+// Returns:
+// - []RotationDue: the passwords with a rotation policy due before the limit, earliest first.
+// - error: any error that occurred during the retrieval process.
+func (m *PasswordModel) GetDueRotations(userId uint, before time.Time) ([]RotationDue, error) {
+	var due []RotationDue
+
+	err := dueRotations(m.DB, before).Where("passwords.user_id = ?", userId).Find(&due).Error
+
+	return due, err
+}
+
+// GetAllDueRotations returns the passwords of all users that must be rotated before a given time.
+//
+// before: the due date limit.
+// Returns the passwords with a rotation policy due before the limit or an error if the query fails.
+func (m *PasswordModel) GetAllDueRotations(before time.Time) ([]RotationDue, error) {
+	var due []RotationDue
+
+	err := dueRotations(m.DB, before).Find(&due).Error
+
+	return due, err
+}
+
+// dueRotations builds the query of the passwords with a rotation policy due before a given time.
+//
+// The policy of a password overrides the one of its category, deleted categories have no policy.
+func dueRotations(db *gorm.DB, before time.Time) *gorm.DB {
+	return db.Table("passwords").
+		Select("passwords.id, passwords.user_id, passwords.category_id, passwords.type, passwords.name, "+
+			rotationInterval+" AS rotation_days, "+lastRotation+" AS rotated_at, "+rotationDue+" AS due_at").
+		Joins("LEFT JOIN categories ON categories.id = passwords.category_id AND categories.deleted_at IS NULL").
+		Where("passwords.deleted_at IS NULL AND "+rotationInterval+" IS NOT NULL AND "+rotationDue+" <= ?", before).
+		Order("due_at, passwords.id")
+}
+
+// rotated reports whether an update changes the secret of a password.
+//
+// Server-encrypted secrets are compared by fingerprint. The server cannot decrypt client-encrypted ones,
+// so their ciphertext is compared instead and clients must send it unchanged when the secret did not change.
+func rotated(current, updated Password) bool {
+	if current.Encryption != updated.Encryption {
+		return false
+	}
+
+	if updated.Encryption == EncryptionClient {
+		return current.Password != updated.Password || (updated.Type == TypeWifi && current.Data != updated.Data)
+	}
+
+	if updated.Fingerprint == nil {
+		return false
+	}
+
+	return current.Fingerprint == nil || *current.Fingerprint != *updated.Fingerprint
+}
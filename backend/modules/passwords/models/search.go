@@ -0,0 +1,342 @@
+package models
+
+import (
+	"backend/modules/users/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"net/url"
+	"strings"
+)
+
+// searchSimilarity is the minimal trigram word similarity of a fuzzy match, it tolerates about one typo per word.
+const searchSimilarity = "0.5"
+
+// searchDocument is the text matched by the trigram index.
+const searchDocument = "(name || ' ' || category || ' ' || tags || ' ' || hosts || ' ' || login)"
+
+// searchVector is the weighted full-text document, punctuation is replaced with spaces so that
+// e-mail addresses and host names are split into words.
+const searchVector = "(setweight(to_tsvector('simple', regexp_replace(name, '[^[:alnum:]]+', ' ', 'g')), 'A') || " +
+	"setweight(to_tsvector('simple', regexp_replace(category, '[^[:alnum:]]+', ' ', 'g')), 'B') || " +
+	"setweight(to_tsvector('simple', regexp_replace(tags, '[^[:alnum:]]+', ' ', 'g')), 'B') || " +
+	"setweight(to_tsvector('simple', regexp_replace(hosts, '[^[:alnum:]]+', ' ', 'g')), 'B') || " +
+	"setweight(to_tsvector('simple', regexp_replace(login, '[^[:alnum:]]+', ' ', 'g')), 'C'))"
+
+// PasswordSearch is the search index entry of a password.
+//
+// It only holds metadata that is not encrypted: the name, the category name and the tag names, and the host names
+// of the URIs of server-encrypted entries. Paths and queries of the URIs are never indexed, since they may hold
+// tokens. The login is copied in plaintext only when the owner opted in with User.SearchLogins, and never for
+// client-encrypted entries.
+type PasswordSearch struct {
+	PasswordID uint     `gorm:"primaryKey;autoIncrement:false"`
+	Entry      Password `gorm:"foreignKey:PasswordID;constraint:OnDelete:CASCADE"`
+	UserID     uint     `gorm:"not null;index"`
+	CategoryID *uint    `gorm:"nullable;index"`
+	Type       string   `gorm:"not null"`
+	Name       string   `gorm:"not null"`
+	Category   string   `gorm:"not null;default:''"`
+	Tags       string   `gorm:"not null;default:''"`
+	// Hosts holds the host names of the URIs separated by spaces.
+	Hosts string `gorm:"not null;default:''"`
+	Login string `gorm:"not null;default:''"`
+}
+
+// TableName keeps the index in a single password_search table.
+func (PasswordSearch) TableName() string {
+	return "password_search"
+}
+
+type SearchFilter struct {
+	CategoryID *uint
+	Type       string
+	TagIDs     []uint
+}
+
+type SearchResult struct {
+	PasswordSearch
+	Rank float64
+}
+
+type SearchModel struct {
+	DB *gorm.DB
+}
+
+// Migrate creates the search indexes and indexes the passwords saved before search was introduced.
+//
+// The pg_trgm extension is created if needed, which requires the CREATE privilege on the database.
+// When the indexes are upgraded to include the URI hosts, the entries of all users are indexed again.
+//
+// It does not take any parameters.
+// It returns an error if an index cannot be created or filled.
+func (m *SearchModel) Migrate() error {
+	var upgrade bool
+
+	err := m.DB.Raw("SELECT to_regclass('idx_password_search_vector_v3') IS NULL").Scan(&upgrade).Error
+	if err != nil {
+		return err
+	}
+
+	statements := []string{
+		"CREATE EXTENSION IF NOT EXISTS pg_trgm",
+		// The indexes are versioned, the expressions of the previous versions did not include the tags and hosts.
+		"DROP INDEX IF EXISTS idx_password_search_vector",
+		"DROP INDEX IF EXISTS idx_password_search_trigram",
+		"DROP INDEX IF EXISTS idx_password_search_vector_v2",
+		"DROP INDEX IF EXISTS idx_password_search_trigram_v2",
+		"CREATE INDEX IF NOT EXISTS idx_password_search_vector_v3 ON password_search USING gin (" + searchVector + ")",
+		"CREATE INDEX IF NOT EXISTS idx_password_search_trigram_v3 ON password_search USING gin (" + searchDocument + " gin_trgm_ops)",
+	}
+
+	for _, statement := range statements {
+		if err := m.DB.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+
+	var ids []uint
+
+	err = m.DB.Model(&Password{}).
+		Where("NOT EXISTS (SELECT 1 FROM password_search WHERE password_search.password_id = passwords.id)").
+		Pluck("id", &ids).Error
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if err := indexPassword(m.DB, id, "", nil); err != nil {
+			return err
+		}
+	}
+
+	if !upgrade {
+		return nil
+	}
+
+	var userIds []uint
+
+	err = m.DB.Model(&Password{}).Where("encryption = ?", EncryptionServer).Distinct().Pluck("user_id", &userIds).Error
+	if err != nil {
+		return err
+	}
+
+	for _, userId := range userIds {
+		if err := m.reindex(userId); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Search returns the passwords of a user matching a query, best matches first.
+//
+// A password matches when its metadata contains words starting with every term, or when the query
+// is similar enough to a part of it, so small typos are tolerated.
+//
+// Parameters:
+// - userId: the ID of the user.
+// - query: the query as typed by the user.
+// - terms: the lowercase words of the query, matched as prefixes, they may only contain letters and digits.
+// - filter: optional category, type and tag filters, a password must have all the tags.
+// - limit: the maximal number of results.
+//
+// Returns:
+// - []SearchResult: the matching index entries with their rank.
+// - error: any error that occurred during the search.
+func (m *SearchModel) Search(userId uint, query string, terms []string, filter SearchFilter, limit int) ([]SearchResult, error) {
+	var results []SearchResult
+
+	tsquery := ""
+	for i, term := range terms {
+		if i > 0 {
+			tsquery += " & "
+		}
+		tsquery += "'" + term + "':*"
+	}
+
+	err := m.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec("SELECT set_config('pg_trgm.word_similarity_threshold', ?, true)", searchSimilarity).Error
+		if err != nil {
+			return err
+		}
+
+		match := tx.Where("? <% "+searchDocument, query)
+		rank := "word_similarity(?, " + searchDocument + ")"
+		rankArgs := []interface{}{query}
+		if tsquery != "" {
+			match = match.Or(searchVector+" @@ to_tsquery('simple', ?)", tsquery)
+			rank = "ts_rank(" + searchVector + ", to_tsquery('simple', ?)) + " + rank
+			rankArgs = []interface{}{tsquery, query}
+		}
+
+		search := tx.Model(&PasswordSearch{}).
+			Select("*, "+rank+" AS rank", rankArgs...).
+			Where("user_id = ?", userId).
+			Where(match)
+
+		if filter.CategoryID != nil {
+			search = search.Where("category_id = ?", *filter.CategoryID)
+		}
+
+		if filter.Type != "" {
+			search = search.Where("type = ?", filter.Type)
+		}
+
+		if len(filter.TagIDs) > 0 {
+			search = search.Where("password_id IN (?)", taggedWithAll(tx, filter.TagIDs))
+		}
+
+		return search.Order("rank DESC, name").Limit(limit).Find(&results).Error
+	})
+
+	return results, err
+}
+
+// UpdateCategory updates the category name of the indexed passwords after a category was renamed or deleted.
+//
+// Parameters:
+// - categoryId: the ID of the category.
+// - userId: the ID of the user who owns the category.
+// - name: the new name, empty for a deleted category.
+//
+// Returns an error if the update fails.
+func (m *SearchModel) UpdateCategory(categoryId, userId uint, name string) error {
+	return m.DB.Model(&PasswordSearch{}).
+		Where("category_id = ? AND user_id = ?", categoryId, userId).
+		Update("category", name).Error
+}
+
+// RefreshTags updates the tag names of indexed passwords after their tags changed.
+//
+// passwordIds: the IDs of the passwords.
+// Returns an error if the update fails.
+func (m *SearchModel) RefreshTags(passwordIds []uint) error {
+	return refreshTags(m.DB, passwordIds)
+}
+
+// SetSearchLogins enables or disables the indexing of the logins of a user.
+//
+// Enabling it copies the decrypted logins of the server-encrypted entries to the index,
+// disabling it removes them.
+//
+// Parameters:
+// - userId: the ID of the user.
+// - enabled: whether logins are indexed.
+//
+// Returns an error if a login cannot be decrypted or the index cannot be updated.
+func (m *SearchModel) SetSearchLogins(userId uint, enabled bool) error {
+	err := m.DB.Model(&models.User{}).Where("id = ?", userId).Update("search_logins", enabled).Error
+	if err != nil {
+		return err
+	}
+
+	if !enabled {
+		return m.DB.Model(&PasswordSearch{}).Where("user_id = ?", userId).Update("login", "").Error
+	}
+
+	return m.reindex(userId)
+}
+
+// reindex refreshes the search index entries of all passwords of a user from their decrypted values.
+func (m *SearchModel) reindex(userId uint) error {
+	passwordModel := PasswordModel{DB: m.DB}
+
+	passwords, err := passwordModel.GetAll(userId, PasswordFilter{})
+	if err != nil {
+		return err
+	}
+
+	for _, password := range passwords {
+		if err := indexPassword(m.DB, password.ID, password.Login, password.URIs); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// indexPassword creates or refreshes the search index entry of a password.
+//
+// Parameters:
+// - tx: the database connection or transaction.
+// - passwordId: the ID of the password.
+// - login: the plaintext login, it is only stored when the owner opted in and the entry is server-encrypted.
+// - uris: the decrypted URIs, their host names are only stored when the entry is server-encrypted.
+//
+// Returns an error if the entry cannot be saved.
+func indexPassword(tx *gorm.DB, passwordId uint, login string, uris []PasswordURI) error {
+	var entry PasswordSearch
+
+	err := tx.Model(&Password{}).
+		Select("passwords.id AS password_id, passwords.user_id, passwords.category_id, passwords.type, passwords.name, "+
+			"COALESCE(categories.name, '') AS category, "+searchTags("passwords.id")+" AS tags, "+
+			"CASE WHEN passwords.encryption = ? THEN ? ELSE '' END AS hosts, "+
+			"CASE WHEN users.search_logins AND passwords.encryption = ? THEN ? ELSE '' END AS login",
+			EncryptionServer, searchHosts(uris), EncryptionServer, login).
+		Joins("JOIN users ON users.id = passwords.user_id").
+		Joins("LEFT JOIN categories ON categories.id = passwords.category_id AND categories.deleted_at IS NULL").
+		Where("passwords.id = ?", passwordId).
+		Take(&entry).Error
+	if err != nil {
+		return err
+	}
+
+	return tx.Clauses(clause.OnConflict{UpdateAll: true}).Omit(clause.Associations).Create(&entry).Error
+}
+
+// refreshTags updates the tag names of indexed passwords.
+func refreshTags(tx *gorm.DB, passwordIds []uint) error {
+	if len(passwordIds) == 0 {
+		return nil
+	}
+
+	return tx.Model(&PasswordSearch{}).
+		Where("password_id IN ?", passwordIds).
+		Update("tags", gorm.Expr(searchTags("password_search.password_id"))).Error
+}
+
+// searchTags returns the expression aggregating the tag names of the password whose ID is in the given column.
+func searchTags(passwordId string) string {
+	return "COALESCE((SELECT string_agg(tags.name, ' ' ORDER BY tags.name) FROM password_tags " +
+		"JOIN tags ON tags.id = password_tags.tag_id WHERE password_tags.password_id = " + passwordId + "), '')"
+}
+
+// searchHosts returns the distinct host names of URIs separated by spaces.
+//
+// The https scheme is assumed for URIs without one, as in "example.com/login". Regular expressions are skipped.
+func searchHosts(uris []PasswordURI) string {
+	var hosts []string
+	seen := map[string]bool{}
+
+	for _, uri := range uris {
+		if uri.Match == MatchRegex {
+			continue
+		}
+
+		value := uri.URI
+		if !strings.Contains(value, "://") {
+			value = "https://" + value
+		}
+
+		parsed, err := url.Parse(value)
+		if err != nil {
+			continue
+		}
+
+		host := strings.ToLower(parsed.Hostname())
+		if host == "" || seen[host] {
+			continue
+		}
+
+		seen[host] = true
+		hosts = append(hosts, host)
+	}
+
+	return strings.Join(hosts, " ")
+}
+
+// unindexPassword removes the search index entry of a password.
+func unindexPassword(tx *gorm.DB, passwordId uint) error {
+	return tx.Where("password_id = ?", passwordId).Delete(&PasswordSearch{}).Error
+}
@@ -0,0 +1,413 @@
+package models
+
+import (
+	"backend/modules/users/models"
+	"backend/services/encryption"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	PermissionView = "view"
+	PermissionEdit = "edit"
+	// PermissionViewWithoutReveal shows the entry with its secrets masked.
+	PermissionViewWithoutReveal = "view_without_reveal"
+)
+
+var Permissions = []string{PermissionView, PermissionEdit, PermissionViewWithoutReveal}
+
+// SharedPassword holds the key of a password shared with other users and the shared content.
+//
+// The entry key is random and sealed to the public key of each user who has access, the owner included.
+// Snapshot is the content of the password encrypted with the entry key, it is refreshed when the password changes.
+type SharedPassword struct {
+	gorm.Model
+	PasswordID uint     `gorm:"not null;uniqueIndex"`
+	Entry      Password `gorm:"foreignKey:PasswordID;constraint:OnDelete:CASCADE"`
+	UserID     uint     `gorm:"not null;index"`
+	// OwnerKey is the entry key sealed to the public key of the owner.
+	OwnerKey string `gorm:"not null"`
+	Snapshot string `gorm:"not null"`
+	// Bound is false for rows whose keys and content were encrypted before they were bound to their row, see BindLegacy.
+	Bound bool `gorm:"not null;default:false"`
+}
+
+// PasswordShare grants a recipient access to a shared password.
+type PasswordShare struct {
+	gorm.Model
+	PasswordID  uint        `gorm:"not null;uniqueIndex:idx_password_shares_recipient"`
+	Entry       Password    `gorm:"foreignKey:PasswordID;constraint:OnDelete:CASCADE"`
+	UserID      uint        `gorm:"not null;index"`
+	Owner       models.User `gorm:"foreignKey:UserID"`
+	RecipientID uint        `gorm:"not null;index;uniqueIndex:idx_password_shares_recipient"`
+	Recipient   models.User `gorm:"foreignKey:RecipientID;constraint:OnDelete:CASCADE"`
+	Permission  string      `gorm:"not null"`
+	// EntryKey is the entry key sealed to the public key of the recipient.
+	EntryKey string `gorm:"not null"`
+	// Snapshot holds the decrypted shared content returned by GetReceived.
+	Snapshot []byte `gorm:"-"`
+}
+
+type ShareModel struct {
+	DB *gorm.DB
+}
+
+// GetAll returns the shares of a password with their recipients.
+//
+// Parameters:
+// - passwordId: the ID of the password.
+// - userId: the ID of the user who owns the password.
+//
+// Returns:
+// - []PasswordShare: the shares, oldest first.
+// - error: any error that occurred during the retrieval process.
+func (m *ShareModel) GetAll(passwordId, userId uint) ([]PasswordShare, error) {
+	var shares []PasswordShare
+
+	err := m.DB.Preload("Recipient", selectUser).
+		Where("password_id = ? AND user_id = ?", passwordId, userId).
+		Order("id").
+		Find(&shares).Error
+
+	return shares, err
+}
+
+// Share gives a recipient access to a password, or changes the permission of an existing share.
+//
+// The entry key is created on the first share of the password.
+//
+// Parameters:
+// - passwordId: the ID of the password.
+// - userId: the ID of the user who owns the password.
+// - recipientId: the ID of the recipient.
+// - permission: PermissionView, PermissionEdit or PermissionViewWithoutReveal.
+// - snapshot: the current shared content of the password.
+//
+// Returns:
+// - PasswordShare: the share.
+// - error: any key, encryption or database error.
+func (m *ShareModel) Share(passwordId, userId, recipientId uint, permission string, snapshot []byte) (PasswordShare, error) {
+	share := PasswordShare{PasswordID: passwordId, UserID: userId, RecipientID: recipientId, Permission: permission}
+
+	err := m.DB.Transaction(func(tx *gorm.DB) error {
+		var shared SharedPassword
+
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("password_id = ?", passwordId).Limit(1).Find(&shared).Error
+		if err != nil {
+			return err
+		}
+
+		var entryKey []byte
+		if shared.ID == 0 {
+			if entryKey, err = encryption.GenerateKey(); err != nil {
+				return err
+			}
+		} else if entryKey, err = m.openOwnerKey(tx, shared); err != nil {
+			return err
+		}
+
+		if shared, err = m.saveKey(tx, shared, passwordId, userId, entryKey, snapshot); err != nil {
+			return err
+		}
+
+		// The entry key is bound to the ID of the share, so it is sealed once the row exists.
+		err = tx.Omit(clause.Associations).Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "password_id"}, {Name: "recipient_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"permission", "updated_at"}),
+		}).Create(&share).Error
+		if err != nil {
+			return err
+		}
+
+		if share.EntryKey, err = sealKey(tx, recipientId, entryKey, shared.entryKeyAAD(share.ID)); err != nil {
+			return err
+		}
+
+		return tx.Model(&PasswordShare{}).Where("id = ?", share.ID).Update("entry_key", share.EntryKey).Error
+	})
+
+	return share, err
+}
+
+// Refresh encrypts the current content of a shared password, passwords that are not shared are skipped.
+//
+// Parameters:
+// - passwordId: the ID of the password.
+// - userId: the ID of the user who owns the password.
+// - snapshot: the current shared content of the password.
+//
+// Returns an error if the content cannot be encrypted or saved.
+func (m *ShareModel) Refresh(passwordId, userId uint, snapshot []byte) error {
+	return m.DB.Transaction(func(tx *gorm.DB) error {
+		var shared SharedPassword
+
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("password_id = ? AND user_id = ?", passwordId, userId).Limit(1).Find(&shared).Error
+		if err != nil || shared.ID == 0 {
+			return err
+		}
+
+		entryKey, err := m.openOwnerKey(tx, shared)
+		if err != nil {
+			return err
+		}
+
+		_, err = m.saveKey(tx, shared, passwordId, userId, entryKey, snapshot)
+
+		return err
+	})
+}
+
+// Revoke removes a share and rotates the entry key, so a revoked recipient cannot read later changes.
+//
+// The key is dropped with the shared content when no share is left.
+//
+// Parameters:
+// - id: the ID of the share.
+// - passwordId: the ID of the password.
+// - userId: the ID of the user who owns the password.
+// - snapshot: the current shared content of the password.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound if the share does not exist, or any key, encryption or database error.
+func (m *ShareModel) Revoke(id, passwordId, userId uint, snapshot []byte) error {
+	return m.DB.Transaction(func(tx *gorm.DB) error {
+		var shared SharedPassword
+
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("password_id = ? AND user_id = ?", passwordId, userId).First(&shared).Error
+		if err != nil {
+			return err
+		}
+
+		result := tx.Unscoped().Where("id = ? AND password_id = ? AND user_id = ?", id, passwordId, userId).Delete(&PasswordShare{})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		var shares []PasswordShare
+		if err := tx.Where("password_id = ?", passwordId).Find(&shares).Error; err != nil {
+			return err
+		}
+
+		if len(shares) == 0 {
+			return tx.Unscoped().Delete(&shared).Error
+		}
+
+		entryKey, err := encryption.GenerateKey()
+		if err != nil {
+			return err
+		}
+
+		if shared, err = m.saveKey(tx, shared, passwordId, userId, entryKey, snapshot); err != nil {
+			return err
+		}
+
+		return sealShares(tx, shared, shares, entryKey)
+	})
+}
+
+// BindLegacy binds the keys and content of passwords shared before they were bound to their row.
+//
+// The entry key is kept and sealed again to the owner and every recipient.
+//
+// It does not take any parameters.
+// It returns an error if a key cannot be opened or sealed, or a row cannot be saved.
+func (m *ShareModel) BindLegacy() error {
+	var legacy []SharedPassword
+
+	return m.DB.Where("NOT bound").FindInBatches(&legacy, 100, func(tx *gorm.DB, batch int) error {
+		for _, shared := range legacy {
+			err := m.DB.Transaction(func(tx *gorm.DB) error {
+				entryKey, err := m.openOwnerKey(tx, shared)
+				if err != nil {
+					return err
+				}
+
+				snapshot, err := decryptSnapshot(entryKey, shared)
+				if err != nil {
+					return err
+				}
+
+				var shares []PasswordShare
+				if err := tx.Where("password_id = ?", shared.PasswordID).Find(&shares).Error; err != nil {
+					return err
+				}
+
+				shared.Bound = true
+				if shared, err = m.saveKey(tx, shared, shared.PasswordID, shared.UserID, entryKey, snapshot); err != nil {
+					return err
+				}
+
+				return sealShares(tx, shared, shares, entryKey)
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}).Error
+}
+
+// GetReceived returns the passwords shared with a recipient with their decrypted content, trashed passwords excluded.
+//
+// Parameters:
+// - recipientId: the ID of the recipient.
+// - passwordId: only return the share of this password, all shares when 0.
+//
+// Returns:
+// - []PasswordShare: the shares with their owner and Snapshot, newest first.
+// - error: any key, decryption or retrieval error.
+func (m *ShareModel) GetReceived(recipientId, passwordId uint) ([]PasswordShare, error) {
+	var shares []PasswordShare
+
+	query := m.DB.Preload("Owner", selectUser).
+		Joins("JOIN passwords ON passwords.id = password_shares.password_id AND passwords.deleted_at IS NULL").
+		Where("password_shares.recipient_id = ?", recipientId)
+	if passwordId != 0 {
+		query = query.Where("password_shares.password_id = ?", passwordId)
+	}
+
+	err := query.Order("password_shares.id DESC").Find(&shares).Error
+	if err != nil || len(shares) == 0 {
+		return shares, err
+	}
+
+	userModel := models.UserModel{DB: m.DB}
+
+	_, privateKey, err := userModel.GetKeyPair(recipientId)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range shares {
+		var shared SharedPassword
+		if err := m.DB.Where("password_id = ?", shares[i].PasswordID).First(&shared).Error; err != nil {
+			return nil, err
+		}
+
+		entryKey, err := encryption.Open(privateKey, shares[i].EntryKey, shared.entryKeyAAD(shares[i].ID))
+		if err != nil {
+			return nil, err
+		}
+
+		if shares[i].Snapshot, err = decryptSnapshot(entryKey, shared); err != nil {
+			return nil, err
+		}
+	}
+
+	return shares, nil
+}
+
+// openOwnerKey opens the entry key of a shared password with the private key of its owner.
+func (m *ShareModel) openOwnerKey(tx *gorm.DB, shared SharedPassword) ([]byte, error) {
+	userModel := models.UserModel{DB: tx}
+
+	_, privateKey, err := userModel.GetKeyPair(shared.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return encryption.Open(privateKey, shared.OwnerKey, shared.aad("owner_key"))
+}
+
+// saveKey seals the entry key to the owner and saves it with the encrypted content.
+//
+// The key and the content are bound to the ID of the row, so a new row is inserted before they are encrypted.
+func (m *ShareModel) saveKey(tx *gorm.DB, shared SharedPassword, passwordId, userId uint, entryKey, snapshot []byte) (SharedPassword, error) {
+	shared.PasswordID, shared.UserID = passwordId, userId
+
+	if shared.ID == 0 {
+		shared.Bound = true
+		if err := tx.Omit(clause.Associations).Create(&shared).Error; err != nil {
+			return shared, err
+		}
+	}
+
+	ownerKey, err := sealKey(tx, userId, entryKey, shared.aad("owner_key"))
+	if err != nil {
+		return shared, err
+	}
+
+	cipher, err := encryption.NewCipher(entryKey)
+	if err != nil {
+		return shared, err
+	}
+
+	encrypted, err := cipher.EncryptBytes(snapshot, shared.aad("snapshot"))
+	if err != nil {
+		return shared, err
+	}
+
+	shared.OwnerKey, shared.Snapshot = ownerKey, encrypted
+
+	return shared, tx.Omit(clause.Associations).Save(&shared).Error
+}
+
+// sealShares seals an entry key to the recipients of the shares of a password.
+func sealShares(tx *gorm.DB, shared SharedPassword, shares []PasswordShare, entryKey []byte) error {
+	for _, share := range shares {
+		sealed, err := sealKey(tx, share.RecipientID, entryKey, shared.entryKeyAAD(share.ID))
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Model(&PasswordShare{}).Where("id = ?", share.ID).Update("entry_key", sealed).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// aad returns the additional authenticated data of the owner key or the snapshot of a shared password.
+func (s SharedPassword) aad(column string) string {
+	if s.Bound {
+		return rowAAD("shared_passwords", s.ID)(column)
+	}
+
+	if column == "owner_key" {
+		return "entry_key"
+	}
+
+	return column
+}
+
+// entryKeyAAD returns the additional authenticated data of the entry key sealed to the recipient of a share.
+func (s SharedPassword) entryKeyAAD(shareId uint) string {
+	if s.Bound {
+		return rowAAD("password_shares", shareId)("entry_key")
+	}
+
+	return "entry_key"
+}
+
+// sealKey seals an entry key to the public key of a user.
+func sealKey(tx *gorm.DB, userId uint, entryKey []byte, aad string) (string, error) {
+	userModel := models.UserModel{DB: tx}
+
+	publicKey, _, err := userModel.GetKeyPair(userId)
+	if err != nil {
+		return "", err
+	}
+
+	return encryption.Seal(publicKey, entryKey, aad)
+}
+
+// decryptSnapshot decrypts the shared content of a password with its entry key.
+func decryptSnapshot(entryKey []byte, shared SharedPassword) ([]byte, error) {
+	cipher, err := encryption.NewCipher(entryKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.DecryptBytes(shared.Snapshot, shared.aad("snapshot"))
+}
+
+// selectUser only loads the public profile of a preloaded user.
+func selectUser(db *gorm.DB) *gorm.DB {
+	return db.Select("id", "name", "email")
+}
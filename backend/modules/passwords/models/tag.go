@@ -0,0 +1,144 @@
+package models
+
+import (
+	models2 "backend/modules/tags/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PasswordTag is a row of the password_tags join table between passwords and tags.
+type PasswordTag struct {
+	PasswordID uint `gorm:"primaryKey"`
+	TagID      uint `gorm:"primaryKey"`
+}
+
+// TableName returns the join table created for Password.Tags.
+func (PasswordTag) TableName() string {
+	return "password_tags"
+}
+
+// AttachTag tags passwords of the owner of a tag, passwords that already have it are skipped.
+//
+// Parameters:
+// - tagId: the ID of the tag.
+// - userId: the ID of the user who owns the tag and the passwords.
+// - passwordIds: the IDs of the passwords, those of other users are ignored.
+//
+// Returns:
+// - int64: the number of newly tagged passwords.
+// - error: gorm.ErrRecordNotFound if the tag does not belong to the user, or any update error.
+func (m *PasswordModel) AttachTag(tagId, userId uint, passwordIds []uint) (int64, error) {
+	var affected int64
+
+	err := m.DB.Transaction(func(tx *gorm.DB) error {
+		if err := checkTag(tx, tagId, userId); err != nil {
+			return err
+		}
+
+		var ids []uint
+
+		err := tx.Model(&Password{}).Where("id IN ? AND user_id = ?", passwordIds, userId).Pluck("id", &ids).Error
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+
+		rows := make([]PasswordTag, len(ids))
+		for i, id := range ids {
+			rows[i] = PasswordTag{PasswordID: id, TagID: tagId}
+		}
+
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&rows)
+		if result.Error != nil {
+			return result.Error
+		}
+		affected = result.RowsAffected
+
+		return refreshTags(tx, ids)
+	})
+
+	return affected, err
+}
+
+// DetachTag removes a tag from passwords of its owner.
+//
+// Parameters:
+// - tagId: the ID of the tag.
+// - userId: the ID of the user who owns the tag and the passwords.
+// - passwordIds: the IDs of the passwords.
+//
+// Returns:
+// - int64: the number of untagged passwords.
+// - error: gorm.ErrRecordNotFound if the tag does not belong to the user, or any update error.
+func (m *PasswordModel) DetachTag(tagId, userId uint, passwordIds []uint) (int64, error) {
+	var affected int64
+
+	err := m.DB.Transaction(func(tx *gorm.DB) error {
+		if err := checkTag(tx, tagId, userId); err != nil {
+			return err
+		}
+
+		result := tx.Where("tag_id = ? AND password_id IN ?", tagId, passwordIds).Delete(&PasswordTag{})
+		if result.Error != nil {
+			return result.Error
+		}
+		affected = result.RowsAffected
+
+		return refreshTags(tx, passwordIds)
+	})
+
+	return affected, err
+}
+
+// TaggedWith returns the IDs of the passwords having a tag.
+//
+// tagId: the ID of the tag.
+// Returns the password IDs or an error if the query fails.
+func (m *PasswordModel) TaggedWith(tagId uint) ([]uint, error) {
+	var ids []uint
+
+	err := m.DB.Model(&PasswordTag{}).Where("tag_id = ?", tagId).Pluck("password_id", &ids).Error
+
+	return ids, err
+}
+
+// checkTag makes sure the tag belongs to the user.
+func checkTag(tx *gorm.DB, tagId, userId uint) error {
+	return tx.Select("id").Where("id = ? AND user_id = ?", tagId, userId).First(&models2.Tag{}).Error
+}
+
+// replaceTags replaces the tags of a password, the tags must belong to its owner.
+func replaceTags(tx *gorm.DB, passwordId uint, tagIds []uint) error {
+	if err := tx.Where("password_id = ?", passwordId).Delete(&PasswordTag{}).Error; err != nil {
+		return err
+	}
+
+	if len(tagIds) == 0 {
+		return nil
+	}
+
+	rows := make([]PasswordTag, len(tagIds))
+	for i, tagId := range tagIds {
+		rows[i] = PasswordTag{PasswordID: passwordId, TagID: tagId}
+	}
+
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error
+}
+
+// taggedWithAll returns a subquery selecting the IDs of the passwords having all the given tags.
+func taggedWithAll(tx *gorm.DB, tagIds []uint) *gorm.DB {
+	unique := map[uint]bool{}
+	for _, tagId := range tagIds {
+		unique[tagId] = true
+	}
+
+	return tx.Model(&PasswordTag{}).
+		Select("password_id").
+		Where("tag_id IN ?", tagIds).
+		Group("password_id").
+		Having("COUNT(*) = ?", len(unique))
+}
+
+// orderTags sorts preloaded tags by their name.
+func orderTags(db *gorm.DB) *gorm.DB {
+	return db.Order("name")
+}
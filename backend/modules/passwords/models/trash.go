@@ -0,0 +1,218 @@
+package models
+
+import (
+	models2 "backend/modules/categories/models"
+	"errors"
+	"gorm.io/gorm"
+	"time"
+)
+
+// GetTrashed returns the deleted passwords of a given user, most recently deleted first.
+//
+// Only the metadata is loaded, the secret fields are left empty.
+//
+// Parameters:
+// - userId: the ID of the user.
+//
+// Returns:
+// - []Password: the deleted passwords.
+// - error: any error that occurred during the retrieval process.
+func (m *PasswordModel) GetTrashed(userId uint) ([]Password, error) {
+	var passwords []Password
+
+	err := m.DB.Unscoped().
+		Select("id", "deleted_at", "user_id", "category_id", "type", "name").
+		Where("user_id = ? AND deleted_at IS NOT NULL", userId).
+		Order("deleted_at DESC").
+		Find(&passwords).Error
+
+	return passwords, err
+}
+
+// GetExpired returns the passwords of all users deleted before a given time.
+//
+// before: the deletion time limit.
+// Returns the IDs and owners of the expired passwords or an error if the query fails.
+func (m *PasswordModel) GetExpired(before time.Time) ([]Password, error) {
+	var passwords []Password
+
+	err := m.DB.Unscoped().Select("id", "user_id").Where("deleted_at < ?", before).Find(&passwords).Error
+
+	return passwords, err
+}
+
+// Restore restores a deleted password.
+//
+// A deleted category of the password is restored as well.
+//
+// Parameters:
+// - id: the ID of the password.
+// - userId: the ID of the user who owns the password.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound if the password is not in the trash, or an error if the update fails.
+func (m *PasswordModel) Restore(id, userId uint) error {
+	return m.DB.Transaction(func(tx *gorm.DB) error {
+		var password Password
+
+		err := tx.Unscoped().
+			Select("id", "category_id").
+			Where("id = ? AND user_id = ? AND deleted_at IS NOT NULL", id, userId).
+			First(&password).Error
+		if err != nil {
+			return err
+		}
+
+		if password.CategoryID != nil {
+			categoryModel := models2.CategoryModel{DB: tx}
+
+			category, err := categoryModel.Restore(*password.CategoryID, userId)
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+
+			if err == nil {
+				searchModel := SearchModel{DB: tx}
+				if err := searchModel.UpdateCategory(category.ID, userId, category.Name); err != nil {
+					return err
+				}
+			}
+		}
+
+		err = tx.Unscoped().Model(&Password{}).Where("id = ?", id).UpdateColumn("deleted_at", nil).Error
+		if err != nil {
+			return err
+		}
+
+		passwordModel := PasswordModel{DB: tx}
+
+		return passwordModel.Reindex(id, userId)
+	})
+}
+
+// Purge permanently deletes a password from the trash together with its custom fields, revisions and attachments.
+//
+// Parameters:
+// - id: the ID of the password.
+// - userId: the ID of the user who owns the password.
+//
+// Returns:
+// - []string: the storage keys of the deleted attachment contents.
+// - error: gorm.ErrRecordNotFound if the password is not in the trash, or an error if the deletion fails.
+func (m *PasswordModel) Purge(id, userId uint) ([]string, error) {
+	var keys []string
+
+	err := m.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().
+			Select("id").
+			Where("id = ? AND user_id = ? AND deleted_at IS NOT NULL", id, userId).
+			First(&Password{}).Error
+		if err != nil {
+			return err
+		}
+
+		attachmentModel := AttachmentModel{DB: tx}
+
+		if keys, err = attachmentModel.DeleteAll(id); err != nil {
+			return err
+		}
+
+		return tx.Unscoped().Delete(&Password{}, id).Error
+	})
+
+	return keys, err
+}
+
+// DetachCategory removes a category from the passwords of a user, including deleted passwords and revisions.
+//
+// Parameters:
+// - categoryId: the ID of the category.
+// - userId: the ID of the user who owns the category.
+//
+// Returns an error if the update fails.
+func (m *PasswordModel) DetachCategory(categoryId, userId uint) error {
+	for _, model := range []interface{}{&Password{}, &PasswordRevision{}, &PasswordSearch{}} {
+		err := m.DB.Unscoped().Model(model).
+			Where("category_id = ? AND user_id = ?", categoryId, userId).
+			UpdateColumn("category_id", nil).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DetachActiveCategory removes a category from the passwords of a user that are not in the trash.
+//
+// Deleted passwords and revisions keep the category, so restoring them restores the category as well.
+// DetachCategory clears them once the category is purged.
+//
+// Parameters:
+// - categoryId: the ID of the category.
+// - userId: the ID of the user who owns the category.
+//
+// Returns an error if the update fails.
+func (m *PasswordModel) DetachActiveCategory(categoryId, userId uint) error {
+	err := m.DB.Model(&PasswordSearch{}).
+		Where("category_id = ? AND user_id = ?", categoryId, userId).
+		Where("password_id IN (?)", m.DB.Model(&Password{}).Select("id").Where("user_id = ?", userId)).
+		UpdateColumn("category_id", nil).Error
+	if err != nil {
+		return err
+	}
+
+	return m.DB.Model(&Password{}).
+		Where("category_id = ? AND user_id = ?", categoryId, userId).
+		UpdateColumn("category_id", nil).Error
+}
+
+// MoveCategory moves the passwords of a category to another category of the same user, including deleted passwords.
+//
+// The revisions keep the category they were saved with. The category name of the search index is left to
+// SearchModel.UpdateCategory.
+//
+// Parameters:
+// - categoryId: the ID of the category.
+// - targetId: the ID of the category the passwords are moved to.
+// - userId: the ID of the user who owns both categories.
+//
+// Returns an error if the update fails.
+func (m *PasswordModel) MoveCategory(categoryId, targetId, userId uint) error {
+	for _, model := range []interface{}{&Password{}, &PasswordSearch{}} {
+		err := m.DB.Unscoped().Model(model).
+			Where("category_id = ? AND user_id = ?", categoryId, userId).
+			UpdateColumn("category_id", targetId).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteCategory moves the passwords of a category to the trash.
+//
+// Restoring one of them restores the category as well.
+//
+// Parameters:
+// - categoryId: the ID of the category.
+// - userId: the ID of the user who owns the category.
+//
+// Returns an error if the deletion fails.
+func (m *PasswordModel) DeleteCategory(categoryId, userId uint) error {
+	return m.DB.Transaction(func(tx *gorm.DB) error {
+		var ids []uint
+
+		err := tx.Model(&Password{}).Where("category_id = ? AND user_id = ?", categoryId, userId).Pluck("id", &ids).Error
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+
+		if err := tx.Where("id IN ?", ids).Delete(&Password{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("password_id IN ?", ids).Delete(&PasswordSearch{}).Error
+	})
+}
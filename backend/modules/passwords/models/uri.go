@@ -0,0 +1,165 @@
+package models
+
+import (
+	"backend/services/encryption"
+	"gorm.io/gorm"
+	"time"
+)
+
+const (
+	MatchDomain     = "domain"
+	MatchHost       = "host"
+	MatchStartsWith = "starts_with"
+	MatchRegex      = "regex"
+	MatchNever      = "never"
+)
+
+// MatchTypes lists the supported URI match strategies.
+var MatchTypes = []string{MatchDomain, MatchHost, MatchStartsWith, MatchRegex, MatchNever}
+
+// PasswordURI is a website or application address of a password used for autofill.
+//
+// The URI is encrypted with the key of the owner like the custom field values, Match tells
+// how it is compared with the address of a page.
+type PasswordURI struct {
+	gorm.Model
+	PasswordID uint   `gorm:"not null;index" json:"-"`
+	Position   int    `gorm:"not null" json:"position"`
+	URI        string `gorm:"not null" json:"uri"`
+	Match      string `gorm:"not null;default:domain" json:"match"`
+}
+
+// GetWithURIs returns the server-encrypted logins of a given user that have URIs, for autofill lookups.
+//
+// Only the metadata, the login and the URIs are loaded and decrypted.
+//
+// Parameters:
+// - userId: the ID of the user.
+//
+// Returns:
+// - []Password: the logins with their decrypted login and URIs.
+// - error: any error that occurred during the retrieval or decryption process.
+func (m *PasswordModel) GetWithURIs(userId uint) ([]Password, error) {
+	cipher, err := m.getCipher(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	var passwords []Password
+
+	err = m.DB.Select("id", "user_id", "category_id", "type", "name", "login", "encryption", "last_used_at").
+		Where("user_id = ? AND type = ? AND encryption = ?", userId, TypeLogin, EncryptionServer).
+		Where("EXISTS (SELECT 1 FROM password_uris WHERE password_uris.password_id = passwords.id AND password_uris.deleted_at IS NULL)").
+		Preload("URIs", orderURIs).
+		Find(&passwords).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range passwords {
+		aad := rowAAD("passwords", passwords[i].ID)
+
+		if encryption.IsEncrypted(passwords[i].Login) {
+			if passwords[i].Login, err = cipher.Decrypt(passwords[i].Login, aad("login")); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := decryptURIs(passwords[i].URIs, cipher, aad("uris")); err != nil {
+			return nil, err
+		}
+	}
+
+	return passwords, nil
+}
+
+// MarkUsed records that a password was used, for example to autofill a form.
+//
+// The UpdatedAt timestamp is left untouched, so using a password does not count as a change.
+//
+// Parameters:
+// - id: the ID of the password.
+// - userId: the ID of the user who owns the password.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound if the password does not exist, or any update error.
+func (m *PasswordModel) MarkUsed(id, userId uint) error {
+	result := m.DB.Model(&Password{}).
+		Where("id = ? AND user_id = ?", id, userId).
+		UpdateColumn("last_used_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// encryptURIs returns a copy of the URIs with encrypted values.
+//
+// A copy is returned so the plaintext URIs of the caller stay untouched.
+func encryptURIs(uris []PasswordURI, cipher encryption.Cipher, aad string) ([]PasswordURI, error) {
+	encrypted := make([]PasswordURI, len(uris))
+
+	for i, uri := range uris {
+		value, err := cipher.Encrypt(uri.URI, aad)
+		if err != nil {
+			return nil, err
+		}
+
+		uri.URI = value
+		encrypted[i] = uri
+	}
+
+	return encrypted, nil
+}
+
+// decryptURIs decrypts the URIs in place.
+func decryptURIs(uris []PasswordURI, cipher encryption.Cipher, aad string) error {
+	for i := range uris {
+		if !encryption.IsEncrypted(uris[i].URI) {
+			continue
+		}
+
+		value, err := cipher.Decrypt(uris[i].URI, aad)
+		if err != nil {
+			return err
+		}
+
+		uris[i].URI = value
+	}
+
+	return nil
+}
+
+// replaceURIs deletes the URIs of a password and stores the given ones in their place.
+func replaceURIs(tx *gorm.DB, passwordId uint, uris []PasswordURI) error {
+	err := tx.Unscoped().Where("password_id = ?", passwordId).Delete(&PasswordURI{}).Error
+	if err != nil {
+		return err
+	}
+
+	if len(uris) == 0 {
+		return nil
+	}
+
+	saved := make([]PasswordURI, len(uris))
+	for i, uri := range uris {
+		saved[i] = PasswordURI{
+			PasswordID: passwordId,
+			Position:   uri.Position,
+			URI:        uri.URI,
+			Match:      uri.Match,
+		}
+	}
+
+	return tx.Create(&saved).Error
+}
+
+// orderURIs sorts preloaded URIs by their position.
+func orderURIs(db *gorm.DB) *gorm.DB {
+	return db.Order("position")
+}
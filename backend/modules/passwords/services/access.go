@@ -0,0 +1,310 @@
+package services
+
+import (
+	services4 "backend/modules/audit/services"
+	services3 "backend/modules/organizations/services"
+	"backend/modules/passwords/models"
+	"backend/modules/passwords/services/totp"
+	services2 "backend/modules/tags/services"
+	"encoding/json"
+	"errors"
+	"slices"
+	"time"
+)
+
+// Secrets that can be copied with CopySecret.
+const (
+	SecretPassword       = "password"
+	SecretTotp           = "totp"
+	SecretCardNumber     = "card_number"
+	SecretCardCVV        = "card_cvv"
+	SecretPassportNumber = "passport_number"
+	SecretLicenseNumber  = "license_number"
+	SecretNationalID     = "national_id"
+	SecretWifiKey        = "wifi_key"
+	SecretNote           = "note"
+	// SecretField is the value of the custom field with a given label.
+	SecretField = "field"
+)
+
+var ErrSecretNotSet = errors.New("password has no such secret")
+
+type AccessLogEntry struct {
+	ID     uint   `json:"id"`
+	Email  string `json:"email"`
+	Name   string `json:"name"`
+	Action string `json:"action"`
+	// Copied secret, the revealed revision as revision:{id} or the downloaded attachment as attachment:{id},
+	// empty for reveals of the entry.
+	Field     string    `json:"field"`
+	TokenID   *uint     `json:"token_id"`
+	IP        string    `json:"ip"`
+	UserAgent string    `json:"user_agent"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type AccessLogFilter struct {
+	Since *time.Time
+	Until *time.Time
+}
+
+// getAccessLogModel returns an AccessLogModel.
+func (s *PasswordService) getAccessLogModel() models.AccessLogModel {
+	return models.AccessLogModel{DB: s.DB}
+}
+
+// RevealPassword returns a password with its secrets and records the access in its access log.
+//
+// The owner, users it is shared with and members of its collections can reveal it, unless their access is
+// limited to viewing it without its secrets. The category and tags of the owner are hidden from the others.
+// Users it is only shared with read the content sealed for them, see revealContent.
+//
+// Parameters:
+// - id: the ID of the password.
+// - userId: the ID of the user.
+// - client: the token, IP address and user agent of the request, for the access log.
+//
+// Returns:
+// - Password: the password with its secrets.
+// - error: gorm.ErrRecordNotFound, services3.ErrAccessDenied or any decryption or logging error.
+func (s *PasswordService) RevealPassword(id, userId uint, client services4.Client) (Password, error) {
+	authorizationService := services3.AuthorizationService{DB: s.DB}
+
+	access, err := authorizationService.Authorize(userId, id, services3.AccessReveal)
+	if err != nil {
+		return Password{}, err
+	}
+
+	password, err := s.revealContent(id, userId, access)
+	if err != nil {
+		return Password{}, err
+	}
+
+	if err := s.recordAccess(id, userId, models.AccessReveal, "", client); err != nil {
+		return Password{}, err
+	}
+
+	return toOrganizationPassword(password, access.Manage), nil
+}
+
+// OpenPassword returns a password with its secrets on behalf of a user the caller already authorized,
+// e.g. an emergency contact, and records the access in its access log.
+//
+// Parameters:
+// - id: the ID of the password.
+// - ownerId: the ID of the owner.
+// - userId: the ID of the user reading the password.
+// - client: the token, IP address and user agent of the request, for the access log.
+//
+// Returns:
+// - Password: the password with its secrets.
+// - error: gorm.ErrRecordNotFound if the password does not belong to the owner, or any decryption or logging error.
+func (s *PasswordService) OpenPassword(id, ownerId, userId uint, client services4.Client) (Password, error) {
+	password, err := s.getPassword(id, ownerId)
+	if err != nil {
+		return Password{}, err
+	}
+
+	if err := s.recordAccess(id, userId, models.AccessReveal, "", client); err != nil {
+		return Password{}, err
+	}
+
+	return password, nil
+}
+
+// CopySecret returns a single secret of a password and records the access in its access log.
+//
+// The current one-time code is returned for SecretTotp. Access is checked as in RevealPassword.
+//
+// Parameters:
+// - id: the ID of the password.
+// - userId: the ID of the user.
+// - secret: one of the Secret constants.
+// - label: the label of the custom field for SecretField.
+// - client: the token, IP address and user agent of the request, for the access log.
+//
+// Returns:
+// - string: the secret.
+// - error: gorm.ErrRecordNotFound, services3.ErrAccessDenied, ErrSecretNotSet, ErrClientEncrypted for one-time codes
+// of client-encrypted passwords, or any decryption or logging error.
+func (s *PasswordService) CopySecret(id, userId uint, secret, label string, client services4.Client) (string, error) {
+	authorizationService := services3.AuthorizationService{DB: s.DB}
+
+	access, err := authorizationService.Authorize(userId, id, services3.AccessReveal)
+	if err != nil {
+		return "", err
+	}
+
+	password, err := s.revealContent(id, userId, access)
+	if err != nil {
+		return "", err
+	}
+
+	value, err := secretValue(password, secret, label)
+	if err != nil {
+		return "", err
+	}
+
+	field := secret
+	if secret == SecretField {
+		field = secret + ":" + label
+	}
+
+	if err := s.recordAccess(id, userId, models.AccessCopy, field, client); err != nil {
+		return "", err
+	}
+
+	return value, nil
+}
+
+// GetAccessLog returns who revealed or copied the secrets of a password, for its owner.
+//
+// Parameters:
+// - id: the ID of the password.
+// - userId: the ID of the owner.
+// - filter: the optional time range.
+//
+// Returns:
+// - []AccessLogEntry: the accesses, newest first.
+// - error: gorm.ErrRecordNotFound if the password does not belong to the user, or any retrieval error.
+func (s *PasswordService) GetAccessLog(id, userId uint, filter AccessLogFilter) ([]AccessLogEntry, error) {
+	if err := s.checkPassword(id, userId); err != nil {
+		return nil, err
+	}
+
+	accessLogModel := s.getAccessLogModel()
+
+	entries, err := accessLogModel.GetAll(id, models.AccessLogFilter{Since: filter.Since, Until: filter.Until})
+
+	entriesList := []AccessLogEntry{}
+
+	for _, entry := range entries {
+		entriesList = append(entriesList, AccessLogEntry{
+			ID:        entry.ID,
+			Email:     entry.User.Email,
+			Name:      entry.User.Name,
+			Action:    entry.Action,
+			Field:     entry.Field,
+			TokenID:   entry.TokenID,
+			IP:        entry.IP,
+			UserAgent: entry.UserAgent,
+			CreatedAt: entry.CreatedAt,
+		})
+	}
+
+	return entriesList, err
+}
+
+// recordAccess adds an entry to the access log of a password.
+func (s *PasswordService) recordAccess(id, userId uint, action, field string, client services4.Client) error {
+	accessLogModel := s.getAccessLogModel()
+
+	return accessLogModel.Create(models.AccessLog{
+		PasswordID: id,
+		UserID:     userId,
+		TokenID:    client.TokenID,
+		Action:     action,
+		Field:      field,
+		IP:         client.IP,
+		UserAgent:  client.UserAgent,
+	})
+}
+
+// secretValue picks a secret of a password.
+func secretValue(password Password, secret, label string) (string, error) {
+	value := ""
+
+	switch secret {
+	case SecretPassword:
+		value = password.Password
+	case SecretTotp:
+		if password.Totp == "" {
+			return "", ErrSecretNotSet
+		}
+
+		if password.Encryption == models.EncryptionClient {
+			return "", ErrClientEncrypted
+		}
+
+		key, err := totp.Parse(password.Totp)
+		if err != nil {
+			return "", err
+		}
+
+		value = key.Generate(time.Now()).Code
+	case SecretCardNumber:
+		if password.Card != nil {
+			value = password.Card.Number
+		}
+	case SecretCardCVV:
+		if password.Card != nil {
+			value = password.Card.CVV
+		}
+	case SecretPassportNumber:
+		if password.Identity != nil {
+			value = password.Identity.PassportNumber
+		}
+	case SecretLicenseNumber:
+		if password.Identity != nil {
+			value = password.Identity.LicenseNumber
+		}
+	case SecretNationalID:
+		if password.Identity != nil {
+			value = password.Identity.NationalID
+		}
+	case SecretWifiKey:
+		if password.Wifi != nil {
+			value = password.Wifi.Key
+		}
+	case SecretNote:
+		if password.Note != nil {
+			value = password.Note.Text
+		}
+	case SecretField:
+		for _, field := range password.Fields {
+			if field.Label == label {
+				value = field.Value
+				break
+			}
+		}
+	}
+
+	if value == "" {
+		return "", ErrSecretNotSet
+	}
+
+	return value, nil
+}
+
+// revealContent returns a password with its secrets for a user who may reveal it.
+//
+// Users it is only shared with read the snapshot sealed to their own key, the owner and members of its collections
+// read the entry of the owner, collections have no sealed copy.
+func (s *PasswordService) revealContent(id, userId uint, access services3.Access) (Password, error) {
+	if access.OwnerID == userId || slices.Contains(access.Sources, services3.SourceOrganization) {
+		return s.getPassword(id, access.OwnerID)
+	}
+
+	share, err := s.getReceivedShare(id, userId)
+	if err != nil {
+		return Password{}, err
+	}
+
+	var content snapshot
+	if err := json.Unmarshal(share.Snapshot, &content); err != nil {
+		return Password{}, err
+	}
+
+	return Password{
+		ID:         share.PasswordID,
+		Item:       content.Item,
+		Name:       content.Name,
+		Login:      content.Login,
+		Password:   content.Password,
+		Fields:     content.Fields,
+		URIs:       content.URIs,
+		Tags:       []services2.Tag{},
+		Totp:       content.Totp,
+		Encryption: models.EncryptionServer,
+	}, nil
+}
@@ -0,0 +1,282 @@
+package services
+
+import (
+	services4 "backend/modules/audit/services"
+	"backend/modules/passwords/models"
+	"backend/services/config"
+	"backend/services/encryption"
+	"backend/services/storage"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"strconv"
+	"time"
+)
+
+const megabyte = 1024 * 1024
+
+var (
+	ErrAttachmentTooLarge = errors.New("attachment is too large")
+	ErrQuotaExceeded      = models.ErrQuotaExceeded
+	ErrIncompleteUpload   = errors.New("upload ended before the announced size")
+)
+
+type Attachment struct {
+	ID          uint      `json:"id"`
+	CreatedAt   time.Time `json:"created_at"`
+	Name        string    `json:"name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+}
+
+type AttachmentUpload struct {
+	Name        string
+	ContentType string
+	Size        int64
+	Content     io.Reader
+}
+
+// getAttachmentModel returns an AttachmentModel.
+//
+// No parameters.
+// Returns a models.AttachmentModel.
+func (s *PasswordService) getAttachmentModel() models.AttachmentModel {
+	return models.AttachmentModel{DB: s.DB}
+}
+
+// GetAttachments returns the attachments of a password.
+//
+// Parameters:
+// - passwordId: the ID of the password.
+// - userId: the ID of the user.
+//
+// Returns:
+// - []Attachment: the attachments.
+// - error: gorm.ErrRecordNotFound if the password does not belong to the user.
+func (s *PasswordService) GetAttachments(passwordId, userId uint) ([]Attachment, error) {
+	if err := s.checkPassword(passwordId, userId); err != nil {
+		return nil, err
+	}
+
+	attachmentModel := s.getAttachmentModel()
+
+	attachments, err := attachmentModel.GetAll(passwordId, userId)
+
+	attachmentsList := []Attachment{}
+
+	for _, attachment := range attachments {
+		attachmentsList = append(attachmentsList, toAttachment(attachment))
+	}
+
+	return attachmentsList, err
+}
+
+// UploadAttachment encrypts a file with a new key and streams it to the blob storage.
+//
+// ATTACHMENT_MAX_SIZE_MB limits the size of a file (25 by default) and ATTACHMENT_QUOTA_MB
+// the total size of the files of a user (500 by default), 0 disables a limit.
+//
+// Parameters:
+// - ctx: the context of the request, the upload is cancelled with it.
+// - passwordId: the ID of the password.
+// - userId: the ID of the user.
+// - upload: the file, Content must provide exactly Size bytes.
+//
+// Returns:
+// - Attachment: the stored attachment.
+// - error: ErrAttachmentTooLarge, ErrQuotaExceeded, ErrIncompleteUpload, gorm.ErrRecordNotFound or any storage error.
+func (s *PasswordService) UploadAttachment(ctx context.Context, passwordId, userId uint, upload AttachmentUpload) (Attachment, error) {
+	maxSize := int64(config.GetInt("ATTACHMENT_MAX_SIZE_MB", 25)) * megabyte
+	if maxSize > 0 && upload.Size > maxSize {
+		return Attachment{}, ErrAttachmentTooLarge
+	}
+
+	if err := s.checkPassword(passwordId, userId); err != nil {
+		return Attachment{}, err
+	}
+
+	if upload.ContentType == "" {
+		upload.ContentType = "application/octet-stream"
+	}
+
+	attachmentModel := s.getAttachmentModel()
+
+	attachment, fileCipher, err := attachmentModel.Reserve(models.Attachment{
+		PasswordID:  passwordId,
+		UserID:      userId,
+		Name:        upload.Name,
+		ContentType: upload.ContentType,
+		Size:        upload.Size,
+	}, int64(config.GetInt("ATTACHMENT_QUOTA_MB", 500))*megabyte)
+	if err != nil {
+		return Attachment{}, err
+	}
+
+	content := &countingReader{reader: io.LimitReader(upload.Content, upload.Size)}
+
+	err = s.storeAttachment(ctx, attachment.StorageKey, fileCipher, content, upload.Size)
+	if content.count < upload.Size && (err == nil || content.err != nil) {
+		err = ErrIncompleteUpload
+	}
+
+	if err != nil {
+		s.removeAttachments(attachment.StorageKey)
+		if _, deleteErr := attachmentModel.Delete(attachment.ID, passwordId, userId); deleteErr != nil {
+			log.Println("failed to delete attachment:", deleteErr)
+		}
+
+		return Attachment{}, err
+	}
+
+	if err := attachmentModel.MarkReady(attachment.ID); err != nil {
+		return Attachment{}, err
+	}
+
+	return toAttachment(attachment), nil
+}
+
+// OpenAttachment opens the decrypted content of an attachment without recording it in the access log.
+//
+// Exports use it, they are recorded as a whole in the audit trail. Downloads go through DownloadAttachment.
+// The caller closes the returned reader. A read error means the stored content was tampered with
+// or truncated, and the data already read must be discarded.
+//
+// Parameters:
+// - ctx: the context of the request.
+// - id: the ID of the attachment.
+// - passwordId: the ID of the password.
+// - userId: the ID of the user.
+//
+// Returns:
+// - Attachment: the attachment.
+// - io.ReadCloser: the decrypted content.
+// - error: gorm.ErrRecordNotFound if the attachment does not belong to the user, or any storage error.
+func (s *PasswordService) OpenAttachment(ctx context.Context, id, passwordId, userId uint) (Attachment, io.ReadCloser, error) {
+	attachmentModel := s.getAttachmentModel()
+
+	attachment, fileCipher, err := attachmentModel.Get(id, passwordId, userId)
+	if err != nil {
+		return Attachment{}, nil, err
+	}
+
+	blob, err := storage.Get().Get(ctx, attachment.StorageKey)
+	if err != nil {
+		return Attachment{}, nil, err
+	}
+
+	content, err := fileCipher.DecryptStream(blob)
+	if err != nil {
+		blob.Close()
+		return Attachment{}, nil, err
+	}
+
+	return toAttachment(attachment), readCloser{Reader: content, Closer: blob}, nil
+}
+
+// DownloadAttachment opens the decrypted content of an attachment and records the download in the access log
+// of its password as a reveal of attachment:{id}.
+//
+// Parameters:
+// - ctx: the context of the request.
+// - id: the ID of the attachment.
+// - passwordId: the ID of the password.
+// - userId: the ID of the user.
+// - client: the token, IP address and user agent of the request, for the access log.
+//
+// Returns:
+// - Attachment: the attachment.
+// - io.ReadCloser: the decrypted content, see OpenAttachment.
+// - error: gorm.ErrRecordNotFound if the attachment does not belong to the user, or any storage or logging error.
+func (s *PasswordService) DownloadAttachment(ctx context.Context, id, passwordId, userId uint, client services4.Client) (Attachment, io.ReadCloser, error) {
+	attachment, content, err := s.OpenAttachment(ctx, id, passwordId, userId)
+	if err != nil {
+		return Attachment{}, nil, err
+	}
+
+	field := "attachment:" + strconv.FormatUint(uint64(id), 10)
+	if err := s.recordAccess(passwordId, userId, models.AccessReveal, field, client); err != nil {
+		content.Close()
+		return Attachment{}, nil, err
+	}
+
+	return attachment, content, nil
+}
+
+// DeleteAttachment deletes an attachment and its content.
+//
+// Parameters:
+// - id: the ID of the attachment.
+// - passwordId: the ID of the password.
+// - userId: the ID of the user.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound if the attachment does not belong to the user, or any deletion error.
+func (s *PasswordService) DeleteAttachment(id, passwordId, userId uint) error {
+	attachmentModel := s.getAttachmentModel()
+
+	key, err := attachmentModel.Delete(id, passwordId, userId)
+	if err != nil {
+		return err
+	}
+
+	s.removeAttachments(key)
+
+	return nil
+}
+
+// storeAttachment encrypts the content and writes it to the blob storage.
+func (s *PasswordService) storeAttachment(ctx context.Context, key string, fileCipher encryption.Cipher, content io.Reader, size int64) error {
+	encrypted, err := fileCipher.EncryptStream(content)
+	if err != nil {
+		return err
+	}
+
+	return storage.Get().Put(ctx, key, encrypted, encryption.EncryptedStreamSize(size))
+}
+
+// removeAttachments deletes contents from the blob storage.
+//
+// The rows are already gone at this point, so failures are only logged.
+func (s *PasswordService) removeAttachments(keys ...string) {
+	for _, key := range keys {
+		if err := storage.Get().Delete(context.Background(), key); err != nil {
+			log.Printf("failed to delete attachment content %s: %v", key, err)
+		}
+	}
+}
+
+// toAttachment converts an attachment model to its response representation.
+func toAttachment(attachment models.Attachment) Attachment {
+	return Attachment{
+		ID:          attachment.ID,
+		CreatedAt:   attachment.CreatedAt,
+		Name:        attachment.Name,
+		ContentType: attachment.ContentType,
+		Size:        attachment.Size,
+	}
+}
+
+// countingReader counts the bytes read from a reader and keeps its last error.
+type countingReader struct {
+	reader io.Reader
+	count  int64
+	err    error
+}
+
+// Read implements io.Reader.
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.count += int64(n)
+	if err != nil {
+		r.err = err
+	}
+
+	return n, err
+}
+
+// readCloser combines a reader with the closer of the underlying stream.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
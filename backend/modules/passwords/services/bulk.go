@@ -0,0 +1,188 @@
+package services
+
+import (
+	"gorm.io/gorm"
+)
+
+// BulkResult is the outcome of a bulk operation on one password.
+type BulkResult struct {
+	ID uint
+	// Error is nil when the operation succeeded.
+	Error error
+}
+
+// MovePasswords moves several passwords of a given user to a category.
+//
+// Parameters:
+// - ids: the IDs of the passwords.
+// - userId: the ID of the user.
+// - categoryId: the ID of the category, nil to remove the category.
+//
+// Returns:
+// - []BulkResult: the outcome for each password.
+// - error: ErrCategoryNotFound, or an error if the transaction fails.
+func (s *PasswordService) MovePasswords(ids []uint, userId uint, categoryId *uint) ([]BulkResult, error) {
+	if err := s.checkCategory(userId, categoryId); err != nil {
+		return nil, err
+	}
+
+	return s.bulk(ids, func(service PasswordService, id uint) error {
+		passwordModel := service.getModel()
+
+		return passwordModel.Move(id, userId, categoryId)
+	})
+}
+
+// DeletePasswords moves several passwords of a given user to the trash.
+//
+// Parameters:
+// - ids: the IDs of the passwords.
+// - userId: the ID of the user.
+//
+// Returns:
+// - []BulkResult: the outcome for each password.
+// - error: an error if the transaction fails.
+func (s *PasswordService) DeletePasswords(ids []uint, userId uint) ([]BulkResult, error) {
+	return s.bulk(ids, func(service PasswordService, id uint) error {
+		return service.DeletePassword(id, userId)
+	})
+}
+
+// RestorePasswords restores several passwords from the trash of a given user.
+//
+// Parameters:
+// - ids: the IDs of the passwords.
+// - userId: the ID of the user.
+//
+// Returns:
+// - []BulkResult: the outcome for each password.
+// - error: an error if the transaction fails.
+func (s *PasswordService) RestorePasswords(ids []uint, userId uint) ([]BulkResult, error) {
+	return s.bulk(ids, func(service PasswordService, id uint) error {
+		return service.RestorePassword(id, userId)
+	})
+}
+
+// TagPasswords adds tags to several passwords of a given user.
+//
+// Parameters:
+// - ids: the IDs of the passwords.
+// - userId: the ID of the user.
+// - tagIds: the IDs of the tags, passwords that already have a tag keep it.
+//
+// Returns:
+// - []BulkResult: the outcome for each password.
+// - error: ErrTagNotFound, or an error if the transaction fails.
+func (s *PasswordService) TagPasswords(ids []uint, userId uint, tagIds []uint) ([]BulkResult, error) {
+	if err := s.checkTags(userId, tagIds); err != nil {
+		return nil, err
+	}
+
+	return s.bulk(ids, func(service PasswordService, id uint) error {
+		if err := service.checkPassword(id, userId); err != nil {
+			return err
+		}
+
+		passwordModel := service.getModel()
+
+		for _, tagId := range tagIds {
+			if _, err := passwordModel.AttachTag(tagId, userId, []uint{id}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// UntagPasswords removes tags from several passwords of a given user.
+//
+// Parameters:
+// - ids: the IDs of the passwords.
+// - userId: the ID of the user.
+// - tagIds: the IDs of the tags.
+//
+// Returns:
+// - []BulkResult: the outcome for each password.
+// - error: ErrTagNotFound, or an error if the transaction fails.
+func (s *PasswordService) UntagPasswords(ids []uint, userId uint, tagIds []uint) ([]BulkResult, error) {
+	if err := s.checkTags(userId, tagIds); err != nil {
+		return nil, err
+	}
+
+	return s.bulk(ids, func(service PasswordService, id uint) error {
+		if err := service.checkPassword(id, userId); err != nil {
+			return err
+		}
+
+		passwordModel := service.getModel()
+
+		for _, tagId := range tagIds {
+			if _, err := passwordModel.DetachTag(tagId, userId, []uint{id}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// SharePasswords shares several passwords of a given user with another user, see SharePassword.
+//
+// Parameters:
+// - ids: the IDs of the passwords.
+// - userId: the ID of the owner.
+// - email: the email of the recipient.
+// - permission: models.PermissionView, models.PermissionEdit or models.PermissionViewWithoutReveal.
+//
+// Returns:
+// - []BulkResult: the outcome for each password.
+// - error: an error if the transaction fails.
+func (s *PasswordService) SharePasswords(ids []uint, userId uint, email, permission string) ([]BulkResult, error) {
+	return s.bulk(ids, func(service PasswordService, id uint) error {
+		_, err := service.SharePassword(id, userId, email, permission)
+
+		return err
+	})
+}
+
+// bulk runs an operation on several passwords in a single transaction.
+//
+// Each password gets its own savepoint: a failure only rolls back the changes made to that password and is
+// reported in its result, the other passwords are still committed. Duplicate IDs are handled once.
+//
+// Parameters:
+// - ids: the IDs of the passwords.
+// - operation: the operation, called with a service bound to the savepoint.
+//
+// Returns:
+// - []BulkResult: the outcome for each password, in the order of the IDs.
+// - error: an error if the transaction cannot be started or committed.
+func (s *PasswordService) bulk(ids []uint, operation func(service PasswordService, id uint) error) ([]BulkResult, error) {
+	var results []BulkResult
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		results = []BulkResult{}
+		seen := map[uint]bool{}
+
+		for _, id := range ids {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+
+			err := tx.Transaction(func(item *gorm.DB) error {
+				return operation(PasswordService{DB: item, Cipher: s.Cipher}, id)
+			})
+
+			results = append(results, BulkResult{ID: id, Error: err})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
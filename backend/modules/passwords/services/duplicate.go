@@ -0,0 +1,75 @@
+package services
+
+import (
+	"backend/modules/passwords/models"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// DuplicateKey returns a key identifying the content of a password, used to detect duplicates on import.
+//
+// Two entries have the same key when they have the same type, name (ignoring case), login, password
+// and type-specific data. Custom fields, URIs and tags are not compared.
+//
+// Parameters:
+// - data: the values of the password.
+//
+// Returns:
+// - string: the key.
+// - error: an error if the item data cannot be encoded.
+func DuplicateKey(data PasswordData) (string, error) {
+	itemData, err := encodeItem(data.Item)
+	if err != nil {
+		return "", err
+	}
+
+	itemType := data.Type
+	if itemType == "" {
+		itemType = models.TypeLogin
+	}
+
+	return duplicateKey(itemType, data.Name, data.Login, data.Password, itemData), nil
+}
+
+// GetDuplicateKeys returns the duplicate keys of the server-encrypted passwords of a given user.
+//
+// Parameters:
+// - userId: the ID of the user.
+//
+// Returns:
+// - map[string]bool: the keys, see DuplicateKey.
+// - error: any error that occurred during the retrieval or decryption process.
+func (s *PasswordService) GetDuplicateKeys(userId uint) (map[string]bool, error) {
+	passwordModel := s.getModel()
+
+	passwords, err := passwordModel.GetAll(userId, models.PasswordFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	keys := map[string]bool{}
+
+	for _, password := range passwords {
+		if password.Encryption == models.EncryptionClient {
+			continue
+		}
+
+		keys[duplicateKey(password.Type, password.Name, password.Login, password.Password, password.Data)] = true
+	}
+
+	return keys, nil
+}
+
+// duplicateKey hashes the compared values of a password.
+func duplicateKey(itemType, name, login, password, data string) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{
+		itemType,
+		strings.ToLower(strings.TrimSpace(name)),
+		login,
+		password,
+		data,
+	}, "\x00")))
+
+	return hex.EncodeToString(sum[:])
+}
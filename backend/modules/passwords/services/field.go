@@ -0,0 +1,97 @@
+package services
+
+import (
+	"backend/modules/passwords/models"
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"slices"
+	"time"
+)
+
+const MaxFields = 50
+
+var ErrInvalidField = errors.New("invalid custom field")
+
+type Field struct {
+	Label string `json:"label"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// validateFields checks the values of custom fields against their types.
+//
+// Values of client-encrypted entries are ciphertext, so only the types and labels are checked then.
+func validateFields(fields []Field, clientEncrypted bool) error {
+	if len(fields) > MaxFields {
+		return fmt.Errorf("%w: at most %d fields are allowed", ErrInvalidField, MaxFields)
+	}
+
+	for i, field := range fields {
+		if field.Label == "" {
+			return fmt.Errorf("%w: field %d has no label", ErrInvalidField, i)
+		}
+
+		if !slices.Contains(models.FieldTypes, field.Type) {
+			return fmt.Errorf("%w: unknown type %q of field %q", ErrInvalidField, field.Type, field.Label)
+		}
+
+		if clientEncrypted || field.Value == "" {
+			continue
+		}
+
+		if err := validateValue(field.Type, field.Value); err != nil {
+			return fmt.Errorf("%w: field %q: %s", ErrInvalidField, field.Label, err)
+		}
+	}
+
+	return nil
+}
+
+// validateValue checks a single plaintext value of a typed field.
+func validateValue(fieldType, value string) error {
+	switch fieldType {
+	case models.FieldURL:
+		parsed, err := url.Parse(value)
+		if err != nil || parsed.Scheme == "" || (parsed.Host == "" && parsed.Opaque == "") {
+			return errors.New("expected an absolute URL")
+		}
+	case models.FieldEmail:
+		if _, err := mail.ParseAddress(value); err != nil {
+			return errors.New("expected an email address")
+		}
+	case models.FieldDate:
+		if _, err := time.Parse(time.DateOnly, value); err != nil {
+			return errors.New("expected a date in the YYYY-MM-DD format")
+		}
+	case models.FieldBoolean:
+		if value != "true" && value != "false" {
+			return errors.New("expected true or false")
+		}
+	}
+
+	return nil
+}
+
+// toFieldModels converts custom fields to models, the position is the index in the list.
+func toFieldModels(fields []Field) []models.PasswordField {
+	fieldModels := make([]models.PasswordField, len(fields))
+
+	for i, field := range fields {
+		fieldModels[i] = models.PasswordField{Position: i, Label: field.Label, Type: field.Type, Value: field.Value}
+	}
+
+	return fieldModels
+}
+
+// toFields converts custom field models to their response representation.
+func toFields(fieldModels []models.PasswordField) []Field {
+	fields := make([]Field, len(fieldModels))
+
+	for i, field := range fieldModels {
+		fields[i] = Field{Label: field.Label, Type: field.Type, Value: field.Value}
+	}
+
+	return fields
+}
@@ -0,0 +1,310 @@
+package services
+
+import (
+	"backend/modules/passwords/models"
+	"backend/modules/passwords/services/generator"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultLength    = 20
+	defaultWords     = 5
+	defaultSeparator = "-"
+)
+
+var (
+	ErrInvalidPolicy = generator.ErrInvalidPolicy
+	ErrPresetExists  = errors.New("generator preset with this name already exists")
+)
+
+type GeneratorService struct {
+	DB *gorm.DB
+}
+
+type GeneratorOptions struct {
+	Type             string `json:"type" example:"password"`
+	Length           int    `json:"length"`
+	Lowercase        bool   `json:"lowercase"`
+	Uppercase        bool   `json:"uppercase"`
+	Digits           bool   `json:"digits"`
+	Symbols          bool   `json:"symbols"`
+	ExcludeAmbiguous bool   `json:"exclude_ambiguous"`
+	MinLowercase     int    `json:"min_lowercase"`
+	MinUppercase     int    `json:"min_uppercase"`
+	MinDigits        int    `json:"min_digits"`
+	MinSymbols       int    `json:"min_symbols"`
+	Words            int    `json:"words"`
+	Separator        string `json:"separator"`
+	Capitalize       bool   `json:"capitalize"`
+	IncludeNumber    bool   `json:"include_number"`
+}
+
+type GeneratorPreset struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+	GeneratorOptions
+}
+
+type GeneratedPassword struct {
+	Password string  `json:"password"`
+	Entropy  float64 `json:"entropy"`
+}
+
+// getModel returns a GeneratorPresetModel.
+//
+// No parameters.
+// Returns a models.GeneratorPresetModel.
+func (s *GeneratorService) getModel() models.GeneratorPresetModel {
+	return models.GeneratorPresetModel{DB: s.DB}
+}
+
+// Generate generates a password or passphrase.
+//
+// Parameters:
+// - userId: the ID of the user.
+// - presetId: optional preset of the user, its options replace the given options.
+// - options: the generator options.
+//
+// Returns:
+// - GeneratedPassword: the generated value and its entropy in bits.
+// - error: ErrInvalidPolicy, gorm.ErrRecordNotFound for a foreign preset, or a random source error.
+func (s *GeneratorService) Generate(userId uint, presetId *uint, options GeneratorOptions) (GeneratedPassword, error) {
+	if presetId != nil {
+		presetModel := s.getModel()
+
+		preset, err := presetModel.Get(*presetId, userId)
+		if err != nil {
+			return GeneratedPassword{}, err
+		}
+
+		options = toGeneratorPreset(preset).GeneratorOptions
+	}
+
+	return generate(options)
+}
+
+// GetPresets returns the generator presets of a given user.
+//
+// userId: the ID of the user.
+// Returns the presets and an error.
+func (s *GeneratorService) GetPresets(userId uint) ([]GeneratorPreset, error) {
+	presetModel := s.getModel()
+
+	presets, err := presetModel.GetAll(userId)
+
+	presetsList := []GeneratorPreset{}
+
+	for _, preset := range presets {
+		presetsList = append(presetsList, toGeneratorPreset(preset))
+	}
+
+	return presetsList, err
+}
+
+// CreatePreset creates a named generator preset for a given user.
+//
+// Parameters:
+// - userId: the ID of the user.
+// - name: the name of the preset, unique per user.
+// - options: the generator options, they are validated before saving.
+//
+// Returns:
+// - GeneratorPreset: the created preset.
+// - error: ErrInvalidPolicy, ErrPresetExists or any creation error.
+func (s *GeneratorService) CreatePreset(userId uint, name string, options GeneratorOptions) (GeneratorPreset, error) {
+	options = withDefaults(options)
+	if _, err := generate(options); err != nil {
+		return GeneratorPreset{}, err
+	}
+
+	if err := s.checkName(0, userId, name); err != nil {
+		return GeneratorPreset{}, err
+	}
+
+	presetModel := s.getModel()
+
+	preset, err := presetModel.Create(toPresetModel(userId, name, options))
+	if err != nil {
+		return GeneratorPreset{}, err
+	}
+
+	return toGeneratorPreset(preset), nil
+}
+
+// UpdatePreset updates a generator preset of a given user.
+//
+// Parameters:
+// - id: the ID of the preset.
+// - userId: the ID of the user.
+// - name: the new name of the preset.
+// - options: the new generator options.
+//
+// Returns:
+// - uint: the ID of the updated preset.
+// - error: ErrInvalidPolicy, ErrPresetExists, gorm.ErrRecordNotFound or any update error.
+func (s *GeneratorService) UpdatePreset(id, userId uint, name string, options GeneratorOptions) (uint, error) {
+	options = withDefaults(options)
+	if _, err := generate(options); err != nil {
+		return id, err
+	}
+
+	if err := s.checkName(id, userId, name); err != nil {
+		return id, err
+	}
+
+	presetModel := s.getModel()
+
+	return presetModel.Update(id, userId, toPresetModel(userId, name, options))
+}
+
+// DeletePreset deletes a generator preset by its ID and user ID.
+//
+// Parameters:
+// - id: the ID of the preset to be deleted.
+// - userId: the ID of the user requesting the deletion.
+//
+// Return type: error.
+func (s *GeneratorService) DeletePreset(id, userId uint) error {
+	presetModel := s.getModel()
+
+	return presetModel.Delete(id, userId)
+}
+
+// checkName returns ErrPresetExists if another preset of the user already has the name.
+func (s *GeneratorService) checkName(id, userId uint, name string) error {
+	var count int64
+
+	err := s.DB.Model(&models.GeneratorPreset{}).
+		Where("user_id = ? AND name = ? AND id <> ?", userId, name, id).
+		Count(&count).Error
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return ErrPresetExists
+	}
+
+	return nil
+}
+
+// generate runs the generator for the given options.
+func generate(options GeneratorOptions) (GeneratedPassword, error) {
+	options = withDefaults(options)
+
+	var value string
+	var entropy float64
+	var err error
+
+	switch options.Type {
+	case models.GeneratorTypePassword:
+		value, entropy, err = generator.Password(generator.Policy{
+			Length:           options.Length,
+			Lowercase:        options.Lowercase,
+			Uppercase:        options.Uppercase,
+			Digits:           options.Digits,
+			Symbols:          options.Symbols,
+			ExcludeAmbiguous: options.ExcludeAmbiguous,
+			MinLowercase:     options.MinLowercase,
+			MinUppercase:     options.MinUppercase,
+			MinDigits:        options.MinDigits,
+			MinSymbols:       options.MinSymbols,
+		})
+	case models.GeneratorTypePassphrase:
+		value, entropy, err = generator.Passphrase(generator.PassphrasePolicy{
+			Words:         options.Words,
+			Separator:     options.Separator,
+			Capitalize:    options.Capitalize,
+			IncludeNumber: options.IncludeNumber,
+		})
+	default:
+		return GeneratedPassword{}, fmt.Errorf("%w: unknown type %q", ErrInvalidPolicy, options.Type)
+	}
+
+	if err != nil {
+		return GeneratedPassword{}, err
+	}
+
+	return GeneratedPassword{Password: value, Entropy: entropy}, nil
+}
+
+// withDefaults fills the options that were not set.
+//
+// Passwords are 20 characters long and use every character class when none is enabled,
+// passphrases have 5 words separated by a dash.
+func withDefaults(options GeneratorOptions) GeneratorOptions {
+	if options.Type == "" {
+		options.Type = models.GeneratorTypePassword
+	}
+
+	switch options.Type {
+	case models.GeneratorTypePassword:
+		if options.Length == 0 {
+			options.Length = defaultLength
+		}
+
+		if !options.Lowercase && !options.Uppercase && !options.Digits && !options.Symbols {
+			options.Lowercase, options.Uppercase, options.Digits, options.Symbols = true, true, true, true
+		}
+	case models.GeneratorTypePassphrase:
+		if options.Words == 0 {
+			options.Words = defaultWords
+		}
+
+		if options.Separator == "" {
+			options.Separator = defaultSeparator
+		}
+	}
+
+	return options
+}
+
+// toPresetModel converts the options to a preset model.
+func toPresetModel(userId uint, name string, options GeneratorOptions) models.GeneratorPreset {
+	return models.GeneratorPreset{
+		UserID:           userId,
+		Name:             name,
+		Type:             options.Type,
+		Length:           options.Length,
+		Lowercase:        options.Lowercase,
+		Uppercase:        options.Uppercase,
+		Digits:           options.Digits,
+		Symbols:          options.Symbols,
+		ExcludeAmbiguous: options.ExcludeAmbiguous,
+		MinLowercase:     options.MinLowercase,
+		MinUppercase:     options.MinUppercase,
+		MinDigits:        options.MinDigits,
+		MinSymbols:       options.MinSymbols,
+		Words:            options.Words,
+		Separator:        options.Separator,
+		Capitalize:       options.Capitalize,
+		IncludeNumber:    options.IncludeNumber,
+	}
+}
+
+// toGeneratorPreset converts a preset model to its response representation.
+func toGeneratorPreset(preset models.GeneratorPreset) GeneratorPreset {
+	return GeneratorPreset{
+		ID:   preset.ID,
+		Name: preset.Name,
+		GeneratorOptions: GeneratorOptions{
+			Type:             preset.Type,
+			Length:           preset.Length,
+			Lowercase:        preset.Lowercase,
+			Uppercase:        preset.Uppercase,
+			Digits:           preset.Digits,
+			Symbols:          preset.Symbols,
+			ExcludeAmbiguous: preset.ExcludeAmbiguous,
+			MinLowercase:     preset.MinLowercase,
+			MinUppercase:     preset.MinUppercase,
+			MinDigits:        preset.MinDigits,
+			MinSymbols:       preset.MinSymbols,
+			Words:            preset.Words,
+			Separator:        preset.Separator,
+			Capitalize:       preset.Capitalize,
+			IncludeNumber:    preset.IncludeNumber,
+		},
+	}
+}
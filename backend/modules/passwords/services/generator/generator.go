@@ -0,0 +1,230 @@
+package generator
+
+import (
+	"crypto/rand"
+	_ "embed"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"unicode"
+)
+
+const (
+	lowercase = "abcdefghijklmnopqrstuvwxyz"
+	uppercase = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	digits    = "0123456789"
+	symbols   = "!@#$%^&*()-_=+[]{};:,.<>/?~"
+	ambiguous = "Il1O0o|`'\""
+
+	MaxLength = 128
+	MaxWords  = 20
+)
+
+var ErrInvalidPolicy = errors.New("invalid generator policy")
+
+var (
+	ErrInvalidLength  = fmt.Errorf("%w: length must be between 4 and 128", ErrInvalidPolicy)
+	ErrInvalidWords   = fmt.Errorf("%w: words must be between 3 and 20", ErrInvalidPolicy)
+	ErrNoCharacters   = fmt.Errorf("%w: at least one character class must be enabled", ErrInvalidPolicy)
+	ErrMinimumsTooBig = fmt.Errorf("%w: minimum counts exceed the length", ErrInvalidPolicy)
+	ErrMinimumClass   = fmt.Errorf("%w: minimum counts must be positive and set only for enabled classes", ErrInvalidPolicy)
+)
+
+//go:embed wordlist.txt
+var wordlistFile string
+
+var wordlist = strings.Fields(wordlistFile)
+
+// Policy describes how a random password is generated.
+type Policy struct {
+	Length           int
+	Lowercase        bool
+	Uppercase        bool
+	Digits           bool
+	Symbols          bool
+	ExcludeAmbiguous bool
+	MinLowercase     int
+	MinUppercase     int
+	MinDigits        int
+	MinSymbols       int
+}
+
+// PassphrasePolicy describes how a diceware-style passphrase is generated.
+type PassphrasePolicy struct {
+	Words         int
+	Separator     string
+	Capitalize    bool
+	IncludeNumber bool
+}
+
+// Password generates a random password from the given policy.
+//
+// policy: the length, enabled character classes and minimum counts.
+// Returns the password and its entropy in bits.
+func Password(policy Policy) (string, float64, error) {
+	if policy.Length < 4 || policy.Length > MaxLength {
+		return "", 0, ErrInvalidLength
+	}
+
+	classes := []struct {
+		enabled bool
+		chars   string
+		minimum int
+	}{
+		{policy.Lowercase, lowercase, policy.MinLowercase},
+		{policy.Uppercase, uppercase, policy.MinUppercase},
+		{policy.Digits, digits, policy.MinDigits},
+		{policy.Symbols, symbols, policy.MinSymbols},
+	}
+
+	var pool strings.Builder
+	var result []byte
+	minimums := 0
+
+	for _, c := range classes {
+		if c.minimum < 0 {
+			return "", 0, ErrMinimumClass
+		}
+
+		if !c.enabled {
+			if c.minimum > 0 {
+				return "", 0, ErrMinimumClass
+			}
+			continue
+		}
+
+		chars := c.chars
+		if policy.ExcludeAmbiguous {
+			chars = removeChars(chars, ambiguous)
+		}
+
+		pool.WriteString(chars)
+		minimums += c.minimum
+
+		for i := 0; i < c.minimum; i++ {
+			char, err := pick(chars)
+			if err != nil {
+				return "", 0, err
+			}
+			result = append(result, char)
+		}
+	}
+
+	if pool.Len() == 0 {
+		return "", 0, ErrNoCharacters
+	}
+
+	if minimums > policy.Length {
+		return "", 0, ErrMinimumsTooBig
+	}
+
+	for len(result) < policy.Length {
+		char, err := pick(pool.String())
+		if err != nil {
+			return "", 0, err
+		}
+		result = append(result, char)
+	}
+
+	if err := shuffle(result); err != nil {
+		return "", 0, err
+	}
+
+	return string(result), float64(policy.Length) * math.Log2(float64(pool.Len())), nil
+}
+
+// Passphrase generates a diceware-style passphrase from the embedded wordlist.
+//
+// policy: the number of words, separator and capitalisation.
+// Returns the passphrase and its entropy in bits.
+func Passphrase(policy PassphrasePolicy) (string, float64, error) {
+	if policy.Words < 3 || policy.Words > MaxWords {
+		return "", 0, ErrInvalidWords
+	}
+
+	words := make([]string, policy.Words)
+	for i := range words {
+		index, err := randomInt(len(wordlist))
+		if err != nil {
+			return "", 0, err
+		}
+
+		word := wordlist[index]
+		if policy.Capitalize {
+			word = string(unicode.ToUpper(rune(word[0]))) + word[1:]
+		}
+		words[i] = word
+	}
+
+	entropy := float64(policy.Words) * math.Log2(float64(len(wordlist)))
+
+	if policy.IncludeNumber {
+		index, err := randomInt(len(words))
+		if err != nil {
+			return "", 0, err
+		}
+
+		number, err := pick(digits)
+		if err != nil {
+			return "", 0, err
+		}
+
+		words[index] += string(number)
+		entropy += math.Log2(float64(len(words) * len(digits)))
+	}
+
+	return strings.Join(words, policy.Separator), entropy, nil
+}
+
+// Words returns a copy of the embedded wordlist.
+//
+// It does not take any parameters.
+// It returns the words in alphabetical order.
+func Words() []string {
+	return append([]string{}, wordlist...)
+}
+
+// pick returns a random character from the given set.
+func pick(chars string) (byte, error) {
+	index, err := randomInt(len(chars))
+	if err != nil {
+		return 0, err
+	}
+
+	return chars[index], nil
+}
+
+// shuffle shuffles the characters in place with the Fisher-Yates algorithm.
+func shuffle(chars []byte) error {
+	for i := len(chars) - 1; i > 0; i-- {
+		j, err := randomInt(i + 1)
+		if err != nil {
+			return err
+		}
+		chars[i], chars[j] = chars[j], chars[i]
+	}
+
+	return nil
+}
+
+// randomInt returns a uniform random number in [0, max) read from crypto/rand.
+func randomInt(max int) (int, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(max)))
+	if err != nil {
+		return 0, err
+	}
+
+	return int(n.Int64()), nil
+}
+
+// removeChars returns chars without any of the excluded characters.
+func removeChars(chars, excluded string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(excluded, r) {
+			return -1
+		}
+		return r
+	}, chars)
+}
@@ -0,0 +1,360 @@
+package services
+
+import (
+	"backend/modules/passwords/models"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidItem = errors.New("invalid item")
+
+const (
+	WifiOpen           = "open"
+	WifiWEP            = "wep"
+	WifiWPA            = "wpa"
+	WifiWPA2           = "wpa2"
+	WifiWPA3           = "wpa3"
+	WifiWPA2Enterprise = "wpa2_enterprise"
+)
+
+type Item struct {
+	Type     string    `json:"type"`
+	Note     *Note     `json:"note,omitempty"`
+	Card     *Card     `json:"card,omitempty"`
+	Identity *Identity `json:"identity,omitempty"`
+	Wifi     *Wifi     `json:"wifi,omitempty"`
+}
+
+type Note struct {
+	Text string `json:"text"`
+}
+
+type Card struct {
+	Cardholder string `json:"cardholder"`
+	Brand      string `json:"brand"`
+	Number     string `json:"number"`
+	ExpMonth   string `json:"exp_month"`
+	ExpYear    string `json:"exp_year"`
+	CVV        string `json:"cvv"`
+}
+
+type Identity struct {
+	Title          string  `json:"title"`
+	FirstName      string  `json:"first_name"`
+	MiddleName     string  `json:"middle_name"`
+	LastName       string  `json:"last_name"`
+	Company        string  `json:"company"`
+	Email          string  `json:"email"`
+	Phone          string  `json:"phone"`
+	Address        Address `json:"address"`
+	PassportNumber string  `json:"passport_number"`
+	LicenseNumber  string  `json:"license_number"`
+	NationalID     string  `json:"national_id"`
+}
+
+type Address struct {
+	Line1      string `json:"line1"`
+	Line2      string `json:"line2"`
+	City       string `json:"city"`
+	State      string `json:"state"`
+	PostalCode string `json:"postal_code"`
+	Country    string `json:"country"`
+}
+
+type Wifi struct {
+	SSID     string `json:"ssid"`
+	Security string `json:"security"`
+	Key      string `json:"key"`
+	Hidden   bool   `json:"hidden"`
+}
+
+// validateItem checks the type-specific payload of an item.
+//
+// Values of client-encrypted entries are ciphertext, so only the presence of the payload is checked then.
+func validateItem(item Item, clientEncrypted bool) error {
+	payloads := map[string]bool{
+		models.TypeNote:     item.Note != nil,
+		models.TypeCard:     item.Card != nil,
+		models.TypeIdentity: item.Identity != nil,
+		models.TypeWifi:     item.Wifi != nil,
+	}
+
+	for itemType, present := range payloads {
+		if present && itemType != item.Type {
+			return fmt.Errorf("%w: %s data given for a %s item", ErrInvalidItem, itemType, item.Type)
+		}
+	}
+
+	switch item.Type {
+	case models.TypeLogin:
+		return nil
+	case models.TypeNote, models.TypeCard, models.TypeIdentity, models.TypeWifi:
+		if !payloads[item.Type] {
+			return fmt.Errorf("%w: missing %s data", ErrInvalidItem, item.Type)
+		}
+	default:
+		return fmt.Errorf("%w: unknown type %q", ErrInvalidItem, item.Type)
+	}
+
+	if clientEncrypted {
+		return nil
+	}
+
+	switch item.Type {
+	case models.TypeCard:
+		return validateCard(item.Card)
+	case models.TypeIdentity:
+		return validateIdentity(item.Identity)
+	case models.TypeWifi:
+		return validateWifi(item.Wifi)
+	}
+
+	return nil
+}
+
+// validateCard checks the number with the Luhn algorithm, the expiry date and the CVV.
+func validateCard(card *Card) error {
+	card.Number = strings.NewReplacer(" ", "", "-", "").Replace(card.Number)
+
+	if card.Number != "" && !luhn(card.Number) {
+		return fmt.Errorf("%w: invalid card number", ErrInvalidItem)
+	}
+
+	if card.ExpMonth != "" {
+		month, err := strconv.Atoi(card.ExpMonth)
+		if err != nil || !isDigits(card.ExpMonth) || month < 1 || month > 12 {
+			return fmt.Errorf("%w: expiry month must be between 1 and 12", ErrInvalidItem)
+		}
+		card.ExpMonth = fmt.Sprintf("%02d", month)
+	}
+
+	if card.ExpYear != "" {
+		year, err := strconv.Atoi(card.ExpYear)
+		if err != nil || !isDigits(card.ExpYear) || (len(card.ExpYear) != 2 && len(card.ExpYear) != 4) {
+			return fmt.Errorf("%w: expiry year must have 2 or 4 digits", ErrInvalidItem)
+		}
+		if year < 100 {
+			card.ExpYear = strconv.Itoa(2000 + year)
+		}
+	}
+
+	if card.CVV != "" {
+		if !isDigits(card.CVV) || len(card.CVV) < 3 || len(card.CVV) > 4 {
+			return fmt.Errorf("%w: CVV must have 3 or 4 digits", ErrInvalidItem)
+		}
+	}
+
+	return nil
+}
+
+// validateIdentity checks the email address of an identity.
+func validateIdentity(identity *Identity) error {
+	if identity.Email == "" {
+		return nil
+	}
+
+	if _, err := mail.ParseAddress(identity.Email); err != nil {
+		return fmt.Errorf("%w: invalid email address", ErrInvalidItem)
+	}
+
+	return nil
+}
+
+// validateWifi checks the security type and the key length required by it.
+func validateWifi(wifi *Wifi) error {
+	if wifi.SSID == "" || len(wifi.SSID) > 32 {
+		return fmt.Errorf("%w: SSID must have 1 to 32 bytes", ErrInvalidItem)
+	}
+
+	switch wifi.Security {
+	case WifiOpen:
+		if wifi.Key != "" {
+			return fmt.Errorf("%w: open networks have no key", ErrInvalidItem)
+		}
+	case WifiWEP:
+		switch len(wifi.Key) {
+		case 5, 13:
+		case 10, 26:
+			if _, err := hex.DecodeString(wifi.Key); err != nil {
+				return fmt.Errorf("%w: WEP keys of 10 or 26 characters must be hex digits", ErrInvalidItem)
+			}
+		default:
+			return fmt.Errorf("%w: WEP key must have 5 or 13 characters, or 10 or 26 hex digits", ErrInvalidItem)
+		}
+	case WifiWPA, WifiWPA2, WifiWPA3:
+		if len(wifi.Key) < 8 || len(wifi.Key) > 63 {
+			return fmt.Errorf("%w: WPA key must have 8 to 63 characters", ErrInvalidItem)
+		}
+	case WifiWPA2Enterprise:
+	default:
+		return fmt.Errorf("%w: unknown security type %q", ErrInvalidItem, wifi.Security)
+	}
+
+	return nil
+}
+
+// luhn reports whether a card number of 12 to 19 digits passes the Luhn checksum.
+func luhn(number string) bool {
+	if len(number) < 12 || len(number) > 19 {
+		return false
+	}
+
+	sum := 0
+	for i := range number {
+		digit := int(number[len(number)-1-i] - '0')
+		if digit < 0 || digit > 9 {
+			return false
+		}
+
+		if i%2 == 1 {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+	}
+
+	return sum%10 == 0
+}
+
+// isDigits reports whether a value only consists of ASCII digits.
+func isDigits(value string) bool {
+	for i := range value {
+		if value[i] < '0' || value[i] > '9' {
+			return false
+		}
+	}
+
+	return value != ""
+}
+
+// secret returns the value of an item used for strength estimation and breach checks.
+//
+// The payload is nil if the stored data could not be decoded, such items have no secret.
+func (i Item) secret(login string) (string, bool) {
+	switch i.Type {
+	case models.TypeLogin:
+		return login, true
+	case models.TypeWifi:
+		if i.Wifi == nil {
+			return "", false
+		}
+
+		return i.Wifi.Key, i.Wifi.Key != ""
+	}
+
+	return "", false
+}
+
+// encodeItem serializes the type-specific payload of an item, empty for logins.
+func encodeItem(item Item) (string, error) {
+	var payload any
+
+	switch item.Type {
+	case models.TypeNote:
+		payload = item.Note
+	case models.TypeCard:
+		payload = item.Card
+	case models.TypeIdentity:
+		payload = item.Identity
+	case models.TypeWifi:
+		payload = item.Wifi
+	default:
+		return "", nil
+	}
+
+	data, err := json.Marshal(payload)
+
+	return string(data), err
+}
+
+// decodeItem deserializes the stored payload of an item.
+//
+// A payload that cannot be decoded is left out of the item.
+func decodeItem(itemType, data string) Item {
+	item := Item{Type: itemType}
+	if data == "" {
+		return item
+	}
+
+	switch itemType {
+	case models.TypeNote:
+		item.Note = &Note{}
+		if json.Unmarshal([]byte(data), item.Note) != nil {
+			item.Note = nil
+		}
+	case models.TypeCard:
+		item.Card = &Card{}
+		if json.Unmarshal([]byte(data), item.Card) != nil {
+			item.Card = nil
+		}
+	case models.TypeIdentity:
+		item.Identity = &Identity{}
+		if json.Unmarshal([]byte(data), item.Identity) != nil {
+			item.Identity = nil
+		}
+	case models.TypeWifi:
+		item.Wifi = &Wifi{}
+		if json.Unmarshal([]byte(data), item.Wifi) != nil {
+			item.Wifi = nil
+		}
+	}
+
+	return item
+}
+
+// mask hides the secrets of an entry in list and get responses, the full entry is returned by RevealPassword.
+//
+// Card and document numbers keep their last 4 characters, the other secrets are emptied.
+// Client-encrypted values are ciphertext, so they are emptied as well.
+func (p *Password) mask() {
+	maskEnd := maskEnd
+	if p.Encryption == models.EncryptionClient {
+		maskEnd = func(string) string { return "" }
+	}
+
+	p.Password = ""
+	p.Totp = ""
+
+	if p.Note != nil {
+		p.Note.Text = ""
+	}
+
+	for i := range p.Fields {
+		if p.Fields[i].Type == models.FieldHidden {
+			p.Fields[i].Value = ""
+		}
+	}
+
+	if p.Card != nil {
+		p.Card.Number = maskEnd(p.Card.Number)
+		p.Card.CVV = ""
+	}
+
+	if p.Identity != nil {
+		p.Identity.PassportNumber = maskEnd(p.Identity.PassportNumber)
+		p.Identity.LicenseNumber = maskEnd(p.Identity.LicenseNumber)
+		p.Identity.NationalID = maskEnd(p.Identity.NationalID)
+	}
+
+	if p.Wifi != nil {
+		p.Wifi.Key = ""
+	}
+}
+
+// maskEnd replaces all but the last 4 characters of a value.
+func maskEnd(value string) string {
+	runes := []rune(value)
+
+	if len(runes) <= 4 {
+		return strings.Repeat("*", len(runes))
+	}
+
+	return strings.Repeat("*", len(runes)-4) + string(runes[len(runes)-4:])
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	models2 "backend/modules/organizations/models"
+	services3 "backend/modules/organizations/services"
+	services2 "backend/modules/tags/services"
+)
+
+// GetAccess returns what a user can do with a password, whether it is theirs, shared with them
+// or in a collection of one of their organizations.
+//
+// Parameters:
+// - id: the ID of the password.
+// - userId: the ID of the user.
+//
+// Returns:
+// - services3.Access: the effective permission of the user.
+// - error: gorm.ErrRecordNotFound if the password is not accessible to the user.
+func (s *PasswordService) GetAccess(id, userId uint) (services3.Access, error) {
+	authorizationService := services3.AuthorizationService{DB: s.DB}
+
+	return authorizationService.GetAccess(userId, id)
+}
+
+// GetCollectionPasswords returns the entries of an organization collection a user can read.
+//
+// Secrets are masked in the list, use RevealPassword or CopySecret to read them.
+//
+// Parameters:
+// - organizationId: the ID of the organization.
+// - collectionId: the ID of the collection.
+// - userId: the ID of the user.
+//
+// Returns:
+// - []Password: the entries ordered by name.
+// - error: gorm.ErrRecordNotFound if the collection is not accessible to the user, or any retrieval error.
+func (s *PasswordService) GetCollectionPasswords(organizationId, collectionId, userId uint) ([]Password, error) {
+	authorizationService := services3.AuthorizationService{DB: s.DB}
+
+	if _, err := authorizationService.GetCollectionPermission(organizationId, collectionId, userId); err != nil {
+		return nil, err
+	}
+
+	collectionModel := models2.CollectionModel{DB: s.DB}
+
+	entries, err := collectionModel.GetPasswords(collectionId)
+	if err != nil {
+		return nil, err
+	}
+
+	passwordModel := s.getModel()
+
+	passwordsList := []Password{}
+
+	for _, entry := range entries {
+		password, err := passwordModel.Get(entry.ID, entry.UserID)
+		if err != nil {
+			return nil, err
+		}
+
+		item := toOrganizationPassword(toPassword(password), entry.UserID == userId)
+		item.mask()
+		passwordsList = append(passwordsList, item)
+	}
+
+	return passwordsList, nil
+}
+
+// GetOrganizationPassword returns a password a user can read through an organization or a share.
+//
+// Secrets are masked, use RevealPassword or CopySecret to read them. The category and tags of the owner are hidden.
+//
+// Parameters:
+// - id: the ID of the password.
+// - userId: the ID of the user.
+//
+// Returns:
+// - Password: the password.
+// - error: gorm.ErrRecordNotFound if the password is not accessible to the user, or any decryption error.
+func (s *PasswordService) GetOrganizationPassword(id, userId uint) (Password, error) {
+	authorizationService := services3.AuthorizationService{DB: s.DB}
+
+	access, err := authorizationService.Authorize(userId, id, services3.AccessRead)
+	if err != nil {
+		return Password{}, err
+	}
+
+	password, err := s.GetPassword(id, access.OwnerID)
+	if err != nil {
+		return Password{}, err
+	}
+
+	return toOrganizationPassword(password, access.Manage), nil
+}
+
+// UpdateOrganizationPassword updates a password a user can write to through an organization or a share.
+//
+// The category, tags, rotation interval and item values of the owner are kept.
+//
+// Parameters:
+// - id: the ID of the password.
+// - userId: the ID of the user.
+// - data: the new values of the password.
+//
+// Returns:
+// - uint: the ID of the updated password.
+// - error: gorm.ErrRecordNotFound, services3.ErrAccessDenied or any update error.
+func (s *PasswordService) UpdateOrganizationPassword(id, userId uint, data PasswordData) (uint, error) {
+	authorizationService := services3.AuthorizationService{DB: s.DB}
+
+	access, err := authorizationService.Authorize(userId, id, services3.AccessWrite)
+	if err != nil {
+		return id, err
+	}
+
+	return s.updateForOwner(id, access.OwnerID, data)
+}
+
+// toOrganizationPassword hides the category, tags and usage of a password from users other than its owner.
+func toOrganizationPassword(password Password, owner bool) Password {
+	if !owner {
+		password.CategoryID = nil
+		password.Tags = []services2.Tag{}
+		password.RotationDays = nil
+		password.LastUsedAt = nil
+	}
+
+	return password
+}
@@ -0,0 +1,637 @@
+package services
+
+import (
+	"backend/modules/breaches/services"
+	models2 "backend/modules/categories/models"
+	"backend/modules/passwords/models"
+	"backend/modules/passwords/services/strength"
+	"backend/modules/passwords/services/totp"
+	models4 "backend/modules/tags/models"
+	services2 "backend/modules/tags/services"
+	models3 "backend/modules/users/models"
+	"backend/services/encryption"
+	"errors"
+	"gorm.io/gorm"
+	"strings"
+	"time"
+)
+
+var (
+	ErrCategoryNotFound = errors.New("category not found")
+	ErrTagNotFound      = errors.New("tag not found")
+	ErrTypeMismatch     = errors.New("the entry has another type, update it with the route of its type")
+)
+
+type PasswordService struct {
+	DB *gorm.DB
+	// Cipher decrypts the passwords instead of the cipher of their owner when set, see models.PasswordModel.
+	Cipher *encryption.Cipher
+}
+
+type Password struct {
+	ID         uint  `json:"id"`
+	CategoryID *uint `json:"category_id"`
+	Item
+	Name          string          `json:"name"`
+	Login         string          `json:"login"`
+	Password      string          `json:"password"`
+	Fields        []Field         `json:"fields"`
+	URIs          []URI           `json:"uris"`
+	Tags          []services2.Tag `json:"tags"`
+	Totp          string          `json:"totp"`
+	Encryption    string          `json:"encryption"`
+	StrengthScore *int            `json:"strength_score"`
+	StrengthFlags []string        `json:"strength_flags"`
+	Breached      *int            `json:"breached"`
+	RotationDays  *int            `json:"rotation_days"`
+	RotatedAt     *time.Time      `json:"rotated_at"`
+	LastUsedAt    *time.Time      `json:"last_used_at"`
+}
+
+type PasswordData struct {
+	CategoryID *uint
+	Item
+	Name     string
+	Login    string
+	Password string
+	Fields   []Field
+	URIs     []URI
+	Totp     string
+	TagIDs   []uint
+	// RotationDays overrides the rotation interval of the category.
+	RotationDays *int
+}
+
+type PasswordFilter struct {
+	CategoryID *uint
+	Type       string
+	Score      *int
+	MaxScore   *int
+	Breached   *bool
+	TagIDs     []uint
+}
+
+type Strength struct {
+	Score            int      `json:"score"`
+	Guesses          float64  `json:"guesses"`
+	Entropy          float64  `json:"entropy"`
+	CrackTimeSeconds float64  `json:"crack_time_seconds"`
+	CrackTimeDisplay string   `json:"crack_time_display"`
+	Flags            []string `json:"flags"`
+	Feedback         []string `json:"feedback"`
+}
+
+// getModel returns a PasswordModel.
+//
+// No parameters.
+// Returns a models.PasswordModel.
+func (s *PasswordService) getModel() models.PasswordModel {
+	return models.PasswordModel{DB: s.DB, Cipher: s.Cipher}
+}
+
+// GetPasswords returns the passwords of a given user.
+//
+// Parameters:
+// - userId: the ID of the user.
+// - filter: optional category, type, strength score, breach and tag filters.
+//
+// Returns:
+// - []Password: the list of passwords with masked secrets.
+// - error: any error that occurred during the retrieval process.
+func (s *PasswordService) GetPasswords(userId uint, filter PasswordFilter) ([]Password, error) {
+	passwordModel := s.getModel()
+
+	passwords, err := passwordModel.GetAll(userId, models.PasswordFilter{
+		CategoryID: filter.CategoryID,
+		Type:       filter.Type,
+		Score:      filter.Score,
+		MaxScore:   filter.MaxScore,
+		Breached:   filter.Breached,
+		TagIDs:     filter.TagIDs,
+	})
+
+	passwordsList := []Password{}
+
+	for _, password := range passwords {
+		item := toPassword(password)
+		item.mask()
+		passwordsList = append(passwordsList, item)
+	}
+
+	return passwordsList, err
+}
+
+// GetVault returns the server-encrypted passwords of a given user with their secrets, used by exports.
+//
+// Client-encrypted passwords are skipped, the server cannot decrypt them.
+//
+// Parameters:
+// - userId: the ID of the user.
+//
+// Returns:
+// - []Password: the passwords with plaintext secrets.
+// - error: any error that occurred during the retrieval or decryption process.
+func (s *PasswordService) GetVault(userId uint) ([]Password, error) {
+	passwordModel := s.getModel()
+
+	passwords, err := passwordModel.GetAll(userId, models.PasswordFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	passwordsList := []Password{}
+
+	for _, password := range passwords {
+		if password.Encryption == models.EncryptionClient {
+			continue
+		}
+
+		passwordsList = append(passwordsList, toPassword(password))
+	}
+
+	return passwordsList, nil
+}
+
+// GetPassword returns a single password of a given user.
+//
+// Secrets are masked, use RevealPassword or CopySecret to read them.
+//
+// Parameters:
+// - id: the ID of the password.
+// - userId: the ID of the user.
+//
+// Returns:
+// - Password: the found password with masked secrets.
+// - error: gorm.ErrRecordNotFound if the password does not belong to the user.
+func (s *PasswordService) GetPassword(id, userId uint) (Password, error) {
+	password, err := s.getPassword(id, userId)
+	if err != nil {
+		return Password{}, err
+	}
+
+	password.mask()
+
+	return password, nil
+}
+
+// getPassword returns a single password of a given user with its secrets.
+func (s *PasswordService) getPassword(id, userId uint) (Password, error) {
+	passwordModel := s.getModel()
+
+	password, err := passwordModel.Get(id, userId)
+	if err != nil {
+		return Password{}, err
+	}
+
+	return toPassword(password), nil
+}
+
+// CreatePassword creates a new password for a given user.
+//
+// Parameters:
+// - userId: the ID of the user.
+// - data: the values of the new password.
+//
+// Returns:
+// - Password: the created password.
+// - error: ErrCategoryNotFound or ErrTagNotFound if the category or a tag does not belong to the user, or any creation error.
+func (s *PasswordService) CreatePassword(userId uint, data PasswordData) (Password, error) {
+	if err := s.checkCategory(userId, data.CategoryID); err != nil {
+		return Password{}, err
+	}
+
+	if err := s.checkTags(userId, data.TagIDs); err != nil {
+		return Password{}, err
+	}
+
+	password, err := s.newPassword(userId, data)
+	if err != nil {
+		return Password{}, err
+	}
+
+	passwordModel := s.getModel()
+
+	password, err = passwordModel.Create(password)
+	if err != nil {
+		return Password{}, err
+	}
+
+	return s.GetPassword(password.ID, userId)
+}
+
+// UpdatePassword updates a password of a given user.
+//
+// The type of an entry cannot change, so a login update cannot erase the data of a card and the other way around.
+// The content shared with other users is refreshed.
+//
+// Parameters:
+// - id: the ID of the password to update.
+// - userId: the ID of the user.
+// - data: the new values of the password.
+//
+// Returns:
+// - uint: the ID of the updated password.
+// - error: ErrCategoryNotFound, ErrTagNotFound, ErrTypeMismatch, gorm.ErrRecordNotFound or any update error.
+func (s *PasswordService) UpdatePassword(id, userId uint, data PasswordData) (uint, error) {
+	if err := s.checkType(id, userId, data.Type); err != nil {
+		return id, err
+	}
+
+	if err := s.checkCategory(userId, data.CategoryID); err != nil {
+		return id, err
+	}
+
+	if err := s.checkTags(userId, data.TagIDs); err != nil {
+		return id, err
+	}
+
+	password, err := s.newPassword(userId, data)
+	if err != nil {
+		return id, err
+	}
+
+	passwordModel := s.getModel()
+
+	if _, err := passwordModel.Update(id, userId, password); err != nil {
+		return id, err
+	}
+
+	return id, s.refreshShare(id, userId)
+}
+
+// DeletePassword moves a password to the trash by its ID and user ID.
+//
+// Its attachments are kept until the password is purged from the trash.
+//
+// Parameters:
+// - id: the ID of the password to be deleted.
+// - userId: the ID of the user requesting the deletion.
+//
+// Return type: error.
+func (s *PasswordService) DeletePassword(id, userId uint) error {
+	passwordModel := s.getModel()
+
+	return passwordModel.Delete(id, userId)
+}
+
+// checkType makes sure the password exists, belongs to the user and has the given type, empty for logins.
+func (s *PasswordService) checkType(id, userId uint, itemType string) error {
+	var password models.Password
+
+	err := s.DB.Select("id", "type").Where("id = ? AND user_id = ?", id, userId).First(&password).Error
+	if err != nil {
+		return err
+	}
+
+	if itemType == "" {
+		itemType = models.TypeLogin
+	}
+
+	if password.Type != itemType {
+		return ErrTypeMismatch
+	}
+
+	return nil
+}
+
+// checkCategory makes sure the category, if any, belongs to the user.
+//
+// Parameters:
+// - userId: the ID of the user.
+// - categoryId: the optional category ID.
+//
+// Returns ErrCategoryNotFound when the category is missing or owned by someone else.
+func (s *PasswordService) checkCategory(userId uint, categoryId *uint) error {
+	if categoryId == nil {
+		return nil
+	}
+
+	categoryModel := models2.CategoryModel{DB: s.DB}
+
+	_, err := categoryModel.Get(*categoryId, userId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrCategoryNotFound
+	}
+
+	return err
+}
+
+// checkTags makes sure the tags belong to the user.
+//
+// Parameters:
+// - userId: the ID of the user.
+// - tagIds: the tag IDs, duplicates are allowed.
+//
+// Returns ErrTagNotFound when a tag is missing or owned by someone else.
+func (s *PasswordService) checkTags(userId uint, tagIds []uint) error {
+	unique := map[uint]bool{}
+	for _, tagId := range tagIds {
+		unique[tagId] = true
+	}
+
+	if len(unique) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, 0, len(unique))
+	for tagId := range unique {
+		ids = append(ids, tagId)
+	}
+
+	tagModel := models4.TagModel{DB: s.DB}
+
+	count, err := tagModel.Count(ids, userId)
+	if err != nil {
+		return err
+	}
+
+	if count != int64(len(ids)) {
+		return ErrTagNotFound
+	}
+
+	return nil
+}
+
+// EstimateStrength estimates the strength of a password without saving it.
+//
+// Parameters:
+// - password: the plaintext password.
+// - userInputs: values the password should not be based on.
+//
+// Returns the strength estimate.
+func (s *PasswordService) EstimateStrength(password string, userInputs []string) Strength {
+	result := strength.Estimate(password, userInputs...)
+
+	return Strength{
+		Score:            result.Score,
+		Guesses:          result.Guesses,
+		Entropy:          result.Entropy,
+		CrackTimeSeconds: result.CrackTimeSeconds,
+		CrackTimeDisplay: result.CrackTimeDisplay,
+		Flags:            result.Flags,
+		Feedback:         result.Feedback,
+	}
+}
+
+// EstimateMissingStrength estimates the strength of server-encrypted logins and WiFi keys saved without a score.
+//
+// It does not take any parameters.
+// It returns an error if an entry cannot be decrypted or saved.
+func (s *PasswordService) EstimateMissingStrength() error {
+	var pending []models.Password
+
+	err := s.DB.Select("id", "user_id").
+		Where("strength_score IS NULL AND encryption = ? AND type IN ?", models.EncryptionServer, []string{models.TypeLogin, models.TypeWifi}).
+		Find(&pending).Error
+	if err != nil {
+		return err
+	}
+
+	passwordModel := s.getModel()
+
+	for _, entry := range pending {
+		password, err := passwordModel.Get(entry.ID, entry.UserID)
+		if err != nil {
+			return err
+		}
+
+		secret, ok := decodeItem(password.Type, password.Data).secret(password.Password)
+		if !ok {
+			continue
+		}
+
+		var user models3.User
+		if err := s.DB.Select("id", "name", "email").Where("id = ?", entry.UserID).First(&user).Error; err != nil {
+			return err
+		}
+
+		result := strength.Estimate(secret, user.Name, user.Email, password.Name, password.Login)
+
+		err = s.DB.Model(&models.Password{}).Where("id = ?", entry.ID).UpdateColumns(map[string]interface{}{
+			"strength_score": result.Score,
+			"strength_flags": strings.Join(result.Flags, ","),
+		}).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RescanBreaches checks the secret of every server-encrypted entry against the breach dataset
+// and stores the new breach counts.
+//
+// The same secrets as on save are checked, the password of logins and the key of WiFi networks.
+//
+// It does not take any parameters.
+// It returns the number of entries whose count changed and an error.
+func (s *PasswordService) RescanBreaches() (int, error) {
+	var userIds []uint
+
+	err := s.DB.Model(&models.Password{}).
+		Where("encryption = ?", models.EncryptionServer).
+		Distinct().
+		Pluck("user_id", &userIds).Error
+	if err != nil {
+		return 0, err
+	}
+
+	passwordModel := s.getModel()
+	breachService := services.BreachService{DB: s.DB}
+	changed := 0
+
+	for _, userId := range userIds {
+		passwords, err := passwordModel.GetAll(userId, models.PasswordFilter{})
+		if err != nil {
+			return changed, err
+		}
+
+		for _, password := range passwords {
+			if password.Encryption != models.EncryptionServer {
+				continue
+			}
+
+			secret, ok := decodeItem(password.Type, password.Data).secret(password.Password)
+			if !ok {
+				continue
+			}
+
+			count, err := breachService.Check(secret)
+			if err != nil {
+				return changed, err
+			}
+
+			if password.Breached != nil && *password.Breached == count {
+				continue
+			}
+
+			err = s.DB.Model(&models.Password{}).Where("id = ?", password.ID).UpdateColumn("breached", count).Error
+			if err != nil {
+				return changed, err
+			}
+			changed++
+		}
+	}
+
+	return changed, nil
+}
+
+// FingerprintMissing computes the fingerprints of server-encrypted entries saved before fingerprints were introduced.
+//
+// It does not take any parameters.
+// It returns an error if an entry cannot be decrypted or saved.
+func (s *PasswordService) FingerprintMissing() error {
+	var pending []models.Password
+
+	err := s.DB.Select("id", "user_id").
+		Where("fingerprint IS NULL AND encryption = ?", models.EncryptionServer).
+		Find(&pending).Error
+	if err != nil {
+		return err
+	}
+
+	passwordModel := s.getModel()
+
+	for _, entry := range pending {
+		password, err := passwordModel.Get(entry.ID, entry.UserID)
+		if err != nil {
+			return err
+		}
+
+		secret, _ := decodeItem(password.Type, password.Data).secret(password.Password)
+
+		if err := passwordModel.SetFingerprint(entry.ID, entry.UserID, secret); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// newPassword builds the password model to store from the input of a user.
+//
+// In the zero-knowledge vault mode the client sends ciphertext, so the server stores it as it is
+// and cannot estimate its strength. Otherwise the TOTP URI and the item data are validated, and the password
+// of logins or the key of WiFi networks is scored and checked against the breach dataset here, before encryption.
+// Only the results are kept.
+func (s *PasswordService) newPassword(userId uint, data PasswordData) (models.Password, error) {
+	var user models3.User
+
+	err := s.DB.Select("id", "name", "email", "vault_mode").Where("id = ?", userId).First(&user).Error
+	if err != nil {
+		return models.Password{}, err
+	}
+
+	if data.Type == "" {
+		data.Type = models.TypeLogin
+	}
+
+	clientEncrypted := user.VaultMode == models3.VaultModeZeroKnowledge
+	if err := validateItem(data.Item, clientEncrypted); err != nil {
+		return models.Password{}, err
+	}
+
+	if err := validateFields(data.Fields, clientEncrypted); err != nil {
+		return models.Password{}, err
+	}
+
+	if err := validateURIs(data.URIs, clientEncrypted); err != nil {
+		return models.Password{}, err
+	}
+
+	itemData, err := encodeItem(data.Item)
+	if err != nil {
+		return models.Password{}, err
+	}
+
+	password := models.Password{
+		UserID:       userId,
+		CategoryID:   data.CategoryID,
+		Type:         data.Type,
+		Name:         data.Name,
+		Login:        data.Login,
+		Password:     data.Password,
+		Fields:       toFieldModels(data.Fields),
+		URIs:         toURIModels(data.URIs),
+		TagIDs:       data.TagIDs,
+		Totp:         data.Totp,
+		Data:         itemData,
+		Encryption:   models.EncryptionServer,
+		RotationDays: data.RotationDays,
+	}
+
+	if clientEncrypted {
+		password.Encryption = models.EncryptionClient
+		return password, nil
+	}
+
+	if data.Totp != "" {
+		if _, err := totp.Parse(data.Totp); err != nil {
+			return models.Password{}, err
+		}
+	}
+
+	secret, ok := data.Item.secret(data.Password)
+	if !ok {
+		return password, nil
+	}
+	password.Secret = secret
+
+	result := strength.Estimate(secret, user.Name, user.Email, data.Name, data.Login)
+	password.StrengthScore = &result.Score
+	password.StrengthFlags = strings.Join(result.Flags, ",")
+
+	breachService := services.BreachService{DB: s.DB}
+
+	breached, err := breachService.Check(secret)
+	if err != nil {
+		return models.Password{}, err
+	}
+	password.Breached = &breached
+
+	return password, nil
+}
+
+// toPassword converts a password model to its response representation.
+func toPassword(password models.Password) Password {
+	return Password{
+		ID:            password.ID,
+		CategoryID:    password.CategoryID,
+		Item:          decodeItem(password.Type, password.Data),
+		Name:          password.Name,
+		Login:         password.Login,
+		Password:      password.Password,
+		Fields:        toFields(password.Fields),
+		URIs:          toURIs(password.URIs),
+		Tags:          toTags(password.Tags),
+		Totp:          password.Totp,
+		Encryption:    password.Encryption,
+		StrengthScore: password.StrengthScore,
+		StrengthFlags: splitFlags(password.StrengthFlags),
+		Breached:      password.Breached,
+		RotationDays:  password.RotationDays,
+		RotatedAt:     password.RotatedAt,
+		LastUsedAt:    password.LastUsedAt,
+	}
+}
+
+// toTags converts the tags of a password to their response representation.
+func toTags(tags []models4.Tag) []services2.Tag {
+	tagsList := []services2.Tag{}
+
+	for _, tag := range tags {
+		tagsList = append(tagsList, services2.ToTag(tag))
+	}
+
+	return tagsList
+}
+
+// splitFlags converts the stored comma separated flags to a list.
+func splitFlags(flags string) []string {
+	if flags == "" {
+		return []string{}
+	}
+
+	return strings.Split(flags, ",")
+}
@@ -0,0 +1,184 @@
+package services
+
+import (
+	services4 "backend/modules/audit/services"
+	"backend/modules/passwords/models"
+	"strconv"
+	"time"
+)
+
+type PasswordRevision struct {
+	ID         uint      `json:"id"`
+	CreatedAt  time.Time `json:"created_at"`
+	CategoryID *uint     `json:"category_id"`
+	Name       string    `json:"name"`
+}
+
+type PasswordRevisionDetails struct {
+	PasswordRevision
+	Item
+	Login      string  `json:"login"`
+	Password   string  `json:"password"`
+	Fields     []Field `json:"fields"`
+	URIs       []URI   `json:"uris"`
+	Totp       string  `json:"totp"`
+	Encryption string  `json:"encryption"`
+}
+
+// getRevisionModel returns a PasswordRevisionModel.
+//
+// No parameters.
+// Returns a models.PasswordRevisionModel.
+func (s *PasswordService) getRevisionModel() models.PasswordRevisionModel {
+	return models.PasswordRevisionModel{DB: s.DB}
+}
+
+// GetRevisions returns the previous values of a password without their secrets.
+//
+// Parameters:
+// - passwordId: the ID of the password.
+// - userId: the ID of the user.
+//
+// Returns:
+// - []PasswordRevision: the revisions, newest first.
+// - error: gorm.ErrRecordNotFound if the password does not belong to the user.
+func (s *PasswordService) GetRevisions(passwordId, userId uint) ([]PasswordRevision, error) {
+	if err := s.checkPassword(passwordId, userId); err != nil {
+		return nil, err
+	}
+
+	revisionModel := s.getRevisionModel()
+
+	revisions, err := revisionModel.GetAll(passwordId, userId)
+
+	revisionsList := []PasswordRevision{}
+
+	for _, revision := range revisions {
+		revisionsList = append(revisionsList, toPasswordRevision(revision))
+	}
+
+	return revisionsList, err
+}
+
+// PurgeRevisions deletes the revisions of all users that are older than the configured retention.
+//
+// It does not take any parameters.
+// It returns the number of deleted revisions and an error if the deletion fails.
+func (s *PasswordService) PurgeRevisions() (int64, error) {
+	revisionModel := s.getRevisionModel()
+
+	return revisionModel.PruneExpired()
+}
+
+// GetRevision returns a single decrypted revision of a password.
+//
+// Secrets are masked as in GetPassword, use RevealRevision to read them.
+//
+// Parameters:
+// - id: the ID of the revision.
+// - passwordId: the ID of the password.
+// - userId: the ID of the user.
+//
+// Returns:
+// - PasswordRevisionDetails: the revision with masked secrets.
+// - error: gorm.ErrRecordNotFound if the revision does not belong to the user.
+func (s *PasswordService) GetRevision(id, passwordId, userId uint) (PasswordRevisionDetails, error) {
+	revision, err := s.getRevision(id, passwordId, userId)
+	if err != nil {
+		return PasswordRevisionDetails{}, err
+	}
+
+	revision.mask()
+
+	return revision, nil
+}
+
+// RevealRevision returns a single revision of a password with its secrets and records the access
+// in the access log of the password.
+//
+// Parameters:
+// - id: the ID of the revision.
+// - passwordId: the ID of the password.
+// - userId: the ID of the user.
+// - client: the token, IP address and user agent of the request, for the access log.
+//
+// Returns:
+// - PasswordRevisionDetails: the revision with its secrets.
+// - error: gorm.ErrRecordNotFound if the revision does not belong to the user, or any logging error.
+func (s *PasswordService) RevealRevision(id, passwordId, userId uint, client services4.Client) (PasswordRevisionDetails, error) {
+	revision, err := s.getRevision(id, passwordId, userId)
+	if err != nil {
+		return PasswordRevisionDetails{}, err
+	}
+
+	field := "revision:" + strconv.FormatUint(uint64(id), 10)
+
+	if err := s.recordAccess(passwordId, userId, models.AccessReveal, field, client); err != nil {
+		return PasswordRevisionDetails{}, err
+	}
+
+	return revision, nil
+}
+
+// getRevision returns a single decrypted revision of a password with its secrets.
+func (s *PasswordService) getRevision(id, passwordId, userId uint) (PasswordRevisionDetails, error) {
+	revisionModel := s.getRevisionModel()
+
+	revision, err := revisionModel.Get(id, passwordId, userId)
+	if err != nil {
+		return PasswordRevisionDetails{}, err
+	}
+
+	return PasswordRevisionDetails{
+		PasswordRevision: toPasswordRevision(revision),
+		Item:             decodeItem(revision.Type, revision.Data),
+		Login:            revision.Login,
+		Password:         revision.Password,
+		Fields:           toFields(revision.Decrypted),
+		URIs:             toURIs(revision.DecryptedURIs),
+		Totp:             revision.Totp,
+		Encryption:       revision.Encryption,
+	}, nil
+}
+
+// RestoreRevision makes a revision the current value of its password.
+//
+// Parameters:
+// - id: the ID of the revision.
+// - passwordId: the ID of the password.
+// - userId: the ID of the user.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound if the revision does not belong to the user, or any update error.
+func (s *PasswordService) RestoreRevision(id, passwordId, userId uint) error {
+	revisionModel := s.getRevisionModel()
+
+	if err := revisionModel.Restore(id, passwordId, userId); err != nil {
+		return err
+	}
+
+	return s.refreshShare(passwordId, userId)
+}
+
+// mask hides the secrets of a revision like Password.mask.
+func (r *PasswordRevisionDetails) mask() {
+	password := Password{Item: r.Item, Password: r.Password, Fields: r.Fields, Totp: r.Totp, Encryption: r.Encryption}
+	password.mask()
+
+	r.Item, r.Password, r.Fields, r.Totp = password.Item, password.Password, password.Fields, password.Totp
+}
+
+// checkPassword makes sure the password exists and belongs to the user.
+func (s *PasswordService) checkPassword(id, userId uint) error {
+	return s.DB.Select("id").Where("id = ? AND user_id = ?", id, userId).First(&models.Password{}).Error
+}
+
+// toPasswordRevision converts a revision model to its response representation.
+func toPasswordRevision(revision models.PasswordRevision) PasswordRevision {
+	return PasswordRevision{
+		ID:         revision.ID,
+		CreatedAt:  revision.CreatedAt,
+		CategoryID: revision.CategoryID,
+		Name:       revision.Name,
+	}
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"time"
+)
+
+type DuePassword struct {
+	ID           uint      `json:"id"`
+	CategoryID   *uint     `json:"category_id"`
+	Type         string    `json:"type"`
+	Name         string    `json:"name"`
+	RotationDays int       `json:"rotation_days"`
+	RotatedAt    time.Time `json:"rotated_at"`
+	DueAt        time.Time `json:"due_at"`
+	Overdue      bool      `json:"overdue"`
+}
+
+// GetDuePasswords returns the passwords of a given user that must be rotated.
+//
+// The rotation interval of a password overrides the one of its category, and its secret is due
+// that many days after it last changed.
+//
+// Parameters:
+// - userId: the ID of the user.
+// - withinDays: also include the passwords due in the next withinDays days, 0 for overdue passwords only.
+//
+// Returns:
+// - []DuePassword: the due passwords, earliest first.
+// - error: any error that occurred during the retrieval process.
+func (s *PasswordService) GetDuePasswords(userId uint, withinDays int) ([]DuePassword, error) {
+	passwordModel := s.getModel()
+
+	now := time.Now()
+
+	due, err := passwordModel.GetDueRotations(userId, now.AddDate(0, 0, withinDays))
+
+	passwordsList := []DuePassword{}
+
+	for _, password := range due {
+		passwordsList = append(passwordsList, DuePassword{
+			ID:           password.ID,
+			CategoryID:   password.CategoryID,
+			Type:         password.Type,
+			Name:         password.Name,
+			RotationDays: password.RotationDays,
+			RotatedAt:    password.RotatedAt,
+			DueAt:        password.DueAt,
+			Overdue:      !password.DueAt.After(now),
+		})
+	}
+
+	return passwordsList, err
+}
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"backend/modules/passwords/models"
+	"errors"
+	"html"
+	"strings"
+	"unicode"
+)
+
+const (
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 100
+)
+
+var ErrInvalidQuery = errors.New("search query must contain a letter or a digit")
+
+type SearchFilter struct {
+	CategoryID *uint
+	Type       string
+	TagIDs     []uint
+	Limit      int
+}
+
+type SearchResult struct {
+	ID         uint    `json:"id"`
+	CategoryID *uint   `json:"category_id"`
+	Type       string  `json:"type"`
+	Name       string  `json:"name"`
+	Category   string  `json:"category"`
+	Rank       float64 `json:"rank"`
+	// Highlight holds HTML-escaped copies of the matched metadata with the matches wrapped in <mark> tags.
+	Highlight SearchHighlight `json:"highlight"`
+}
+
+type SearchHighlight struct {
+	Name     string `json:"name"`
+	Category string `json:"category"`
+	// Tags holds the tag names separated by spaces.
+	Tags string `json:"tags"`
+	// Hosts holds the host names of the URIs separated by spaces, client-encrypted items have none.
+	Hosts string `json:"hosts"`
+	// Login is only set when the user opted in to login search.
+	Login string `json:"login,omitempty"`
+}
+
+// getSearchModel returns a SearchModel.
+//
+// No parameters.
+// Returns a models.SearchModel.
+func (s *PasswordService) getSearchModel() models.SearchModel {
+	return models.SearchModel{DB: s.DB}
+}
+
+// Search searches the metadata of the items of a given user.
+//
+// Only the name, the category and tag names, the URI hosts and, if the user opted in, the login are searched,
+// secrets never are.
+// Words of the query match the beginning of words, and small typos are tolerated.
+//
+// Parameters:
+// - userId: the ID of the user.
+// - query: the search query.
+// - filter: optional category, type and tag filters and the maximal number of results.
+//
+// Returns:
+// - []SearchResult: the matching items, best matches first.
+// - error: ErrInvalidQuery if the query has no letter or digit, or any search error.
+func (s *PasswordService) Search(userId uint, query string, filter SearchFilter) ([]SearchResult, error) {
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return nil, ErrInvalidQuery
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = DefaultSearchLimit
+	}
+	if limit > MaxSearchLimit {
+		limit = MaxSearchLimit
+	}
+
+	searchModel := s.getSearchModel()
+
+	results, err := searchModel.Search(userId, strings.ToLower(query), terms, models.SearchFilter{
+		CategoryID: filter.CategoryID,
+		Type:       filter.Type,
+		TagIDs:     filter.TagIDs,
+	}, limit)
+
+	resultsList := []SearchResult{}
+
+	for _, result := range results {
+		resultsList = append(resultsList, SearchResult{
+			ID:         result.PasswordID,
+			CategoryID: result.CategoryID,
+			Type:       result.Type,
+			Name:       result.Name,
+			Category:   result.Category,
+			Rank:       result.Rank,
+			Highlight: SearchHighlight{
+				Name:     highlight(result.Name, terms),
+				Category: highlight(result.Category, terms),
+				Tags:     highlight(result.Tags, terms),
+				Hosts:    highlight(result.Hosts, terms),
+				Login:    highlight(result.Login, terms),
+			},
+		})
+	}
+
+	return resultsList, err
+}
+
+// SetSearchLogins enables or disables login search for a given user.
+//
+// When enabled, the logins of server-encrypted entries are copied in plaintext to the search index.
+//
+// Parameters:
+// - userId: the ID of the user.
+// - enabled: whether logins are searchable.
+//
+// Returns:
+// - error: any update error.
+func (s *PasswordService) SetSearchLogins(userId uint, enabled bool) error {
+	searchModel := s.getSearchModel()
+
+	return searchModel.SetSearchLogins(userId, enabled)
+}
+
+// searchTerms splits a query into lowercase words of letters and digits.
+func searchTerms(query string) []string {
+	return strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// highlight HTML-escapes a text and wraps the beginnings of words matching a term in <mark> tags.
+func highlight(text string, terms []string) string {
+	var builder strings.Builder
+
+	runes := []rune(text)
+	for i := 0; i < len(runes); {
+		if !isWordRune(runes[i]) {
+			builder.WriteString(html.EscapeString(string(runes[i])))
+			i++
+			continue
+		}
+
+		end := i
+		for end < len(runes) && isWordRune(runes[end]) {
+			end++
+		}
+
+		word := string(runes[i:end])
+		matched := 0
+		for _, term := range terms {
+			length := len([]rune(term))
+			if length > matched && strings.HasPrefix(strings.ToLower(word), term) {
+				matched = length
+			}
+		}
+
+		if matched > 0 {
+			builder.WriteString("<mark>" + html.EscapeString(string(runes[i:i+matched])) + "</mark>")
+			builder.WriteString(html.EscapeString(string(runes[i+matched : end])))
+		} else {
+			builder.WriteString(html.EscapeString(word))
+		}
+
+		i = end
+	}
+
+	return builder.String()
+}
+
+// isWordRune reports whether a rune belongs to a word.
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,346 @@
+package services
+
+import (
+	"backend/modules/passwords/models"
+	models3 "backend/modules/users/models"
+	"encoding/json"
+	"errors"
+	"gorm.io/gorm"
+	"time"
+)
+
+var (
+	ErrRecipientNotFound = errors.New("no user with this email")
+	ErrShareWithSelf     = errors.New("a password cannot be shared with its owner")
+	ErrShareForbidden    = errors.New("the share does not allow this operation")
+)
+
+type Share struct {
+	ID         uint      `json:"id"`
+	Email      string    `json:"email"`
+	Name       string    `json:"name"`
+	Permission string    `json:"permission"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type ReceivedPassword struct {
+	ID         uint   `json:"id"`
+	Owner      string `json:"owner"`
+	Permission string `json:"permission"`
+	Item
+	Name     string    `json:"name"`
+	Login    string    `json:"login"`
+	Password string    `json:"password"`
+	Totp     string    `json:"totp"`
+	Fields   []Field   `json:"fields"`
+	URIs     []URI     `json:"uris"`
+	SharedAt time.Time `json:"shared_at"`
+}
+
+// snapshot is the content of a password shared with other users, its category, tags and attachments stay private.
+type snapshot struct {
+	Item
+	Name     string  `json:"name"`
+	Login    string  `json:"login"`
+	Password string  `json:"password"`
+	Totp     string  `json:"totp"`
+	Fields   []Field `json:"fields"`
+	URIs     []URI   `json:"uris"`
+}
+
+// getShareModel returns a ShareModel.
+func (s *PasswordService) getShareModel() models.ShareModel {
+	return models.ShareModel{DB: s.DB}
+}
+
+// SharePassword shares a password with another user, or changes the permission of an existing share.
+//
+// Parameters:
+// - id: the ID of the password.
+// - userId: the ID of the owner.
+// - email: the email of the recipient.
+// - permission: models.PermissionView, models.PermissionEdit or models.PermissionViewWithoutReveal.
+//
+// Returns:
+// - Share: the share.
+// - error: gorm.ErrRecordNotFound, ErrRecipientNotFound, ErrShareWithSelf, ErrClientEncrypted or any sharing error.
+func (s *PasswordService) SharePassword(id, userId uint, email, permission string) (Share, error) {
+	content, err := s.getSnapshot(id, userId)
+	if err != nil {
+		return Share{}, err
+	}
+
+	var recipient models3.User
+
+	err = s.DB.Select("id", "name", "email").Where("email = ?", email).First(&recipient).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return Share{}, ErrRecipientNotFound
+	}
+	if err != nil {
+		return Share{}, err
+	}
+
+	if recipient.ID == userId {
+		return Share{}, ErrShareWithSelf
+	}
+
+	shareModel := s.getShareModel()
+
+	share, err := shareModel.Share(id, userId, recipient.ID, permission, content)
+	if err != nil {
+		return Share{}, err
+	}
+
+	share.Recipient = recipient
+
+	return toShare(share), nil
+}
+
+// GetShares returns the users a password is shared with.
+//
+// Parameters:
+// - id: the ID of the password.
+// - userId: the ID of the owner.
+//
+// Returns:
+// - []Share: the shares, oldest first.
+// - error: gorm.ErrRecordNotFound if the password does not belong to the user, or any retrieval error.
+func (s *PasswordService) GetShares(id, userId uint) ([]Share, error) {
+	if err := s.checkPassword(id, userId); err != nil {
+		return nil, err
+	}
+
+	shareModel := s.getShareModel()
+
+	shares, err := shareModel.GetAll(id, userId)
+
+	sharesList := []Share{}
+
+	for _, share := range shares {
+		sharesList = append(sharesList, toShare(share))
+	}
+
+	return sharesList, err
+}
+
+// RevokeShare stops sharing a password with a user and rotates the key of the password.
+//
+// Parameters:
+// - shareId: the ID of the share.
+// - id: the ID of the password.
+// - userId: the ID of the owner.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound if the share does not exist, or any revocation error.
+func (s *PasswordService) RevokeShare(shareId, id, userId uint) error {
+	content, err := s.getSnapshot(id, userId)
+	if err != nil {
+		return err
+	}
+
+	shareModel := s.getShareModel()
+
+	return shareModel.Revoke(shareId, id, userId, content)
+}
+
+// GetReceivedPasswords returns the passwords shared with a user.
+//
+// Secrets are masked in the list, use RevealPassword or CopySecret to read them.
+//
+// Parameters:
+// - userId: the ID of the recipient.
+//
+// Returns:
+// - []ReceivedPassword: the shared passwords, newest shares first.
+// - error: any retrieval or decryption error.
+func (s *PasswordService) GetReceivedPasswords(userId uint) ([]ReceivedPassword, error) {
+	shareModel := s.getShareModel()
+
+	shares, err := shareModel.GetReceived(userId, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	passwordsList := []ReceivedPassword{}
+
+	for _, share := range shares {
+		password, err := toReceivedPassword(share)
+		if err != nil {
+			return nil, err
+		}
+
+		passwordsList = append(passwordsList, password)
+	}
+
+	return passwordsList, nil
+}
+
+// GetReceivedPassword returns a password shared with a user.
+//
+// Secrets are masked, use RevealPassword or CopySecret to read them.
+//
+// Parameters:
+// - id: the ID of the password.
+// - userId: the ID of the recipient.
+//
+// Returns:
+// - ReceivedPassword: the shared password.
+// - error: gorm.ErrRecordNotFound if the password is not shared with the user, or any decryption error.
+func (s *PasswordService) GetReceivedPassword(id, userId uint) (ReceivedPassword, error) {
+	share, err := s.getReceivedShare(id, userId)
+	if err != nil {
+		return ReceivedPassword{}, err
+	}
+
+	return toReceivedPassword(share)
+}
+
+// UpdateReceivedPassword updates a password shared with a user who has the edit permission.
+//
+// The category, tags and rotation interval of the owner are kept, the item values of cards,
+// identities and Wi-Fi networks too since the request only carries login values.
+//
+// Parameters:
+// - id: the ID of the password.
+// - userId: the ID of the recipient.
+// - data: the new values of the password.
+//
+// Returns:
+// - uint: the ID of the updated password.
+// - error: gorm.ErrRecordNotFound, ErrShareForbidden or any update error.
+func (s *PasswordService) UpdateReceivedPassword(id, userId uint, data PasswordData) (uint, error) {
+	share, err := s.getReceivedShare(id, userId)
+	if err != nil {
+		return id, err
+	}
+
+	if share.Permission != models.PermissionEdit {
+		return id, ErrShareForbidden
+	}
+
+	return s.updateForOwner(id, share.UserID, data)
+}
+
+// updateForOwner updates a password on behalf of its owner, keeping the category, tags, rotation interval
+// and item values of the owner.
+func (s *PasswordService) updateForOwner(id, ownerId uint, data PasswordData) (uint, error) {
+	passwordModel := s.getModel()
+
+	password, err := passwordModel.Get(id, ownerId)
+	if err != nil {
+		return id, err
+	}
+
+	if password.Type != models.TypeLogin {
+		data.Item = decodeItem(password.Type, password.Data)
+	}
+
+	data.CategoryID = password.CategoryID
+	data.RotationDays = password.RotationDays
+	data.TagIDs = []uint{}
+	for _, tag := range password.Tags {
+		data.TagIDs = append(data.TagIDs, tag.ID)
+	}
+
+	return s.UpdatePassword(id, ownerId, data)
+}
+
+// refreshShare updates the shared content of a password after a change, passwords that are not shared are skipped.
+func (s *PasswordService) refreshShare(id, userId uint) error {
+	content, err := s.getSnapshot(id, userId)
+	if errors.Is(err, ErrClientEncrypted) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	shareModel := s.getShareModel()
+
+	return shareModel.Refresh(id, userId, content)
+}
+
+// getReceivedShare returns the share of a password with a recipient and its decrypted content.
+func (s *PasswordService) getReceivedShare(id, userId uint) (models.PasswordShare, error) {
+	shareModel := s.getShareModel()
+
+	shares, err := shareModel.GetReceived(userId, id)
+	if err != nil {
+		return models.PasswordShare{}, err
+	}
+
+	if len(shares) == 0 {
+		return models.PasswordShare{}, gorm.ErrRecordNotFound
+	}
+
+	return shares[0], nil
+}
+
+// getSnapshot encodes the shared content of a password of its owner.
+//
+// Client-encrypted passwords cannot be shared, the server cannot decrypt them to seal them for someone else.
+func (s *PasswordService) getSnapshot(id, userId uint) ([]byte, error) {
+	password, err := s.getPassword(id, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if password.Encryption == models.EncryptionClient {
+		return nil, ErrClientEncrypted
+	}
+
+	return json.Marshal(snapshot{
+		Item:     password.Item,
+		Name:     password.Name,
+		Login:    password.Login,
+		Password: password.Password,
+		Totp:     password.Totp,
+		Fields:   password.Fields,
+		URIs:     password.URIs,
+	})
+}
+
+// toShare converts a share model to its response representation.
+func toShare(share models.PasswordShare) Share {
+	return Share{
+		ID:         share.ID,
+		Email:      share.Recipient.Email,
+		Name:       share.Recipient.Name,
+		Permission: share.Permission,
+		CreatedAt:  share.CreatedAt,
+		UpdatedAt:  share.UpdatedAt,
+	}
+}
+
+// toReceivedPassword decodes the shared content of a password and masks its secrets.
+func toReceivedPassword(share models.PasswordShare) (ReceivedPassword, error) {
+	var content snapshot
+	if err := json.Unmarshal(share.Snapshot, &content); err != nil {
+		return ReceivedPassword{}, err
+	}
+
+	password := Password{
+		Item:     content.Item,
+		Password: content.Password,
+		Totp:     content.Totp,
+		Fields:   content.Fields,
+	}
+	password.mask()
+
+	content.Item, content.Password, content.Totp, content.Fields = password.Item, password.Password, password.Totp, password.Fields
+
+	return ReceivedPassword{
+		ID:         share.PasswordID,
+		Owner:      share.Owner.Email,
+		Permission: share.Permission,
+		Item:       content.Item,
+		Name:       content.Name,
+		Login:      content.Login,
+		Password:   content.Password,
+		Totp:       content.Totp,
+		Fields:     content.Fields,
+		URIs:       content.URIs,
+		SharedAt:   share.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,473 @@
+package strength
+
+import (
+	"backend/modules/passwords/services/generator"
+	_ "embed"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+//go:embed passwords.txt
+var passwordsFile string
+
+// dictionary maps lowercase words to their rank, the number of guesses before the word is tried.
+type dictionary struct {
+	flag  string
+	words map[string]int
+}
+
+var (
+	commonPasswords = rankedDictionary(FlagCommonPassword, strings.Fields(passwordsFile))
+	englishWords    = unrankedDictionary(FlagDictionaryWord, generator.Words())
+)
+
+var l33tTable = map[rune]rune{
+	'4': 'a', '@': 'a', '8': 'b', '(': 'c', '{': 'c', '3': 'e', '6': 'g', '9': 'g',
+	'1': 'i', '!': 'i', '|': 'l', '0': 'o', '$': 's', '5': 's', '7': 't', '+': 't', '2': 'z', '%': 'x',
+}
+
+var keyboardRows = []string{"`1234567890-=", "qwertyuiop[]\\", "asdfghjkl;'", "zxcvbnm,./"}
+
+var shiftedKeys = map[rune]rune{
+	'~': '`', '!': '1', '@': '2', '#': '3', '$': '4', '%': '5', '^': '6', '&': '7', '*': '8', '(': '9', ')': '0',
+	'_': '-', '+': '=', '{': '[', '}': ']', '|': '\\', ':': ';', '"': '\'', '<': ',', '>': '.', '?': '/',
+}
+
+type keyPosition struct {
+	row, col int
+}
+
+var keyboard = buildKeyboard()
+
+var dateWithSeparator = regexp.MustCompile(`^(\d{1,4})([\s/\\_.-])(\d{1,2})([\s/\\_.-])(\d{1,4})$`)
+
+// rankedDictionary builds a dictionary where earlier words are more likely.
+func rankedDictionary(flag string, words []string) dictionary {
+	ranked := map[string]int{}
+	for i, word := range words {
+		if _, ok := ranked[word]; !ok {
+			ranked[word] = i + 1
+		}
+	}
+
+	return dictionary{flag: flag, words: ranked}
+}
+
+// unrankedDictionary builds a dictionary without frequency information, every word costs the size of the list.
+func unrankedDictionary(flag string, words []string) dictionary {
+	ranked := map[string]int{}
+	for _, word := range words {
+		ranked[word] = len(words)
+	}
+
+	return dictionary{flag: flag, words: ranked}
+}
+
+// dictionaryMatches finds dictionary words, also reversed and with l33t substitutions.
+func dictionaryMatches(runes []rune, userInputs []string) []match {
+	dictionaries := []dictionary{commonPasswords, englishWords}
+	if inputs := normalizeInputs(userInputs); len(inputs) > 0 {
+		dictionaries = append(dictionaries, rankedDictionary(FlagUserInput, inputs))
+	}
+
+	lower := []rune(strings.ToLower(string(runes)))
+	if len(lower) != len(runes) {
+		lower = runes
+	}
+
+	var matches []match
+
+	for i := 0; i < len(runes); i++ {
+		for j := i + 2; j < len(runes); j++ {
+			word := string(lower[i : j+1])
+			original := runes[i : j+1]
+			unleeted, substitutions := unleet(lower[i : j+1])
+
+			for _, d := range dictionaries {
+				if rank, ok := d.words[word]; ok {
+					matches = append(matches, match{i, j, d.flag, float64(rank) * uppercaseVariations(original)})
+				}
+
+				if reversed := reverse(word); reversed != word {
+					if rank, ok := d.words[reversed]; ok {
+						matches = append(matches, match{i, j, d.flag, float64(rank) * uppercaseVariations(original) * 2})
+					}
+				}
+
+				if substitutions > 0 {
+					if rank, ok := d.words[unleeted]; ok {
+						l33t := math.Pow(2, float64(substitutions))
+						matches = append(matches, match{i, j, d.flag, float64(rank) * uppercaseVariations(original) * l33t})
+					}
+				}
+			}
+		}
+	}
+
+	return matches
+}
+
+// unleet replaces l33t characters with the letters they stand for.
+func unleet(word []rune) (string, int) {
+	substitutions := 0
+	result := make([]rune, len(word))
+
+	for i, r := range word {
+		if letter, ok := l33tTable[r]; ok {
+			result[i] = letter
+			substitutions++
+			continue
+		}
+		result[i] = r
+	}
+
+	return string(result), substitutions
+}
+
+// spatialMatches finds runs of adjacent keys on a qwerty keyboard, like "qwerty" or "zxcvfr".
+func spatialMatches(runes []rune) []match {
+	var matches []match
+
+	startingPositions := float64(len(keyboard))
+	averageDegree := averageKeyboardDegree()
+
+	for i := 0; i < len(runes)-2; i++ {
+		j := i
+		turns, shifted := 0, 0
+		lastDirection := keyPosition{}
+
+		if isShifted(runes[i]) {
+			shifted++
+		}
+
+		for j+1 < len(runes) {
+			direction, ok := adjacent(runes[j], runes[j+1])
+			if !ok {
+				break
+			}
+
+			if direction != lastDirection {
+				turns++
+				lastDirection = direction
+			}
+
+			if isShifted(runes[j+1]) {
+				shifted++
+			}
+			j++
+		}
+
+		length := j - i + 1
+		if length < 3 {
+			continue
+		}
+
+		guesses := 0.0
+		for l := 2; l <= length; l++ {
+			for t := 1; t <= turns && t <= l-1; t++ {
+				guesses += binomial(l-1, t-1) * startingPositions * math.Pow(averageDegree, float64(t))
+			}
+		}
+
+		if shifted > 0 {
+			unshifted := length - shifted
+			if unshifted == 0 {
+				guesses *= 2
+			} else {
+				variations := 0.0
+				for k := 1; k <= shifted && k <= unshifted; k++ {
+					variations += binomial(length, k)
+				}
+				guesses *= variations
+			}
+		}
+
+		matches = append(matches, match{i, j, FlagKeyboardPattern, guesses})
+		i = j - 1
+	}
+
+	return matches
+}
+
+// buildKeyboard returns the positions of the unshifted keys of a qwerty keyboard.
+func buildKeyboard() map[rune]keyPosition {
+	positions := map[rune]keyPosition{}
+	for row, keys := range keyboardRows {
+		for col, key := range keys {
+			positions[key] = keyPosition{row, col}
+		}
+	}
+
+	return positions
+}
+
+// adjacent reports whether two keys are neighbours and returns the direction from a to b.
+//
+// Rows are staggered, so a key touches two keys of the row above (same and next column)
+// and two keys of the row below (previous and same column).
+func adjacent(a, b rune) (keyPosition, bool) {
+	pa, ok := keyboard[unshift(a)]
+	if !ok {
+		return keyPosition{}, false
+	}
+
+	pb, ok := keyboard[unshift(b)]
+	if !ok {
+		return keyPosition{}, false
+	}
+
+	direction := keyPosition{pb.row - pa.row, pb.col - pa.col}
+	switch direction {
+	case keyPosition{0, -1}, keyPosition{0, 1}, keyPosition{-1, 0}, keyPosition{-1, 1}, keyPosition{1, -1}, keyPosition{1, 0}:
+		return direction, true
+	}
+
+	return keyPosition{}, false
+}
+
+// averageKeyboardDegree returns the average number of neighbours of a key.
+func averageKeyboardDegree() float64 {
+	total := 0
+	for a := range keyboard {
+		for b := range keyboard {
+			if _, ok := adjacent(a, b); ok {
+				total++
+			}
+		}
+	}
+
+	return float64(total) / float64(len(keyboard))
+}
+
+// unshift returns the key that produces the character without shift.
+func unshift(r rune) rune {
+	if unshifted, ok := shiftedKeys[r]; ok {
+		return unshifted
+	}
+
+	return unicode.ToLower(r)
+}
+
+// isShifted reports whether the character is typed with shift.
+func isShifted(r rune) bool {
+	_, ok := shiftedKeys[r]
+
+	return ok || unicode.IsUpper(r)
+}
+
+// sequenceMatches finds runs with a constant step, like "abc", "6543" or "aceg".
+func sequenceMatches(runes []rune) []match {
+	var matches []match
+
+	for i := 0; i < len(runes)-2; i++ {
+		delta := runes[i+1] - runes[i]
+		if delta == 0 || delta > 5 || delta < -5 {
+			continue
+		}
+
+		j := i + 1
+		for j+1 < len(runes) && runes[j+1]-runes[j] == delta {
+			j++
+		}
+
+		length := j - i + 1
+		if length < 3 {
+			continue
+		}
+
+		base := 26.0
+		switch {
+		case strings.ContainsRune("aAzZ019", runes[i]):
+			base = 4
+		case unicode.IsDigit(runes[i]):
+			base = 10
+		}
+
+		if delta < 0 {
+			base *= 2
+		}
+
+		matches = append(matches, match{i, j, FlagSequence, base * float64(length)})
+		i = j - 1
+	}
+
+	return matches
+}
+
+// repeatMatches finds repeated characters or blocks, like "aaa" or "abcabc".
+func repeatMatches(runes []rune) []match {
+	var matches []match
+
+	for i := 0; i < len(runes)-2; i++ {
+		bestLength, bestBase, bestCount := 0, 0, 0
+
+		for baseLength := 1; i+baseLength*2 <= len(runes); baseLength++ {
+			base := string(runes[i : i+baseLength])
+			count := 1
+			for i+(count+1)*baseLength <= len(runes) && string(runes[i+count*baseLength:i+(count+1)*baseLength]) == base {
+				count++
+			}
+
+			if count < 2 || (baseLength == 1 && count < 3) {
+				continue
+			}
+
+			if length := baseLength * count; length > bestLength {
+				bestLength, bestBase, bestCount = length, baseLength, count
+			}
+		}
+
+		if bestLength == 0 {
+			continue
+		}
+
+		guesses := guessesOf(runes[i:i+bestBase]) * float64(bestCount)
+		matches = append(matches, match{i, i + bestLength - 1, FlagRepeat, guesses})
+	}
+
+	return matches
+}
+
+// dateMatches finds dates with or without separators, like "13.05.1991", "130591" or "1991".
+func dateMatches(runes []rune) []match {
+	var matches []match
+
+	for i := 0; i < len(runes); i++ {
+		for j := i + 3; j < len(runes) && j-i < 10; j++ {
+			candidate := string(runes[i : j+1])
+
+			if isDigits(candidate) && len(candidate) <= 8 {
+				if year, ok := parseDate(candidate); ok {
+					matches = append(matches, match{i, j, FlagDate, dateGuesses(year, len(candidate) == 4, false)})
+				}
+				continue
+			}
+
+			parts := dateWithSeparator.FindStringSubmatch(candidate)
+			if parts == nil || parts[2] != parts[4] {
+				continue
+			}
+
+			if year, ok := validDate(parts[1], parts[3], parts[5]); ok {
+				matches = append(matches, match{i, j, FlagDate, dateGuesses(year, false, true)})
+			}
+		}
+	}
+
+	return matches
+}
+
+// parseDate tries the common layouts of a date written without separators and returns its year.
+func parseDate(digits string) (int, bool) {
+	if len(digits) == 4 {
+		year, _ := strconv.Atoi(digits)
+		return year, year >= 1900 && year <= 2099
+	}
+
+	for _, yearLength := range []int{4, 2} {
+		if len(digits) <= yearLength+1 {
+			continue
+		}
+
+		// Year at the end, e.g. 13051991 or 1391.
+		if year, ok := splitDayMonth(digits[:len(digits)-yearLength], digits[len(digits)-yearLength:]); ok {
+			return year, true
+		}
+
+		// Year at the beginning, e.g. 19910513.
+		if year, ok := splitDayMonth(digits[yearLength:], digits[:yearLength]); ok {
+			return year, true
+		}
+	}
+
+	return 0, false
+}
+
+// splitDayMonth splits the rest of the date into day and month and validates the whole date.
+func splitDayMonth(rest, year string) (int, bool) {
+	for split := 1; split < len(rest); split++ {
+		if split > 2 || len(rest)-split > 2 {
+			continue
+		}
+
+		if y, ok := validDate(rest[:split], rest[split:], year); ok {
+			return y, true
+		}
+	}
+
+	return 0, false
+}
+
+// validDate checks whether the three parts form a day, month and year in any common order.
+func validDate(first, second, third string) (int, bool) {
+	a, _ := strconv.Atoi(first)
+	b, _ := strconv.Atoi(second)
+	c, _ := strconv.Atoi(third)
+
+	candidates := [][3]int{}
+	if len(third) == 4 || len(third) == 2 {
+		candidates = append(candidates, [3]int{a, b, c}, [3]int{b, a, c})
+	}
+	if len(first) == 4 {
+		candidates = append(candidates, [3]int{c, b, a})
+	}
+
+	for _, candidate := range candidates {
+		day, month, year := candidate[0], candidate[1], candidate[2]
+		if year < 100 {
+			if year > 50 {
+				year += 1900
+			} else {
+				year += 2000
+			}
+		}
+
+		if year < 1900 || year > 2099 || month < 1 || month > 12 || day < 1 || day > 31 {
+			continue
+		}
+
+		return year, true
+	}
+
+	return 0, false
+}
+
+// dateGuesses returns the guesses for a date, the closer to today the sooner it is tried.
+func dateGuesses(year int, yearOnly, separator bool) float64 {
+	yearSpace := math.Max(math.Abs(float64(year-time.Now().Year())), 20)
+	if yearOnly {
+		return yearSpace
+	}
+
+	guesses := yearSpace * 365
+	if separator {
+		guesses *= 4
+	}
+
+	return guesses
+}
+
+// isDigits reports whether the string contains only ASCII digits.
+func isDigits(value string) bool {
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return value != ""
+}
+
+// reverse returns the string with its runes in reverse order.
+func reverse(value string) string {
+	runes := []rune(value)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+
+	return string(runes)
+}
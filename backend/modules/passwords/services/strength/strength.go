@@ -0,0 +1,355 @@
+package strength
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+)
+
+const (
+	FlagCommonPassword  = "common_password"
+	FlagDictionaryWord  = "dictionary_word"
+	FlagUserInput       = "user_input"
+	FlagKeyboardPattern = "keyboard_pattern"
+	FlagSequence        = "sequence"
+	FlagRepeat          = "repeat"
+	FlagDate            = "date"
+	FlagShort           = "short"
+
+	flagBruteforce = "bruteforce"
+)
+
+const (
+	// Only the beginning of very long passwords is analysed, the rest cannot make them weaker.
+	maxLength = 100
+	// Offline attack against a slow hash such as bcrypt.
+	guessesPerSecond = 1e4
+	// Every additional pattern in a decomposition multiplies the attack space at least by this factor.
+	minGuessesBeforeGrowingSequence = 1e4
+	minSubmatchGuessesSingleChar    = 10
+	minSubmatchGuessesMultiChar     = 50
+	bruteforceCardinality           = 10
+	minLength                       = 8
+)
+
+var feedback = map[string]string{
+	FlagCommonPassword:  "This is a very common password",
+	FlagDictionaryWord:  "A word by itself is easy to guess",
+	FlagUserInput:       "Avoid your name, email and the entry name",
+	FlagKeyboardPattern: "Straight rows or short patterns of keys are easy to guess",
+	FlagSequence:        "Sequences like abc or 6543 are easy to guess",
+	FlagRepeat:          "Repeats like aaa or abcabc are easy to guess",
+	FlagDate:            "Dates are often easy to guess",
+	FlagShort:           "Use at least 12 characters",
+}
+
+// Result is a zxcvbn-style strength estimate of a password.
+type Result struct {
+	// Score from 0 (too guessable) to 4 (very unguessable).
+	Score int
+	// Guesses is the estimated number of guesses needed to crack the password.
+	Guesses float64
+	// Entropy is log2 of Guesses.
+	Entropy          float64
+	CrackTimeSeconds float64
+	CrackTimeDisplay string
+	// Flags name the weak patterns found in the password.
+	Flags    []string
+	Feedback []string
+}
+
+// match is a part of the password from rune i to rune j, inclusive, recognised as a pattern.
+type match struct {
+	i, j    int
+	flag    string
+	guesses float64
+}
+
+// Estimate estimates the strength of a password.
+//
+// Parameters:
+// - password: the plaintext password, it is never stored.
+// - userInputs: values the password should not be based on, e.g. the user name, email or entry name.
+//
+// Returns the strength estimate.
+func Estimate(password string, userInputs ...string) Result {
+	runes := []rune(password)
+	if len(runes) > maxLength {
+		runes = runes[:maxLength]
+	}
+
+	guesses, sequence := mostGuessableSequence(runes, userInputs)
+
+	flags := []string{}
+	seen := map[string]bool{}
+	for _, m := range sequence {
+		if m.flag == flagBruteforce || seen[m.flag] {
+			continue
+		}
+		seen[m.flag] = true
+		flags = append(flags, m.flag)
+	}
+
+	if len(runes) < minLength {
+		flags = append(flags, FlagShort)
+	}
+
+	messages := []string{}
+	for _, flag := range flags {
+		messages = append(messages, feedback[flag])
+	}
+
+	seconds := guesses / guessesPerSecond
+
+	return Result{
+		Score:            score(guesses),
+		Guesses:          guesses,
+		Entropy:          math.Log2(guesses),
+		CrackTimeSeconds: seconds,
+		CrackTimeDisplay: displayTime(seconds),
+		Flags:            flags,
+		Feedback:         messages,
+	}
+}
+
+// mostGuessableSequence finds the decomposition of the password into patterns that an attacker
+// would need the fewest guesses for, and returns that number of guesses with the patterns.
+func mostGuessableSequence(runes []rune, userInputs []string) (float64, []match) {
+	n := len(runes)
+	if n == 0 {
+		return 1, nil
+	}
+
+	byEnd := make([][]match, n)
+	for _, m := range findMatches(runes, userInputs) {
+		if m.j-m.i+1 < n {
+			m.guesses = math.Max(m.guesses, minSubmatchGuesses(m))
+		}
+		byEnd[m.j] = append(byEnd[m.j], m)
+	}
+
+	// best[k][l] is the smallest product of guesses covering runes 0..k with exactly l matches.
+	best := make([]map[int]float64, n)
+	back := make([]map[int]match, n)
+
+	for k := 0; k < n; k++ {
+		best[k] = map[int]float64{}
+		back[k] = map[int]match{}
+
+		candidates := byEnd[k]
+		for i := 0; i <= k; i++ {
+			candidates = append(candidates, bruteforceMatch(i, k, n))
+		}
+
+		for _, m := range candidates {
+			if m.i == 0 {
+				update(best[k], back[k], 1, m.guesses, m)
+				continue
+			}
+
+			for l, product := range best[m.i-1] {
+				update(best[k], back[k], l+1, product*m.guesses, m)
+			}
+		}
+	}
+
+	guesses := math.Inf(1)
+	length := 0
+	for l, product := range best[n-1] {
+		g := factorial(l)*product + math.Pow(minGuessesBeforeGrowingSequence, float64(l-1))
+		if g < guesses {
+			guesses, length = g, l
+		}
+	}
+
+	sequence := make([]match, length)
+	for k, l := n-1, length; l > 0; l-- {
+		m := back[k][l]
+		sequence[l-1] = m
+		k = m.i - 1
+	}
+
+	return guesses, sequence
+}
+
+// update stores the candidate if it beats the best product for the same number of matches.
+func update(best map[int]float64, back map[int]match, l int, product float64, m match) {
+	if current, ok := best[l]; ok && current <= product {
+		return
+	}
+
+	best[l] = product
+	back[l] = m
+}
+
+// findMatches runs every matcher over the password.
+func findMatches(runes []rune, userInputs []string) []match {
+	var matches []match
+
+	matches = append(matches, dictionaryMatches(runes, userInputs)...)
+	matches = append(matches, spatialMatches(runes)...)
+	matches = append(matches, sequenceMatches(runes)...)
+	matches = append(matches, repeatMatches(runes)...)
+	matches = append(matches, dateMatches(runes)...)
+
+	return matches
+}
+
+// bruteforceMatch covers runes i..j that match no pattern.
+func bruteforceMatch(i, j, n int) match {
+	length := j - i + 1
+	guesses := math.Pow(bruteforceCardinality, float64(length))
+
+	m := match{i: i, j: j, flag: flagBruteforce, guesses: guesses}
+	if length < n {
+		m.guesses = math.Max(guesses, minSubmatchGuesses(m)+1)
+	}
+
+	return m
+}
+
+// minSubmatchGuesses returns the minimal guesses of a match that does not cover the whole password.
+func minSubmatchGuesses(m match) float64 {
+	if m.i == m.j {
+		return minSubmatchGuessesSingleChar
+	}
+
+	return minSubmatchGuessesMultiChar
+}
+
+// guessesOf returns the guesses needed for a standalone string, used for the base of repeats.
+func guessesOf(runes []rune) float64 {
+	guesses, _ := mostGuessableSequence(runes, nil)
+
+	return guesses
+}
+
+// score converts guesses to the 0-4 score of zxcvbn.
+func score(guesses float64) int {
+	const delta = 5
+
+	switch {
+	case guesses < 1e3+delta:
+		return 0
+	case guesses < 1e6+delta:
+		return 1
+	case guesses < 1e8+delta:
+		return 2
+	case guesses < 1e10+delta:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// displayTime converts seconds to a human readable duration.
+func displayTime(seconds float64) string {
+	const (
+		minute  = 60
+		hour    = minute * 60
+		day     = hour * 24
+		month   = day * 31
+		year    = month * 12
+		century = year * 100
+	)
+
+	units := []struct {
+		name    string
+		seconds float64
+	}{
+		{"year", year},
+		{"month", month},
+		{"day", day},
+		{"hour", hour},
+		{"minute", minute},
+		{"second", 1},
+	}
+
+	switch {
+	case seconds < 1:
+		return "less than a second"
+	case seconds >= century:
+		return "centuries"
+	}
+
+	for _, unit := range units {
+		if seconds >= unit.seconds {
+			count := int(math.Round(seconds / unit.seconds))
+			if count == 1 {
+				return fmt.Sprintf("1 %s", unit.name)
+			}
+			return fmt.Sprintf("%d %ss", count, unit.name)
+		}
+	}
+
+	return "less than a second"
+}
+
+// uppercaseVariations returns how many capitalisations of a word an attacker tries.
+func uppercaseVariations(word []rune) float64 {
+	upper, lower := 0, 0
+	for _, r := range word {
+		switch {
+		case unicode.IsUpper(r):
+			upper++
+		case unicode.IsLower(r):
+			lower++
+		}
+	}
+
+	if upper == 0 {
+		return 1
+	}
+
+	first, last := unicode.IsUpper(word[0]), unicode.IsUpper(word[len(word)-1])
+	if lower == 0 || (upper == 1 && (first || last)) {
+		return 2
+	}
+
+	variations := 0.0
+	for i := 1; i <= upper && i <= lower; i++ {
+		variations += binomial(upper+lower, i)
+	}
+
+	return variations
+}
+
+// binomial returns n choose k.
+func binomial(n, k int) float64 {
+	if k > n {
+		return 0
+	}
+
+	result := 1.0
+	for i := 1; i <= k; i++ {
+		result *= float64(n-k+i) / float64(i)
+	}
+
+	return result
+}
+
+// factorial returns n!.
+func factorial(n int) float64 {
+	result := 1.0
+	for i := 2; i <= n; i++ {
+		result *= float64(i)
+	}
+
+	return result
+}
+
+// normalizeInputs splits user inputs into lowercase words, e.g. an email into its parts.
+func normalizeInputs(userInputs []string) []string {
+	var words []string
+
+	for _, input := range userInputs {
+		input = strings.ToLower(input)
+		words = append(words, input)
+		words = append(words, strings.FieldsFunc(input, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})...)
+	}
+
+	return words
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	services4 "backend/modules/audit/services"
+	"backend/modules/passwords/models"
+	"backend/modules/passwords/services/totp"
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidTotp     = totp.ErrInvalidURI
+	ErrTotpNotSet      = errors.New("password has no TOTP secret")
+	ErrClientEncrypted = errors.New("password is encrypted by the client")
+)
+
+type TotpCode struct {
+	Code      string `json:"code"`
+	Remaining int    `json:"remaining"`
+	Period    int    `json:"period"`
+	Digits    int    `json:"digits"`
+	Algorithm string `json:"algorithm"`
+	Issuer    string `json:"issuer"`
+	Account   string `json:"account"`
+}
+
+// GetTotpCode returns the current one-time code of a password and records it as a copy of SecretTotp
+// in its access log.
+//
+// Parameters:
+// - id: the ID of the password.
+// - userId: the ID of the user.
+// - client: the token, IP address and user agent of the request, for the access log.
+//
+// Returns:
+// - TotpCode: the current code and the seconds until it changes.
+// - error: gorm.ErrRecordNotFound, ErrTotpNotSet, ErrClientEncrypted, ErrInvalidTotp or any logging error.
+func (s *PasswordService) GetTotpCode(id, userId uint, client services4.Client) (TotpCode, error) {
+	passwordModel := s.getModel()
+
+	password, err := passwordModel.Get(id, userId)
+	if err != nil {
+		return TotpCode{}, err
+	}
+
+	if password.Encryption == models.EncryptionClient {
+		return TotpCode{}, ErrClientEncrypted
+	}
+
+	if password.Totp == "" {
+		return TotpCode{}, ErrTotpNotSet
+	}
+
+	key, err := totp.Parse(password.Totp)
+	if err != nil {
+		return TotpCode{}, err
+	}
+
+	code := key.Generate(time.Now())
+
+	if err := s.recordAccess(id, userId, models.AccessCopy, SecretTotp, client); err != nil {
+		return TotpCode{}, err
+	}
+
+	return TotpCode{
+		Code:      code.Code,
+		Remaining: code.Remaining,
+		Period:    key.Period,
+		Digits:    key.Digits,
+		Algorithm: key.Algorithm,
+		Issuer:    key.Issuer,
+		Account:   key.Account,
+	}, nil
+}
@@ -0,0 +1,187 @@
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	AlgorithmSHA1   = "SHA1"
+	AlgorithmSHA256 = "SHA256"
+	AlgorithmSHA512 = "SHA512"
+
+	DefaultDigits = 6
+	DefaultPeriod = 30
+)
+
+var ErrInvalidURI = errors.New("invalid TOTP URI")
+
+var (
+	ErrInvalidScheme    = fmt.Errorf("%w: expected otpauth://totp/", ErrInvalidURI)
+	ErrInvalidSecret    = fmt.Errorf("%w: secret must be non-empty base32", ErrInvalidURI)
+	ErrInvalidAlgorithm = fmt.Errorf("%w: algorithm must be SHA1, SHA256 or SHA512", ErrInvalidURI)
+	ErrInvalidDigits    = fmt.Errorf("%w: digits must be 6 or 8", ErrInvalidURI)
+	ErrInvalidPeriod    = fmt.Errorf("%w: period must be between 1 and 300 seconds", ErrInvalidURI)
+)
+
+// Key is a parsed otpauth://totp/ URI.
+type Key struct {
+	Issuer    string
+	Account   string
+	Secret    []byte
+	Algorithm string
+	Digits    int
+	Period    int
+}
+
+// Code is a one-time code valid at a point in time.
+type Code struct {
+	Code      string
+	Remaining int
+}
+
+// Parse parses an otpauth://totp/ URI as produced by QR codes of most services.
+//
+// A bare base32 secret entered manually is accepted as well and gets the default parameters.
+//
+// Parameters:
+// - uri: the URI or secret.
+//
+// Returns:
+// - Key: the parsed key.
+// - error: an error wrapping ErrInvalidURI if the value is not a valid TOTP key.
+func Parse(uri string) (Key, error) {
+	uri = strings.TrimSpace(uri)
+
+	if !strings.Contains(uri, "://") {
+		secret, err := decodeSecret(uri)
+		if err != nil {
+			return Key{}, err
+		}
+
+		return Key{Secret: secret, Algorithm: AlgorithmSHA1, Digits: DefaultDigits, Period: DefaultPeriod}, nil
+	}
+
+	parsed, err := url.Parse(uri)
+	if err != nil || !strings.EqualFold(parsed.Scheme, "otpauth") || !strings.EqualFold(parsed.Host, "totp") {
+		return Key{}, ErrInvalidScheme
+	}
+
+	query := parsed.Query()
+
+	secret, err := decodeSecret(query.Get("secret"))
+	if err != nil {
+		return Key{}, err
+	}
+
+	key := Key{
+		Issuer:    query.Get("issuer"),
+		Secret:    secret,
+		Algorithm: AlgorithmSHA1,
+		Digits:    DefaultDigits,
+		Period:    DefaultPeriod,
+	}
+
+	label := strings.TrimPrefix(parsed.Path, "/")
+	if issuer, account, found := strings.Cut(label, ":"); found {
+		key.Account = strings.TrimSpace(account)
+		if key.Issuer == "" {
+			key.Issuer = issuer
+		}
+	} else {
+		key.Account = label
+	}
+
+	if algorithm := query.Get("algorithm"); algorithm != "" {
+		key.Algorithm = strings.ToUpper(algorithm)
+		if newHash(key.Algorithm) == nil {
+			return Key{}, ErrInvalidAlgorithm
+		}
+	}
+
+	if digits := query.Get("digits"); digits != "" {
+		key.Digits, err = strconv.Atoi(digits)
+		if err != nil || (key.Digits != 6 && key.Digits != 8) {
+			return Key{}, ErrInvalidDigits
+		}
+	}
+
+	if period := query.Get("period"); period != "" {
+		key.Period, err = strconv.Atoi(period)
+		if err != nil || key.Period < 1 || key.Period > 300 {
+			return Key{}, ErrInvalidPeriod
+		}
+	}
+
+	return key, nil
+}
+
+// Generate returns the RFC 6238 code of the key at the given time.
+//
+// Parameters:
+// - at: the time of the code.
+//
+// Returns the code and the seconds until the next one.
+func (k Key) Generate(at time.Time) Code {
+	unix := at.Unix()
+	counter := uint64(unix / int64(k.Period))
+
+	var message [8]byte
+	binary.BigEndian.PutUint64(message[:], counter)
+
+	mac := hmac.New(newHash(k.Algorithm), k.Secret)
+	mac.Write(message[:])
+	sum := mac.Sum(nil)
+
+	// Dynamic truncation, RFC 4226 section 5.3.
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	modulo := uint32(1)
+	for i := 0; i < k.Digits; i++ {
+		modulo *= 10
+	}
+
+	return Code{
+		Code:      fmt.Sprintf("%0*d", k.Digits, value%modulo),
+		Remaining: k.Period - int(unix%int64(k.Period)),
+	}
+}
+
+// newHash returns the hash constructor of an algorithm name, nil when it is not supported.
+func newHash(algorithm string) func() hash.Hash {
+	switch algorithm {
+	case AlgorithmSHA1:
+		return sha1.New
+	case AlgorithmSHA256:
+		return sha256.New
+	case AlgorithmSHA512:
+		return sha512.New
+	}
+
+	return nil
+}
+
+// decodeSecret decodes a base32 secret, ignoring case, spaces and padding.
+func decodeSecret(secret string) ([]byte, error) {
+	secret = strings.ToUpper(strings.ReplaceAll(secret, " ", ""))
+	secret = strings.TrimRight(secret, "=")
+
+	decoded, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	if err != nil || len(decoded) == 0 {
+		return nil, ErrInvalidSecret
+	}
+
+	return decoded, nil
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"time"
+)
+
+type TrashedPassword struct {
+	ID         uint      `json:"id"`
+	CategoryID *uint     `json:"category_id"`
+	Type       string    `json:"type"`
+	Name       string    `json:"name"`
+	DeletedAt  time.Time `json:"deleted_at"`
+}
+
+// GetTrashedPasswords returns the deleted passwords of a given user.
+//
+// Parameters:
+// - userId: the ID of the user.
+//
+// Returns:
+// - []TrashedPassword: the deleted passwords without their secrets, most recently deleted first.
+// - error: any error that occurred during the retrieval process.
+func (s *PasswordService) GetTrashedPasswords(userId uint) ([]TrashedPassword, error) {
+	passwordModel := s.getModel()
+
+	passwords, err := passwordModel.GetTrashed(userId)
+
+	passwordsList := []TrashedPassword{}
+
+	for _, password := range passwords {
+		passwordsList = append(passwordsList, TrashedPassword{
+			ID:         password.ID,
+			CategoryID: password.CategoryID,
+			Type:       password.Type,
+			Name:       password.Name,
+			DeletedAt:  password.DeletedAt.Time,
+		})
+	}
+
+	return passwordsList, err
+}
+
+// RestorePassword restores a deleted password of a given user, and its category if it was deleted too.
+//
+// Parameters:
+// - id: the ID of the password.
+// - userId: the ID of the user.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound if the password is not in the trash of the user, or any update error.
+func (s *PasswordService) RestorePassword(id, userId uint) error {
+	passwordModel := s.getModel()
+
+	return passwordModel.Restore(id, userId)
+}
+
+// PurgePassword permanently deletes a password from the trash of a given user, together with its attachments.
+//
+// Parameters:
+// - id: the ID of the password.
+// - userId: the ID of the user.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound if the password is not in the trash of the user, or any deletion error.
+func (s *PasswordService) PurgePassword(id, userId uint) error {
+	passwordModel := s.getModel()
+
+	keys, err := passwordModel.Purge(id, userId)
+	if err != nil {
+		return err
+	}
+
+	s.removeAttachments(keys...)
+
+	return nil
+}
@@ -0,0 +1,285 @@
+package services
+
+import (
+	"backend/modules/passwords/models"
+	"errors"
+	"fmt"
+	"golang.org/x/net/publicsuffix"
+	"net"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+const MaxURIs = 20
+
+// Match qualities, a higher quality is a more specific match.
+//
+// A starts_with match requires the exact scheme and host, so it ranks as a host match.
+const (
+	qualityRegex      = 1
+	qualityBaseDomain = 2
+	qualityHost       = 3
+)
+
+var ErrInvalidURI = errors.New("invalid URI")
+
+type URI struct {
+	URI string `json:"uri"`
+	// Match is the strategy used to compare the URI with a page: domain, host, starts_with, regex or never.
+	Match string `json:"match"`
+}
+
+type MatchResult struct {
+	ID         uint   `json:"id"`
+	CategoryID *uint  `json:"category_id"`
+	Name       string `json:"name"`
+	Login      string `json:"login"`
+	// URI is the URI of the password that matched the page best.
+	URI        string     `json:"uri"`
+	Match      string     `json:"match"`
+	Quality    int        `json:"quality"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+}
+
+// Match returns the logins of a given user that can be filled in on a page.
+//
+// The URIs of a login are compared with the page using their match strategy: the registrable domain
+// from the public suffix list, the exact host, the exact scheme and host with a prefix of the path,
+// or a regular expression.
+// Client-encrypted logins are skipped since the server cannot read their URIs.
+//
+// Parameters:
+// - userId: the ID of the user.
+// - pageURL: the absolute URL of the page.
+//
+// Returns:
+// - []MatchResult: the matching logins, most specific match first, then most recently used.
+// - error: ErrInvalidURI if the page URL has no host, or any retrieval error.
+func (s *PasswordService) Match(userId uint, pageURL string) ([]MatchResult, error) {
+	page, err := url.Parse(pageURL)
+	if err != nil || page.Hostname() == "" {
+		return nil, fmt.Errorf("%w: expected an absolute page URL", ErrInvalidURI)
+	}
+
+	passwordModel := s.getModel()
+
+	passwords, err := passwordModel.GetWithURIs(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []MatchResult{}
+
+	for _, password := range passwords {
+		result := MatchResult{
+			ID:         password.ID,
+			CategoryID: password.CategoryID,
+			Name:       password.Name,
+			Login:      password.Login,
+			LastUsedAt: password.LastUsedAt,
+		}
+
+		for _, uri := range password.URIs {
+			if quality := matchURI(page, pageURL, uri); quality > result.Quality {
+				result.URI, result.Match, result.Quality = uri.URI, uri.Match, quality
+			}
+		}
+
+		if result.Quality > 0 {
+			results = append(results, result)
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+
+		if a.Quality != b.Quality {
+			return a.Quality > b.Quality
+		}
+
+		if (a.LastUsedAt == nil) != (b.LastUsedAt == nil) {
+			return a.LastUsedAt != nil
+		}
+
+		if a.LastUsedAt != nil && !a.LastUsedAt.Equal(*b.LastUsedAt) {
+			return a.LastUsedAt.After(*b.LastUsedAt)
+		}
+
+		return a.Name < b.Name
+	})
+
+	return results, nil
+}
+
+// MarkUsed records that a password of a given user was used, which ranks it higher in Match.
+//
+// Parameters:
+// - id: the ID of the password.
+// - userId: the ID of the user.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound if the password does not belong to the user, or any update error.
+func (s *PasswordService) MarkUsed(id, userId uint) error {
+	passwordModel := s.getModel()
+
+	return passwordModel.MarkUsed(id, userId)
+}
+
+// validateURIs checks the URIs of a password and sets the default match strategy.
+//
+// URIs of client-encrypted entries are ciphertext, so only their strategies are checked then.
+func validateURIs(uris []URI, clientEncrypted bool) error {
+	if len(uris) > MaxURIs {
+		return fmt.Errorf("%w: at most %d URIs are allowed", ErrInvalidURI, MaxURIs)
+	}
+
+	for i := range uris {
+		if uris[i].Match == "" {
+			uris[i].Match = models.MatchDomain
+		}
+
+		if !slices.Contains(models.MatchTypes, uris[i].Match) {
+			return fmt.Errorf("%w: unknown match strategy %q", ErrInvalidURI, uris[i].Match)
+		}
+
+		if uris[i].URI == "" {
+			return fmt.Errorf("%w: URI %d is empty", ErrInvalidURI, i)
+		}
+
+		if clientEncrypted {
+			continue
+		}
+
+		switch uris[i].Match {
+		case models.MatchRegex:
+			if _, err := regexp.Compile(uris[i].URI); err != nil {
+				return fmt.Errorf("%w: %q is not a valid regular expression", ErrInvalidURI, uris[i].URI)
+			}
+		case models.MatchDomain, models.MatchHost, models.MatchStartsWith:
+			if uriHost(uris[i].URI) == "" {
+				return fmt.Errorf("%w: %q has no host", ErrInvalidURI, uris[i].URI)
+			}
+		}
+	}
+
+	return nil
+}
+
+// matchURI returns the quality of the match of a URI with a page, 0 when it does not match.
+func matchURI(page *url.URL, pageURL string, uri models.PasswordURI) int {
+	switch uri.Match {
+	case models.MatchStartsWith:
+		// The scheme and host are compared exactly, so https://bank.com does not match https://bank.com.evil.com.
+		prefix := parseURI(uri.URI)
+		if prefix == nil || prefix.Host == "" {
+			return 0
+		}
+
+		if strings.EqualFold(prefix.Scheme, page.Scheme) && strings.EqualFold(prefix.Host, page.Host) &&
+			strings.HasPrefix(page.RequestURI(), prefix.RequestURI()) {
+			return qualityHost
+		}
+	case models.MatchRegex:
+		pattern, err := regexp.Compile(uri.URI)
+		if err == nil && pattern.MatchString(pageURL) {
+			return qualityRegex
+		}
+	case models.MatchHost:
+		if strings.EqualFold(uriHost(uri.URI), page.Host) {
+			return qualityHost
+		}
+	case models.MatchDomain:
+		host := hostname(uri.URI)
+		if host == "" {
+			return 0
+		}
+
+		if strings.EqualFold(host, page.Hostname()) {
+			return qualityHost
+		}
+
+		if baseDomain(host) == baseDomain(page.Hostname()) {
+			return qualityBaseDomain
+		}
+	}
+
+	return 0
+}
+
+// parseURI parses a URI, the https scheme is assumed when it has none, as in "example.com/login".
+func parseURI(uri string) *url.URL {
+	if !strings.Contains(uri, "://") {
+		uri = "https://" + uri
+	}
+
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return nil
+	}
+
+	return parsed
+}
+
+// uriHost returns the host of a URI with its port, if any.
+func uriHost(uri string) string {
+	parsed := parseURI(uri)
+	if parsed == nil {
+		return ""
+	}
+
+	return parsed.Host
+}
+
+// hostname returns the host of a URI without its port.
+func hostname(uri string) string {
+	parsed := parseURI(uri)
+	if parsed == nil {
+		return ""
+	}
+
+	return parsed.Hostname()
+}
+
+// baseDomain returns the registrable domain of a host, such as example.co.uk for login.example.co.uk.
+//
+// IP addresses and hosts without a public suffix, like localhost, are returned as they are.
+func baseDomain(host string) string {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+
+	if net.ParseIP(host) != nil {
+		return host
+	}
+
+	domain, err := publicsuffix.EffectiveTLDPlusOne(host)
+	if err != nil {
+		return host
+	}
+
+	return domain
+}
+
+// toURIModels converts URIs to models, the position is the index in the list.
+func toURIModels(uris []URI) []models.PasswordURI {
+	uriModels := make([]models.PasswordURI, len(uris))
+
+	for i, uri := range uris {
+		uriModels[i] = models.PasswordURI{Position: i, URI: uri.URI, Match: uri.Match}
+	}
+
+	return uriModels
+}
+
+// toURIs converts URI models to their response representation.
+func toURIs(uriModels []models.PasswordURI) []URI {
+	uris := make([]URI, len(uriModels))
+
+	for i, uri := range uriModels {
+		uris[i] = URI{URI: uri.URI, Match: uri.Match}
+	}
+
+	return uris
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"backend/modules/passwords/models"
+	"net/url"
+	"testing"
+)
+
+func TestMatchURI(t *testing.T) {
+	tests := []struct {
+		name    string
+		page    string
+		uri     string
+		match   string
+		quality int
+	}{
+		{"starts with same host", "https://bank.com/login", "https://bank.com", models.MatchStartsWith, qualityHost},
+		{"starts with path prefix", "https://bank.com/account/login?next=1", "https://bank.com/account", models.MatchStartsWith, qualityHost},
+		{"starts with default scheme", "https://bank.com/login", "bank.com/login", models.MatchStartsWith, qualityHost},
+		{"starts with host case", "https://Bank.com/login", "https://bank.com/", models.MatchStartsWith, qualityHost},
+		{"starts with other path", "https://bank.com/help", "https://bank.com/account", models.MatchStartsWith, 0},
+		{"starts with host suffix", "https://bank.com.evil.com/", "https://bank.com", models.MatchStartsWith, 0},
+		{"starts with userinfo", "https://bank.com@evil.com/", "https://bank.com", models.MatchStartsWith, 0},
+		{"starts with other scheme", "http://bank.com/login", "https://bank.com", models.MatchStartsWith, 0},
+		{"starts with other port", "https://bank.com:8443/login", "https://bank.com", models.MatchStartsWith, 0},
+		{"starts with subdomain", "https://login.bank.com/", "https://bank.com", models.MatchStartsWith, 0},
+		{"host", "https://bank.com/login", "https://bank.com", models.MatchHost, qualityHost},
+		{"host with port", "https://bank.com:8443/", "bank.com:8443", models.MatchHost, qualityHost},
+		{"host other port", "https://bank.com:8443/", "bank.com", models.MatchHost, 0},
+		{"host subdomain", "https://login.bank.com/", "bank.com", models.MatchHost, 0},
+		{"domain same host", "https://bank.com/", "bank.com", models.MatchDomain, qualityHost},
+		{"domain subdomain", "https://login.bank.co.uk/", "www.bank.co.uk", models.MatchDomain, qualityBaseDomain},
+		{"domain public suffix", "https://other.co.uk/", "bank.co.uk", models.MatchDomain, 0},
+		{"domain host suffix", "https://bank.com.evil.com/", "bank.com", models.MatchDomain, 0},
+		{"regex", "https://bank.com/login", `^https://bank\.com/`, models.MatchRegex, qualityRegex},
+		{"regex no match", "https://evil.com/login", `^https://bank\.com/`, models.MatchRegex, 0},
+		{"never", "https://bank.com/", "bank.com", models.MatchNever, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			page, err := url.Parse(test.page)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			quality := matchURI(page, test.page, models.PasswordURI{URI: test.uri, Match: test.match})
+			if quality != test.quality {
+				t.Errorf("matchURI(%q, %q %s) = %d, want %d", test.page, test.uri, test.match, quality, test.quality)
+			}
+		})
+	}
+}
+
+func TestStartsWithRanksBelowOrEqualHost(t *testing.T) {
+	page, _ := url.Parse("https://bank.com/login")
+
+	startsWith := matchURI(page, page.String(), models.PasswordURI{URI: "https://bank.com/login", Match: models.MatchStartsWith})
+	host := matchURI(page, page.String(), models.PasswordURI{URI: "bank.com", Match: models.MatchHost})
+
+	if startsWith > host {
+		t.Errorf("starts_with quality %d ranks above host quality %d", startsWith, host)
+	}
+}
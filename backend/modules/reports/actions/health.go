@@ -0,0 +1,118 @@
+package actions
+
+import (
+	"backend/modules/reports/services"
+	"backend/modules/users/models"
+	services2 "backend/services"
+	"bytes"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"time"
+)
+
+type HealthReportRequest struct {
+	// Entries with a strength score up to max_score are weak, 1 by default.
+	MaxScore *int `form:"max_score" binding:"omitempty,min=0,max=4"`
+	// Entries not updated for more than max_age_days days are old, 365 by default.
+	MaxAgeDays int `form:"max_age_days" binding:"omitempty,min=1,max=36500"`
+}
+
+// GetHealthReport retrieves the security report of the entries of the logged-in user.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Get health report
+// @Description Reports reused, weak, old and breached passwords of the logged-in user with an overall score.
+// @Description Reuse is detected with keyed fingerprints, so secrets are neither compared in plaintext nor returned.
+// @Description Client-encrypted entries cannot be analysed and are only counted.
+// @Tags Reports
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   max_score  query    int  false  "Highest strength score reported as weak, 1 by default"
+// @Param   max_age_days  query    int  false  "Days after which an entry is reported as old, 365 by default"
+// @Success 200 {object} services.HealthReport
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /reports/health [get]
+func GetHealthReport(c *gin.Context) {
+	report, ok := healthReport(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// ExportHealthReport exports the security report of the entries of the logged-in user as CSV.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Export health report
+// @Description Exports the health report as CSV with one row per issue of an entry.
+// @Tags Reports
+// @Accept  json
+// @Produce  text/csv
+// @Security BearerAuth
+// @Param   max_score  query    int  false  "Highest strength score reported as weak, 1 by default"
+// @Param   max_age_days  query    int  false  "Days after which an entry is reported as old, 365 by default"
+// @Success 200 {file} file
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /reports/health/export [get]
+func ExportHealthReport(c *gin.Context) {
+	report, ok := healthReport(c)
+	if !ok {
+		return
+	}
+
+	reportService, _ := getServiceAndUser(c)
+
+	var buffer bytes.Buffer
+	if err := reportService.WriteHealthCSV(&buffer, report); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	filename := "health-report-" + time.Now().UTC().Format("2006-01-02") + ".csv"
+
+	c.Header("Content-Disposition", "attachment; filename="+filename)
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", buffer.Bytes())
+}
+
+// healthReport binds the report options and builds the health report, aborting the request on failure.
+func healthReport(c *gin.Context) (services.HealthReport, bool) {
+	var request HealthReportRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return services.HealthReport{}, false
+	}
+
+	options := services.HealthOptions{MaxScore: services.DefaultMaxScore, MaxAgeDays: services.DefaultMaxAgeDays}
+	if request.MaxScore != nil {
+		options.MaxScore = *request.MaxScore
+	}
+	if request.MaxAgeDays > 0 {
+		options.MaxAgeDays = request.MaxAgeDays
+	}
+
+	reportService, user := getServiceAndUser(c)
+
+	report, err := reportService.GetHealth(user.User.ID, options)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, services2.ErrorResponse{Error: err.Error()})
+		return services.HealthReport{}, false
+	}
+
+	return report, true
+}
+
+// getServiceAndUser returns the report service and user token.
+//
+// It takes a Gin context as a parameter.
+// It returns a ReportService and a Token.
+func getServiceAndUser(c *gin.Context) (services.ReportService, models.Token) {
+	service := services.ReportService{DB: services2.GetDBConnection()}
+	user := services2.GetUserFromContext(c)
+
+	return service, user
+}
@@ -0,0 +1,257 @@
+package services
+
+import (
+	"backend/modules/passwords/models"
+	"encoding/csv"
+	"gorm.io/gorm"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultMaxScore   = 1
+	DefaultMaxAgeDays = 365
+)
+
+const (
+	IssueReused   = "reused"
+	IssueWeak     = "weak"
+	IssueOld      = "old"
+	IssueBreached = "breached"
+)
+
+type ReportService struct {
+	DB *gorm.DB
+}
+
+type HealthOptions struct {
+	// Entries with a strength score up to MaxScore are weak.
+	MaxScore int
+	// Entries not updated for more than MaxAgeDays days are old.
+	MaxAgeDays int
+}
+
+type HealthEntry struct {
+	ID            uint      `json:"id"`
+	CategoryID    *uint     `json:"category_id"`
+	Type          string    `json:"type"`
+	Name          string    `json:"name"`
+	StrengthScore *int      `json:"strength_score"`
+	Breached      int       `json:"breached"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	AgeDays       int       `json:"age_days"`
+}
+
+type ReusedGroup struct {
+	Count   int           `json:"count"`
+	Entries []HealthEntry `json:"entries"`
+}
+
+type HealthReport struct {
+	// Score is the percentage of checked entries without any issue, 100 when nothing could be checked.
+	Score int `json:"score"`
+	Total int `json:"total"`
+	// Checked counts the server-encrypted entries with a password or key.
+	Checked int `json:"checked"`
+	Healthy int `json:"healthy"`
+	// Unchecked counts the client-encrypted entries, the server cannot analyse their secrets.
+	Unchecked  int           `json:"unchecked"`
+	MaxScore   int           `json:"max_score"`
+	MaxAgeDays int           `json:"max_age_days"`
+	Reused     []ReusedGroup `json:"reused"`
+	Weak       []HealthEntry `json:"weak"`
+	Old        []HealthEntry `json:"old"`
+	Breached   []HealthEntry `json:"breached"`
+}
+
+// getPasswordModel returns a PasswordModel.
+//
+// No parameters.
+// Returns a models.PasswordModel.
+func (s *ReportService) getPasswordModel() models.PasswordModel {
+	return models.PasswordModel{DB: s.DB}
+}
+
+// GetHealth builds the security report of the entries of a given user.
+//
+// Reused passwords are found by comparing the keyed fingerprints of the secrets, so no plaintext is compared
+// or decrypted. Weak and breached entries use the strength and breach results stored when the secret was saved,
+// and old entries are the ones whose UpdatedAt is older than the age limit.
+// Client-encrypted entries are counted as unchecked.
+//
+// Parameters:
+// - userId: the ID of the user.
+// - options: the weak score and age limits.
+//
+// Returns:
+// - HealthReport: the overall score and the entries of each issue.
+// - error: any error that occurred during the retrieval process.
+func (s *ReportService) GetHealth(userId uint, options HealthOptions) (HealthReport, error) {
+	passwordModel := s.getPasswordModel()
+
+	passwords, err := passwordModel.GetHealthMetadata(userId)
+	if err != nil {
+		return HealthReport{}, err
+	}
+
+	report := HealthReport{
+		Total:      len(passwords),
+		MaxScore:   options.MaxScore,
+		MaxAgeDays: options.MaxAgeDays,
+		Reused:     []ReusedGroup{},
+		Weak:       []HealthEntry{},
+		Old:        []HealthEntry{},
+		Breached:   []HealthEntry{},
+	}
+
+	now := time.Now()
+	unhealthy := map[uint]bool{}
+	groups := map[string][]HealthEntry{}
+	var fingerprints []string
+
+	for _, password := range passwords {
+		if password.Encryption == models.EncryptionClient {
+			report.Unchecked++
+			continue
+		}
+
+		if password.Fingerprint == nil || *password.Fingerprint == "" {
+			continue
+		}
+
+		report.Checked++
+
+		entry := toHealthEntry(password, now)
+
+		if _, ok := groups[*password.Fingerprint]; !ok {
+			fingerprints = append(fingerprints, *password.Fingerprint)
+		}
+		groups[*password.Fingerprint] = append(groups[*password.Fingerprint], entry)
+
+		if entry.StrengthScore != nil && *entry.StrengthScore <= options.MaxScore {
+			report.Weak = append(report.Weak, entry)
+			unhealthy[entry.ID] = true
+		}
+
+		if entry.AgeDays > options.MaxAgeDays {
+			report.Old = append(report.Old, entry)
+			unhealthy[entry.ID] = true
+		}
+
+		if entry.Breached > 0 {
+			report.Breached = append(report.Breached, entry)
+			unhealthy[entry.ID] = true
+		}
+	}
+
+	for _, fingerprint := range fingerprints {
+		entries := groups[fingerprint]
+		if len(entries) < 2 {
+			continue
+		}
+
+		report.Reused = append(report.Reused, ReusedGroup{Count: len(entries), Entries: entries})
+		for _, entry := range entries {
+			unhealthy[entry.ID] = true
+		}
+	}
+
+	report.Healthy = report.Checked - len(unhealthy)
+	report.Score = 100
+	if report.Checked > 0 {
+		report.Score = int(math.Round(float64(report.Healthy) * 100 / float64(report.Checked)))
+	}
+
+	return report, nil
+}
+
+// WriteHealthCSV writes a health report as CSV with one row per issue of an entry.
+//
+// Reused entries of the same group share the group number in the detail column.
+// Cells starting with a formula character are prefixed with a quote so spreadsheets do not evaluate them.
+//
+// Parameters:
+// - w: the writer receiving the CSV.
+// - report: the health report.
+//
+// Returns:
+// - error: any write error.
+func (s *ReportService) WriteHealthCSV(w io.Writer, report HealthReport) error {
+	writer := csv.NewWriter(w)
+
+	rows := [][]string{{"issue", "id", "name", "type", "category_id", "detail", "updated_at"}}
+
+	for i, group := range report.Reused {
+		for _, entry := range group.Entries {
+			detail := "group " + strconv.Itoa(i+1) + ", " + strconv.Itoa(group.Count) + " entries"
+			rows = append(rows, healthRow(IssueReused, entry, detail))
+		}
+	}
+
+	for _, entry := range report.Weak {
+		rows = append(rows, healthRow(IssueWeak, entry, "score "+strconv.Itoa(*entry.StrengthScore)))
+	}
+
+	for _, entry := range report.Old {
+		rows = append(rows, healthRow(IssueOld, entry, strconv.Itoa(entry.AgeDays)+" days"))
+	}
+
+	for _, entry := range report.Breached {
+		rows = append(rows, healthRow(IssueBreached, entry, strconv.Itoa(entry.Breached)+" breaches"))
+	}
+
+	if err := writer.WriteAll(rows); err != nil {
+		return err
+	}
+
+	return writer.Error()
+}
+
+// toHealthEntry converts a password model to a HealthEntry.
+func toHealthEntry(password models.Password, now time.Time) HealthEntry {
+	entry := HealthEntry{
+		ID:            password.ID,
+		CategoryID:    password.CategoryID,
+		Type:          password.Type,
+		Name:          password.Name,
+		StrengthScore: password.StrengthScore,
+		UpdatedAt:     password.UpdatedAt,
+		AgeDays:       int(now.Sub(password.UpdatedAt).Hours() / 24),
+	}
+
+	if password.Breached != nil {
+		entry.Breached = *password.Breached
+	}
+
+	return entry
+}
+
+// healthRow builds the CSV row of an issue of an entry.
+func healthRow(issue string, entry HealthEntry, detail string) []string {
+	categoryId := ""
+	if entry.CategoryID != nil {
+		categoryId = strconv.FormatUint(uint64(*entry.CategoryID), 10)
+	}
+
+	return []string{
+		issue,
+		strconv.FormatUint(uint64(entry.ID), 10),
+		escapeCell(entry.Name),
+		entry.Type,
+		categoryId,
+		detail,
+		entry.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+// escapeCell prefixes a cell starting with a formula character with a quote.
+func escapeCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+
+	return value
+}
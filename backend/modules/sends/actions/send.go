@@ -0,0 +1,265 @@
+package actions
+
+import (
+	"backend/modules/sends/services"
+	"backend/modules/users/models"
+	services2 "backend/services"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type CreateSendRequest struct {
+	Type string `json:"type" binding:"required,oneof=text file"`
+	// Name of the send encrypted by the client with the key of the link.
+	Name string `json:"name" binding:"required,max=1024"`
+	// Text of text sends encrypted by the client with the key of the link.
+	Text string `json:"text" binding:"max=1000000"`
+	// Size of the encrypted file of file sends, uploaded with PUT /send/upload/{id}.
+	Size      int64     `json:"size" binding:"min=0"`
+	MaxViews  int       `json:"max_views" binding:"required,min=1,max=1000"`
+	ExpiresAt time.Time `json:"expires_at" binding:"required"`
+	// Passphrase the recipient must enter, bcrypt only uses its first 72 bytes.
+	Passphrase string `json:"passphrase" binding:"max=72"`
+}
+
+type OpenSendRequest struct {
+	Passphrase string `json:"passphrase"`
+}
+
+type SendRequestAndResponse struct {
+	ID string `json:"id" uri:"id" binding:"required,hexadecimal,len=32"`
+}
+
+// GetSends retrieves the sends of the logged-in user.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Get list of sends
+// @Description Retrieves the sends of the logged-in user with their remaining views and expiry
+// @Tags Sends
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Success 200 {array} services.Send
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /send/all [get]
+func GetSends(c *gin.Context) {
+	sendService, user := getServiceAndUser(c)
+
+	sends, err := sendService.GetSends(user.User.ID)
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, sends)
+}
+
+// CreateSend creates a send.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Create send
+// @Description Creates a text or file send. The client encrypts the name, the text and the file with a random key
+// @Description and shares the link /send/{id}#{key}, the key never reaches the server.
+// @Description The content of file sends is uploaded with PUT /send/upload/{id}.
+// @Tags Sends
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   send     body    CreateSendRequest     true        "Send"
+// @Success 200 {object} services.Send
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 413 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /send/create [post]
+func CreateSend(c *gin.Context) {
+	var request CreateSendRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	sendService, user := getServiceAndUser(c)
+
+	send, err := sendService.CreateSend(user.User.ID, services.SendData{
+		Type:       request.Type,
+		Name:       request.Name,
+		Text:       request.Text,
+		Size:       request.Size,
+		MaxViews:   request.MaxViews,
+		ExpiresAt:  request.ExpiresAt,
+		Passphrase: request.Passphrase,
+	})
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, send)
+}
+
+// UploadSendFile uploads the encrypted file of a file send.
+//
+// The request body is streamed to the blob storage without being buffered, so the Content-Length header is required.
+// @Summary Upload send file
+// @Description Stores the encrypted file sent as the raw request body, its size must match the size of the send
+// @Tags Sends
+// @Accept  octet-stream
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     string                             true        "Send ID"
+// @Success 200 {object} SendRequestAndResponse
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 411 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /send/upload/{id} [put]
+func UploadSendFile(c *gin.Context) {
+	var request SendRequestAndResponse
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if c.Request.ContentLength < 0 {
+		c.AbortWithStatusJSON(http.StatusLengthRequired, services2.ErrorResponse{Error: "Content-Length is required"})
+		return
+	}
+
+	sendService, user := getServiceAndUser(c)
+
+	err := sendService.UploadFile(c.Request.Context(), request.ID, user.User.ID, c.Request.ContentLength, c.Request.Body)
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, SendRequestAndResponse{ID: request.ID})
+}
+
+// DeleteSend deletes a send.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Delete send
+// @Description Deletes the send with the given ID and its file, its link stops working
+// @Tags Sends
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     string                             true        "Send ID"
+// @Success 200 {object} SendRequestAndResponse
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /send/delete/{id} [delete]
+func DeleteSend(c *gin.Context) {
+	var request SendRequestAndResponse
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	sendService, user := getServiceAndUser(c)
+
+	if err := sendService.DeleteSend(request.ID, user.User.ID); err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, SendRequestAndResponse{ID: request.ID})
+}
+
+// OpenSend opens a send through its link, no authentication is required.
+//
+// It is a POST request so link previews do not count views.
+// @Summary Open send
+// @Description Counts a view of the send and returns its ciphertext, to be decrypted with the key in the fragment of the link.
+// @Description Text sends are returned as JSON. File sends are returned as the raw encrypted file,
+// @Description with the encrypted name in the X-Send-Name header and the remaining views in X-Send-Views-Left.
+// @Description Expired sends, sends without views left and sends deleted after too many wrong passphrases are not found.
+// @Description Requests are limited per client IP.
+// @Tags Sends
+// @Accept  json
+// @Produce  json,octet-stream
+// @Param   id       path     string                             true        "Send ID"
+// @Param   passphrase     body    OpenSendRequest     false        "Passphrase of protected sends"
+// @Success 200 {object} services.SendContent
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 401 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 429 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /send/{id} [post]
+func OpenSend(c *gin.Context) {
+	var request SendRequestAndResponse
+	var json OpenSendRequest
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := c.ShouldBindJSON(&json); err != nil && !errors.Is(err, io.EOF) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	sendService := services.SendService{DB: services2.GetDBConnection()}
+
+	send, file, err := sendService.OpenSend(c.Request.Context(), request.ID, json.Passphrase)
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Header("X-Content-Type-Options", "nosniff")
+
+	if file == nil {
+		c.JSON(http.StatusOK, send)
+		return
+	}
+	defer file.Close()
+
+	c.DataFromReader(http.StatusOK, send.Size, "application/octet-stream", file, map[string]string{
+		"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{"filename": send.ID}),
+		"X-Send-Name":         send.Name,
+		"X-Send-Views-Left":   strconv.Itoa(send.ViewsLeft),
+	})
+}
+
+// errorStatus maps a service error to the HTTP status code of the response.
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrInvalidSend), errors.Is(err, services.ErrInvalidExpiry),
+		errors.Is(err, services.ErrIncompleteUpload):
+		return http.StatusBadRequest
+	case errors.Is(err, services.ErrWrongPassphrase):
+		return http.StatusUnauthorized
+	case errors.Is(err, services.ErrSendTooLarge), errors.Is(err, services.ErrQuotaExceeded):
+		return http.StatusRequestEntityTooLarge
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// getServiceAndUser returns the send service and user token.
+//
+// It takes a Gin context as a parameter.
+// It returns a SendService and a Token.
+func getServiceAndUser(c *gin.Context) (services.SendService, models.Token) {
+	service := services.SendService{DB: services2.GetDBConnection()}
+	user := services2.GetUserFromContext(c)
+
+	return service, user
+}
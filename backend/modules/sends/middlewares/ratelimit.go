@@ -0,0 +1,56 @@
+package middlewares
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimitMiddleware limits the requests of each client IP within a fixed window.
+//
+// The counters of all clients are reset together at the end of each window, so memory stays bounded
+// by the clients seen during one window. The counters are kept in memory and are not shared between instances.
+//
+// Parameters:
+//   - limit: the number of requests allowed per client IP in a window, 0 disables the limit.
+//   - window: the duration of a window.
+//
+// Return:
+//   - gin.HandlerFunc: A function that aborts the request with 429 Too Many Requests once the limit is reached.
+func RateLimitMiddleware(limit int, window time.Duration) gin.HandlerFunc {
+	var mutex sync.Mutex
+
+	counts := map[string]int{}
+	resetAt := time.Now().Add(window)
+
+	return func(c *gin.Context) {
+		if limit <= 0 {
+			c.Next()
+			return
+		}
+
+		mutex.Lock()
+
+		now := time.Now()
+		if !now.Before(resetAt) {
+			counts = map[string]int{}
+			resetAt = now.Add(window)
+		}
+
+		ip := c.ClientIP()
+		counts[ip]++
+		count, retryAfter := counts[ip], resetAt.Sub(now)
+
+		mutex.Unlock()
+
+		if count > limit {
+			c.Header("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "Too many requests"})
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,269 @@
+package models
+
+import (
+	"backend/modules/users/models"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+const (
+	TypeText = "text"
+	TypeFile = "file"
+)
+
+var ErrQuotaExceeded = errors.New("send storage quota exceeded")
+
+// Send is a secret shared by link with people outside the system.
+//
+// The client encrypts the name, the text and the file with a random key kept in the fragment of the link,
+// the server only stores ciphertext. File sends are Ready once their content is uploaded.
+type Send struct {
+	ID        string `gorm:"primaryKey;size:32"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	UserID    uint        `gorm:"not null;index"`
+	User      models.User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	Type      string      `gorm:"not null"`
+	Name      string      `gorm:"not null"`
+	Text      string      `gorm:"not null;default:''"`
+	// StorageKey is the key of the encrypted file in the blob storage, empty for text sends.
+	StorageKey string `gorm:"not null;default:''"`
+	Size       int64  `gorm:"not null;default:0"`
+	Ready      bool   `gorm:"not null;default:false"`
+	// PassphraseHash is the bcrypt hash of the optional access passphrase.
+	PassphraseHash string `gorm:"not null;default:''"`
+	// FailedAttempts counts the wrong passphrases entered since the send was created.
+	FailedAttempts int       `gorm:"not null;default:0"`
+	MaxViews       int       `gorm:"not null"`
+	ViewsLeft      int       `gorm:"not null"`
+	ExpiresAt      time.Time `gorm:"not null;index"`
+}
+
+type SendModel struct {
+	DB *gorm.DB
+}
+
+// GetAll returns the sends of a given user, newest first.
+//
+// userId: the ID of the user.
+// []Send: the sends, including the expired ones that were not purged yet.
+// error: any error that occurred during the retrieval process.
+func (m *SendModel) GetAll(userId uint) ([]Send, error) {
+	var sends []Send
+
+	err := m.DB.Where("user_id = ?", userId).Order("created_at DESC, id").Find(&sends).Error
+
+	return sends, err
+}
+
+// Create saves a send with a random ID and, for files, a random storage key.
+//
+// The user row is locked while the quota is checked, so concurrent sends cannot exceed it.
+//
+// Parameters:
+// - send: the send to save, text sends are ready at once.
+// - quota: the maximal total size in bytes of the file sends of the user, 0 for no limit.
+//
+// Returns:
+// - Send: the saved send.
+// - error: ErrQuotaExceeded or any creation error.
+func (m *SendModel) Create(send Send, quota int64) (Send, error) {
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return Send{}, err
+	}
+
+	send.ID = hex.EncodeToString(random)
+	send.ViewsLeft = send.MaxViews
+	send.Ready = send.Type == TypeText
+
+	if send.Type == TypeFile {
+		send.StorageKey = fmt.Sprintf("sends/%d/%s", send.UserID, send.ID)
+	}
+
+	err := m.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			Where("id = ?", send.UserID).
+			First(&models.User{}).Error
+		if err != nil {
+			return err
+		}
+
+		if send.Type == TypeFile && quota > 0 {
+			var used int64
+
+			err = tx.Model(&Send{}).
+				Select("COALESCE(SUM(size), 0)").
+				Where("user_id = ? AND type = ?", send.UserID, TypeFile).
+				Scan(&used).Error
+			if err != nil {
+				return err
+			}
+
+			if used+send.Size > quota {
+				return ErrQuotaExceeded
+			}
+		}
+
+		return tx.Omit("User").Create(&send).Error
+	})
+
+	return send, err
+}
+
+// GetPending returns a file send of a given user whose content was not uploaded yet.
+//
+// Parameters:
+// - id: the ID of the send.
+// - userId: the ID of the user.
+//
+// Returns:
+// - Send: the send.
+// - error: gorm.ErrRecordNotFound if there is no such pending send.
+func (m *SendModel) GetPending(id string, userId uint) (Send, error) {
+	var send Send
+
+	err := m.DB.Where("id = ? AND user_id = ? AND type = ? AND NOT ready", id, userId, TypeFile).First(&send).Error
+
+	return send, err
+}
+
+// MarkReady marks the content of a file send as uploaded.
+//
+// id: the ID of the send.
+// Returns an error if the update fails.
+func (m *SendModel) MarkReady(id string) error {
+	return m.DB.Model(&Send{}).Where("id = ?", id).Update("ready", true).Error
+}
+
+// GetAvailable returns a send that can still be viewed, without counting a view.
+//
+// Parameters:
+// - id: the ID of the send.
+// - now: the current time.
+//
+// Returns:
+// - Send: the send.
+// - error: gorm.ErrRecordNotFound if the send does not exist, is not ready, expired or has no views left.
+func (m *SendModel) GetAvailable(id string, now time.Time) (Send, error) {
+	var send Send
+
+	err := available(m.DB, id, now).First(&send).Error
+
+	return send, err
+}
+
+// View counts a view of a send.
+//
+// The row is locked while the view is counted, so concurrent requests cannot exceed the maximal views.
+//
+// Parameters:
+// - id: the ID of the send.
+// - now: the current time.
+//
+// Returns:
+// - Send: the send with the remaining views.
+// - error: gorm.ErrRecordNotFound if the send expired or has no views left anymore, or any update error.
+func (m *SendModel) View(id string, now time.Time) (Send, error) {
+	var send Send
+
+	err := m.DB.Transaction(func(tx *gorm.DB) error {
+		err := available(tx.Clauses(clause.Locking{Strength: "UPDATE"}), id, now).First(&send).Error
+		if err != nil {
+			return err
+		}
+
+		send.ViewsLeft--
+
+		return tx.Model(&Send{}).Where("id = ?", id).Update("views_left", send.ViewsLeft).Error
+	})
+
+	return send, err
+}
+
+// Fail counts a wrong passphrase for a send and deletes the send once maxAttempts are reached.
+//
+// The row is locked while the attempt is counted, so concurrent guesses cannot exceed the limit.
+//
+// Parameters:
+// - id: the ID of the send.
+// - maxAttempts: the number of wrong passphrases after which the send is deleted.
+//
+// Returns:
+// - string: the storage key of the file if the send was deleted, empty otherwise.
+// - error: gorm.ErrRecordNotFound if the send is already gone, or any update error.
+func (m *SendModel) Fail(id string, maxAttempts int) (string, error) {
+	var send Send
+
+	err := m.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "storage_key", "failed_attempts").
+			Where("id = ?", id).
+			First(&send).Error
+		if err != nil {
+			return err
+		}
+
+		send.FailedAttempts++
+
+		if send.FailedAttempts < maxAttempts {
+			send.StorageKey = ""
+			return tx.Model(&Send{}).Where("id = ?", id).Update("failed_attempts", send.FailedAttempts).Error
+		}
+
+		return tx.Delete(&Send{}, "id = ?", id).Error
+	})
+
+	return send.StorageKey, err
+}
+
+// Delete deletes a send of a given user.
+//
+// Parameters:
+// - id: the ID of the send.
+// - userId: the ID of the user.
+//
+// Returns:
+// - string: the storage key of the deleted file, empty for text sends.
+// - error: gorm.ErrRecordNotFound if the send does not belong to the user, or any deletion error.
+func (m *SendModel) Delete(id string, userId uint) (string, error) {
+	var send Send
+
+	err := m.DB.Select("id", "storage_key").Where("id = ? AND user_id = ?", id, userId).First(&send).Error
+	if err != nil {
+		return "", err
+	}
+
+	return send.StorageKey, m.DB.Delete(&send).Error
+}
+
+// GetAllExpired returns the sends of all users that expired, have no views left,
+// or whose upload did not finish before a given time.
+//
+// Parameters:
+// - now: the current time.
+// - pendingBefore: the creation time before which unfinished uploads are abandoned.
+//
+// Returns:
+// - []Send: the sends.
+// - error: any error that occurred during the retrieval process.
+func (m *SendModel) GetAllExpired(now, pendingBefore time.Time) ([]Send, error) {
+	var sends []Send
+
+	err := m.DB.Select("id", "user_id", "storage_key").
+		Where("expires_at <= ? OR views_left <= 0 OR (NOT ready AND created_at < ?)", now, pendingBefore).
+		Find(&sends).Error
+
+	return sends, err
+}
+
+// available filters a send that is ready, not expired and has views left.
+func available(db *gorm.DB, id string, now time.Time) *gorm.DB {
+	return db.Where("id = ? AND ready AND expires_at > ? AND views_left > 0", id, now)
+}
@@ -0,0 +1,329 @@
+package services
+
+import (
+	"backend/modules/sends/models"
+	"backend/services/config"
+	"backend/services/storage"
+	"context"
+	"errors"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"io"
+	"log"
+	"time"
+)
+
+const megabyte = 1024 * 1024
+
+// pendingUploadTimeout is the time after which a file send whose content was not uploaded is purged.
+const pendingUploadTimeout = time.Hour
+
+var (
+	ErrInvalidSend      = errors.New("text sends need a text and file sends a size")
+	ErrInvalidExpiry    = errors.New("the expiry must be in the future and within the maximal lifetime of sends")
+	ErrSendTooLarge     = errors.New("send is too large")
+	ErrIncompleteUpload = errors.New("upload ended before the announced size")
+	ErrWrongPassphrase  = errors.New("a valid passphrase is required to open this send")
+	ErrQuotaExceeded    = models.ErrQuotaExceeded
+)
+
+type SendService struct {
+	DB *gorm.DB
+}
+
+// Send is a send seen by its owner.
+type Send struct {
+	ID            string    `json:"id"`
+	Type          string    `json:"type"`
+	Name          string    `json:"name"`
+	Size          int64     `json:"size"`
+	Ready         bool      `json:"ready"`
+	HasPassphrase bool      `json:"has_passphrase"`
+	MaxViews      int       `json:"max_views"`
+	ViewsLeft     int       `json:"views_left"`
+	ExpiresAt     time.Time `json:"expires_at"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// SendContent is a send opened through its link, its name and text are still encrypted with the key of the link.
+type SendContent struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	Name      string    `json:"name"`
+	Text      string    `json:"text"`
+	Size      int64     `json:"size"`
+	ViewsLeft int       `json:"views_left"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type SendData struct {
+	Type string
+	// Name, Text and the uploaded file are encrypted by the client.
+	Name       string
+	Text       string
+	Size       int64
+	MaxViews   int
+	ExpiresAt  time.Time
+	Passphrase string
+}
+
+// getModel returns a SendModel.
+//
+// No parameters.
+// Returns a models.SendModel.
+func (s *SendService) getModel() models.SendModel {
+	return models.SendModel{DB: s.DB}
+}
+
+// GetSends returns the sends of a given user.
+//
+// Parameters:
+// - userId: the ID of the user.
+//
+// Returns:
+// - []Send: the sends, newest first.
+// - error: any retrieval error.
+func (s *SendService) GetSends(userId uint) ([]Send, error) {
+	sendModel := s.getModel()
+
+	sends, err := sendModel.GetAll(userId)
+
+	sendsList := []Send{}
+
+	for _, send := range sends {
+		sendsList = append(sendsList, toSend(send))
+	}
+
+	return sendsList, err
+}
+
+// CreateSend creates a send, file sends wait for their content to be uploaded with UploadFile.
+//
+// SEND_MAX_DAYS sets the maximal lifetime of a send (30 by default), SEND_MAX_SIZE_MB the maximal size of a file (25 by default)
+// and SEND_QUOTA_MB the maximal total size of the file sends of a user (100 by default, 0 disables it).
+//
+// Parameters:
+// - userId: the ID of the user.
+// - data: the encrypted content, the view limit, the expiry and the optional passphrase of the send.
+//
+// Returns:
+// - Send: the created send, its ID goes in the link with the key in the fragment.
+// - error: ErrInvalidSend, ErrInvalidExpiry, ErrSendTooLarge, ErrQuotaExceeded or any creation error.
+func (s *SendService) CreateSend(userId uint, data SendData) (Send, error) {
+	switch {
+	case data.Type == models.TypeText && data.Text == "",
+		data.Type == models.TypeFile && (data.Size <= 0 || data.Text != ""):
+		return Send{}, ErrInvalidSend
+	}
+
+	maxSize := int64(config.GetInt("SEND_MAX_SIZE_MB", 25)) * megabyte
+	if data.Type == models.TypeFile && maxSize > 0 && data.Size > maxSize {
+		return Send{}, ErrSendTooLarge
+	}
+
+	now := time.Now()
+	if !data.ExpiresAt.After(now) || data.ExpiresAt.After(now.Add(config.GetDays("SEND_MAX_DAYS", 30))) {
+		return Send{}, ErrInvalidExpiry
+	}
+
+	passphraseHash := ""
+	if data.Passphrase != "" {
+		hash, err := bcrypt.GenerateFromPassword([]byte(data.Passphrase), bcrypt.DefaultCost)
+		if err != nil {
+			return Send{}, err
+		}
+
+		passphraseHash = string(hash)
+	}
+
+	sendModel := s.getModel()
+
+	send, err := sendModel.Create(models.Send{
+		UserID:         userId,
+		Type:           data.Type,
+		Name:           data.Name,
+		Text:           data.Text,
+		Size:           data.Size,
+		PassphraseHash: passphraseHash,
+		MaxViews:       data.MaxViews,
+		ExpiresAt:      data.ExpiresAt,
+	}, int64(config.GetInt("SEND_QUOTA_MB", 100))*megabyte)
+	if err != nil {
+		return Send{}, err
+	}
+
+	return toSend(send), nil
+}
+
+// UploadFile stores the encrypted content of a file send.
+//
+// Parameters:
+// - ctx: the context of the request.
+// - id: the ID of the send.
+// - userId: the ID of the user.
+// - size: the announced size of the content, it must match the size of the send.
+// - content: the encrypted file.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound if there is no pending file send, ErrIncompleteUpload or any storage error.
+func (s *SendService) UploadFile(ctx context.Context, id string, userId uint, size int64, content io.Reader) error {
+	sendModel := s.getModel()
+
+	send, err := sendModel.GetPending(id, userId)
+	if err != nil {
+		return err
+	}
+
+	if size != send.Size {
+		return ErrIncompleteUpload
+	}
+
+	if err := storage.Get().Put(ctx, send.StorageKey, io.LimitReader(content, size), size); err != nil {
+		s.removeFiles(send.StorageKey)
+		return err
+	}
+
+	return sendModel.MarkReady(id)
+}
+
+// DeleteSend deletes a send and its file.
+//
+// Parameters:
+// - id: the ID of the send.
+// - userId: the ID of the user.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound if the send does not belong to the user, or any deletion error.
+func (s *SendService) DeleteSend(id string, userId uint) error {
+	sendModel := s.getModel()
+
+	key, err := sendModel.Delete(id, userId)
+	if err != nil {
+		return err
+	}
+
+	s.removeFiles(key)
+
+	return nil
+}
+
+// OpenSend opens a send through its link and counts a view.
+//
+// The caller closes the returned reader, which is nil for text sends.
+// A send is deleted after SEND_MAX_ATTEMPTS wrong passphrases (10 by default).
+//
+// Parameters:
+// - ctx: the context of the request.
+// - id: the ID of the send.
+// - passphrase: the access passphrase, ignored if the send has none.
+//
+// Returns:
+// - SendContent: the encrypted send.
+// - io.ReadCloser: the encrypted file of file sends.
+// - error: gorm.ErrRecordNotFound if the send does not exist, expired or has no views left,
+// ErrWrongPassphrase, or any storage error.
+func (s *SendService) OpenSend(ctx context.Context, id, passphrase string) (SendContent, io.ReadCloser, error) {
+	sendModel := s.getModel()
+
+	// The passphrase is checked before locking the send, bcrypt is slow on purpose.
+	send, err := sendModel.GetAvailable(id, time.Now())
+	if err != nil {
+		return SendContent{}, nil, err
+	}
+
+	if send.PassphraseHash != "" {
+		if err := bcrypt.CompareHashAndPassword([]byte(send.PassphraseHash), []byte(passphrase)); err != nil {
+			key, err := sendModel.Fail(id, config.GetInt("SEND_MAX_ATTEMPTS", 10))
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return SendContent{}, nil, err
+			}
+
+			s.removeFiles(key)
+
+			return SendContent{}, nil, ErrWrongPassphrase
+		}
+	}
+
+	send, err = sendModel.View(id, time.Now())
+	if err != nil {
+		return SendContent{}, nil, err
+	}
+
+	content := SendContent{
+		ID:        send.ID,
+		Type:      send.Type,
+		Name:      send.Name,
+		Text:      send.Text,
+		Size:      send.Size,
+		ViewsLeft: send.ViewsLeft,
+		ExpiresAt: send.ExpiresAt,
+	}
+
+	if send.Type != models.TypeFile {
+		return content, nil, nil
+	}
+
+	file, err := storage.Get().Get(ctx, send.StorageKey)
+	if err != nil {
+		return SendContent{}, nil, err
+	}
+
+	return content, file, nil
+}
+
+// Purge deletes the sends of all users that expired or have no views left, and the abandoned uploads.
+//
+// It does not take any parameters.
+// It returns the number of deleted sends and an error if a deletion fails.
+func (s *SendService) Purge() (int, error) {
+	sendModel := s.getModel()
+
+	now := time.Now()
+
+	sends, err := sendModel.GetAllExpired(now, now.Add(-pendingUploadTimeout))
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+
+	for _, send := range sends {
+		if err := s.DeleteSend(send.ID, send.UserID); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return count, err
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+// removeFiles deletes files from the blob storage.
+//
+// The rows are already gone or the upload failed at this point, so failures are only logged.
+func (s *SendService) removeFiles(keys ...string) {
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+
+		if err := storage.Get().Delete(context.Background(), key); err != nil {
+			log.Printf("failed to delete send file %s: %v", key, err)
+		}
+	}
+}
+
+// toSend converts a send model to its response representation.
+func toSend(send models.Send) Send {
+	return Send{
+		ID:            send.ID,
+		Type:          send.Type,
+		Name:          send.Name,
+		Size:          send.Size,
+		Ready:         send.Ready,
+		HasPassphrase: send.PassphraseHash != "",
+		MaxViews:      send.MaxViews,
+		ViewsLeft:     send.ViewsLeft,
+		ExpiresAt:     send.ExpiresAt,
+		CreatedAt:     send.CreatedAt,
+	}
+}
@@ -0,0 +1,257 @@
+package actions
+
+import (
+	"backend/modules/tags/services"
+	"backend/modules/users/models"
+	services2 "backend/services"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"net/http"
+)
+
+type CreateOrUpdateTagRequest struct {
+	Name string `json:"name" binding:"required,max=64"`
+	// Hex colour such as #e5484d, empty for the default colour.
+	Color string `json:"color" binding:"omitempty,hexcolor"`
+}
+
+type TagRequestAndResponse struct {
+	ID uint `json:"id" uri:"id" binding:"required"`
+}
+
+type TagPasswordsRequest struct {
+	PasswordIDs []uint `json:"password_ids" binding:"required,min=1,max=1000"`
+}
+
+type TagPasswordsResponse struct {
+	ID    uint  `json:"id"`
+	Count int64 `json:"count"`
+}
+
+// GetTags retrieves the tags of the logged-in user.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Get list of tags
+// @Description Retrieves the tags for the logged-in user ordered by name
+// @Tags Tags
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Success 200 {array} services.Tag
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /tag/all [get]
+func GetTags(c *gin.Context) {
+	tagService, user := getServiceAndUser(c)
+
+	tags, err := tagService.GetTags(user.User.ID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, tags)
+}
+
+// CreateTag handles the creation of a new tag.
+//
+// It expects a gin.Context parameter to access the HTTP request and response.
+// It does not have any return values.
+// @Summary Create a new tag
+// @Description Creates a new tag, the name must be unique for the logged-in user
+// @Tags Tags
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   tag     body    CreateOrUpdateTagRequest     true        "Tag"
+// @Success 200 {object} services.Tag
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 409 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /tag/create [post]
+func CreateTag(c *gin.Context) {
+	var request CreateOrUpdateTagRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	tagService, user := getServiceAndUser(c)
+
+	tag, err := tagService.CreateTag(user.User.ID, request.Name, request.Color)
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, tag)
+}
+
+// UpdateTag updates a tag.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Update tag
+// @Description Renames and recolours the tag with the given ID
+// @Tags Tags
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Tag ID"
+// @Param   tag     body    CreateOrUpdateTagRequest     true        "Tag"
+// @Success 200 {object} TagRequestAndResponse
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 409 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /tag/update/{id} [put]
+func UpdateTag(c *gin.Context) {
+	var request TagRequestAndResponse
+	var json CreateOrUpdateTagRequest
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := c.ShouldBindJSON(&json); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	tagService, user := getServiceAndUser(c)
+
+	id, err := tagService.UpdateTag(request.ID, user.User.ID, json.Name, json.Color)
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, TagRequestAndResponse{ID: id})
+}
+
+// DeleteTag deletes a tag.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Delete tag
+// @Description Deletes the tag with the given ID and detaches it from the passwords, which are kept
+// @Tags Tags
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Tag ID"
+// @Success 200 {object} TagRequestAndResponse
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /tag/delete/{id} [delete]
+func DeleteTag(c *gin.Context) {
+	var request TagRequestAndResponse
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	tagService, user := getServiceAndUser(c)
+
+	err := tagService.DeleteTag(request.ID, user.User.ID)
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, TagRequestAndResponse{ID: request.ID})
+}
+
+// AttachTag adds a tag to several passwords.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Tag passwords
+// @Description Adds the tag with the given ID to the passwords, passwords that already have it are skipped
+// @Tags Tags
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Tag ID"
+// @Param   passwords     body    TagPasswordsRequest     true        "Password IDs"
+// @Success 200 {object} TagPasswordsResponse
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /tag/attach/{id} [post]
+func AttachTag(c *gin.Context) {
+	tagPasswords(c, func(service services.TagService, id, userId uint, passwordIds []uint) (int64, error) {
+		return service.AttachTag(id, userId, passwordIds)
+	})
+}
+
+// DetachTag removes a tag from several passwords.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Untag passwords
+// @Description Removes the tag with the given ID from the passwords
+// @Tags Tags
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Tag ID"
+// @Param   passwords     body    TagPasswordsRequest     true        "Password IDs"
+// @Success 200 {object} TagPasswordsResponse
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /tag/detach/{id} [post]
+func DetachTag(c *gin.Context) {
+	tagPasswords(c, func(service services.TagService, id, userId uint, passwordIds []uint) (int64, error) {
+		return service.DetachTag(id, userId, passwordIds)
+	})
+}
+
+// tagPasswords binds a bulk tag request and applies the operation to it.
+func tagPasswords(c *gin.Context, operation func(service services.TagService, id, userId uint, passwordIds []uint) (int64, error)) {
+	var request TagRequestAndResponse
+	var json TagPasswordsRequest
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := c.ShouldBindJSON(&json); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	tagService, user := getServiceAndUser(c)
+
+	count, err := operation(tagService, request.ID, user.User.ID, json.PasswordIDs)
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, TagPasswordsResponse{ID: request.ID, Count: count})
+}
+
+// errorStatus maps a service error to the HTTP status code of the response.
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrTagExists):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// getServiceAndUser returns the tag service and user token.
+//
+// It takes a Gin context as a parameter.
+// It returns a TagService and a Token.
+func getServiceAndUser(c *gin.Context) (services.TagService, models.Token) {
+	service := services.TagService{DB: services2.GetDBConnection()}
+	user := services2.GetUserFromContext(c)
+
+	return service, user
+}
@@ -0,0 +1,133 @@
+package models
+
+import (
+	"backend/modules/users/models"
+	"gorm.io/gorm"
+)
+
+type Tag struct {
+	gorm.Model
+	UserID uint        `gorm:"not null;uniqueIndex:idx_tags_user_name"`
+	User   models.User `gorm:"foreignKey:UserID"`
+	Name   string      `gorm:"not null;uniqueIndex:idx_tags_user_name"`
+	// Color is a #rrggbb or #rgb hex colour, empty for the default colour.
+	Color string `gorm:"not null;default:''"`
+}
+
+type TagModel struct {
+	DB *gorm.DB
+}
+
+// GetAll returns all tags of a given user ID.
+//
+// userId: the ID of the user to retrieve tags for.
+// []Tag: the tags ordered by name.
+// error: any error that occurred during the retrieval process.
+func (m *TagModel) GetAll(userId uint) ([]Tag, error) {
+	var tags []Tag
+
+	err := m.DB.Where("user_id = ?", userId).Order("name").Find(&tags).Error
+
+	return tags, err
+}
+
+// Get returns a single tag by its ID for a given user ID.
+//
+// Parameters:
+// - id: the ID of the tag.
+// - userId: the ID of the user who owns the tag.
+//
+// Returns:
+// - Tag: the found tag.
+// - error: gorm.ErrRecordNotFound if the tag does not exist or belongs to another user.
+func (m *TagModel) Get(id, userId uint) (Tag, error) {
+	var tag Tag
+
+	err := m.DB.Where("id = ? AND user_id = ?", id, userId).First(&tag).Error
+
+	return tag, err
+}
+
+// Count returns how many of the given tags belong to a user.
+//
+// Parameters:
+// - ids: the IDs of the tags, without duplicates.
+// - userId: the ID of the user.
+//
+// Returns:
+// - int64: the number of tags owned by the user.
+// - error: any error that occurred during the count.
+func (m *TagModel) Count(ids []uint, userId uint) (int64, error) {
+	var count int64
+
+	err := m.DB.Model(&Tag{}).Where("id IN ? AND user_id = ?", ids, userId).Count(&count).Error
+
+	return count, err
+}
+
+// Create stores a new tag.
+//
+// Parameters:
+// - tag: the tag to create, UserID must be set.
+//
+// Returns:
+// - Tag: the created tag with its ID.
+// - error: an error if there was a problem creating the tag, e.g. a duplicate name.
+func (m *TagModel) Create(tag Tag) (Tag, error) {
+	err := m.DB.Create(&tag).Error
+	if err != nil {
+		return Tag{}, err
+	}
+
+	return tag, nil
+}
+
+// Update overwrites the name and colour of a tag with the given ID and user ID.
+//
+// Parameters:
+// - id: the ID of the tag to update.
+// - userId: the ID of the user who owns the tag.
+// - tag: the new values.
+//
+// Returns:
+// - uint: the ID of the tag that was updated.
+// - error: gorm.ErrRecordNotFound if nothing was updated, or an error if the update operation fails.
+func (m *TagModel) Update(id, userId uint, tag Tag) (uint, error) {
+	result := m.DB.Model(&Tag{}).
+		Where("id = ? AND user_id = ?", id, userId).
+		Select("Name", "Color").
+		Updates(tag)
+	if result.Error != nil {
+		return id, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return id, gorm.ErrRecordNotFound
+	}
+
+	return id, nil
+}
+
+// Delete deletes a tag from the database.
+//
+// Tags are deleted permanently, so their names can be reused, and the database
+// detaches them from the passwords.
+//
+// Parameters:
+// - id: the ID of the tag to delete.
+// - userId: the ID of the user who owns the tag.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound if nothing was deleted, or an error if the deletion fails.
+func (m *TagModel) Delete(id, userId uint) error {
+	result := m.DB.Unscoped().Where("id = ? AND user_id = ?", id, userId).Delete(&Tag{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
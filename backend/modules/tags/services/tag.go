@@ -0,0 +1,193 @@
+package services
+
+import (
+	models2 "backend/modules/passwords/models"
+	"backend/modules/tags/models"
+	"errors"
+	"gorm.io/gorm"
+)
+
+var ErrTagExists = errors.New("tag with this name already exists")
+
+type TagService struct {
+	DB *gorm.DB
+}
+
+type Tag struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+// getModel returns a TagModel.
+//
+// No parameters.
+// Returns a models.TagModel.
+func (s *TagService) getModel() models.TagModel {
+	return models.TagModel{DB: s.DB}
+}
+
+// GetTags returns the tags of a given user.
+//
+// It takes in a userId of type uint as a parameter.
+// It returns a slice of Tag ordered by name and an error.
+func (s *TagService) GetTags(userId uint) ([]Tag, error) {
+	tagModel := s.getModel()
+
+	tags, err := tagModel.GetAll(userId)
+
+	tagsList := []Tag{}
+
+	for _, tag := range tags {
+		tagsList = append(tagsList, ToTag(tag))
+	}
+
+	return tagsList, err
+}
+
+// CreateTag creates a new tag for a given user.
+//
+// Parameters:
+// - userId: the ID of the user.
+// - name: the name of the tag, unique per user.
+// - color: the hex colour of the tag, may be empty.
+//
+// Returns:
+// - Tag: the created tag.
+// - error: ErrTagExists or any creation error.
+func (s *TagService) CreateTag(userId uint, name, color string) (Tag, error) {
+	if err := s.checkName(0, userId, name); err != nil {
+		return Tag{}, err
+	}
+
+	tagModel := s.getModel()
+
+	tag, err := tagModel.Create(models.Tag{UserID: userId, Name: name, Color: color})
+	if err != nil {
+		return Tag{}, err
+	}
+
+	return ToTag(tag), nil
+}
+
+// UpdateTag renames and recolours a tag of a given user.
+//
+// Parameters:
+// - id: the ID of the tag.
+// - userId: the ID of the user.
+// - name: the new name of the tag.
+// - color: the new hex colour of the tag, may be empty.
+//
+// Returns:
+// - uint: the ID of the updated tag.
+// - error: ErrTagExists, gorm.ErrRecordNotFound or any update error.
+func (s *TagService) UpdateTag(id, userId uint, name, color string) (uint, error) {
+	if err := s.checkName(id, userId, name); err != nil {
+		return id, err
+	}
+
+	tagModel := s.getModel()
+
+	if _, err := tagModel.Update(id, userId, models.Tag{Name: name, Color: color}); err != nil {
+		return id, err
+	}
+
+	return id, s.refreshSearch(id, nil)
+}
+
+// DeleteTag deletes a tag by its ID and user ID, the tagged passwords are kept.
+//
+// Parameters:
+// - id: the ID of the tag to be deleted.
+// - userId: the ID of the user requesting the deletion.
+//
+// Return type: error.
+func (s *TagService) DeleteTag(id, userId uint) error {
+	passwordModel := models2.PasswordModel{DB: s.DB}
+
+	passwordIds, err := passwordModel.TaggedWith(id)
+	if err != nil {
+		return err
+	}
+
+	tagModel := s.getModel()
+
+	if err := tagModel.Delete(id, userId); err != nil {
+		return err
+	}
+
+	return s.refreshSearch(id, passwordIds)
+}
+
+// AttachTag adds a tag to several passwords of a given user.
+//
+// Parameters:
+// - id: the ID of the tag.
+// - userId: the ID of the user.
+// - passwordIds: the IDs of the passwords, those of other users are ignored.
+//
+// Returns:
+// - int64: the number of newly tagged passwords.
+// - error: gorm.ErrRecordNotFound if the tag does not belong to the user, or any update error.
+func (s *TagService) AttachTag(id, userId uint, passwordIds []uint) (int64, error) {
+	passwordModel := models2.PasswordModel{DB: s.DB}
+
+	return passwordModel.AttachTag(id, userId, passwordIds)
+}
+
+// DetachTag removes a tag from several passwords of a given user.
+//
+// Parameters:
+// - id: the ID of the tag.
+// - userId: the ID of the user.
+// - passwordIds: the IDs of the passwords.
+//
+// Returns:
+// - int64: the number of untagged passwords.
+// - error: gorm.ErrRecordNotFound if the tag does not belong to the user, or any update error.
+func (s *TagService) DetachTag(id, userId uint, passwordIds []uint) (int64, error) {
+	passwordModel := models2.PasswordModel{DB: s.DB}
+
+	return passwordModel.DetachTag(id, userId, passwordIds)
+}
+
+// checkName returns ErrTagExists if another tag of the user already has the name.
+func (s *TagService) checkName(id, userId uint, name string) error {
+	var count int64
+
+	err := s.DB.Model(&models.Tag{}).
+		Where("user_id = ? AND name = ? AND id <> ?", userId, name, id).
+		Count(&count).Error
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return ErrTagExists
+	}
+
+	return nil
+}
+
+// refreshSearch updates the search index of the passwords having a tag.
+//
+// passwordIds is nil for a renamed tag, the tagged passwords are looked up then.
+func (s *TagService) refreshSearch(id uint, passwordIds []uint) error {
+	if passwordIds == nil {
+		passwordModel := models2.PasswordModel{DB: s.DB}
+
+		var err error
+		if passwordIds, err = passwordModel.TaggedWith(id); err != nil {
+			return err
+		}
+	}
+
+	searchModel := models2.SearchModel{DB: s.DB}
+
+	return searchModel.RefreshTags(passwordIds)
+}
+
+// ToTag converts a tag model to its response representation.
+func ToTag(tag models.Tag) Tag {
+	return Tag{ID: tag.ID, Name: tag.Name, Color: tag.Color}
+}
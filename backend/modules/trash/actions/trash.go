@@ -0,0 +1,191 @@
+package actions
+
+import (
+	"backend/modules/trash/services"
+	"backend/modules/users/models"
+	services2 "backend/services"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"net/http"
+)
+
+type TrashRequestAndResponse struct {
+	ID uint `json:"id" uri:"id" binding:"required"`
+}
+
+type EmptyTrashResponse struct {
+	Count int `json:"count"`
+}
+
+// GetTrash retrieves the deleted categories and passwords of the logged-in user.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Get trash
+// @Description Retrieves the deleted categories and passwords of the logged-in user, secrets are not included.
+// @Description Items are permanently deleted after TRASH_RETENTION_DAYS days.
+// @Tags Trash
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Success 200 {object} services.Trash
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /trash/all [get]
+func GetTrash(c *gin.Context) {
+	trashService, user := getServiceAndUser(c)
+
+	trash, err := trashService.GetTrash(user.User.ID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, trash)
+}
+
+// RestorePassword restores a deleted password.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Restore password
+// @Description Restores the deleted password with the given ID, its category is restored too if it was deleted
+// @Tags Trash
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Password ID"
+// @Success 200 {object} TrashRequestAndResponse
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /trash/password/restore/{id} [post]
+func RestorePassword(c *gin.Context) {
+	trashItem(c, func(service services.TrashService, id, userId uint) error {
+		return service.RestorePassword(id, userId)
+	})
+}
+
+// DeletePassword permanently deletes a password from the trash.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Delete password permanently
+// @Description Permanently deletes the deleted password with the given ID together with its history and attachments
+// @Tags Trash
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Password ID"
+// @Success 200 {object} TrashRequestAndResponse
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /trash/password/delete/{id} [delete]
+func DeletePassword(c *gin.Context) {
+	trashItem(c, func(service services.TrashService, id, userId uint) error {
+		return service.DeletePassword(id, userId)
+	})
+}
+
+// RestoreCategory restores a deleted category.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Restore category
+// @Description Restores the deleted category with the given ID
+// @Tags Trash
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Category ID"
+// @Success 200 {object} TrashRequestAndResponse
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /trash/category/restore/{id} [post]
+func RestoreCategory(c *gin.Context) {
+	trashItem(c, func(service services.TrashService, id, userId uint) error {
+		return service.RestoreCategory(id, userId)
+	})
+}
+
+// DeleteCategory permanently deletes a category from the trash.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Delete category permanently
+// @Description Permanently deletes the deleted category with the given ID, its passwords are kept without a category
+// @Tags Trash
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id       path     int                             true        "Category ID"
+// @Success 200 {object} TrashRequestAndResponse
+// @Failure 400 {object} services2.ErrorResponse
+// @Failure 404 {object} services2.ErrorResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /trash/category/delete/{id} [delete]
+func DeleteCategory(c *gin.Context) {
+	trashItem(c, func(service services.TrashService, id, userId uint) error {
+		return service.DeleteCategory(id, userId)
+	})
+}
+
+// EmptyTrash permanently deletes everything in the trash.
+//
+// It expects a *gin.Context parameter and returns nothing.
+// @Summary Empty trash
+// @Description Permanently deletes all deleted categories and passwords of the logged-in user
+// @Tags Trash
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Success 200 {object} EmptyTrashResponse
+// @Failure 500 {object} services2.ErrorResponse
+// @Router /trash/empty [delete]
+func EmptyTrash(c *gin.Context) {
+	trashService, user := getServiceAndUser(c)
+
+	count, err := trashService.Empty(user.User.ID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, EmptyTrashResponse{Count: count})
+}
+
+// trashItem binds the ID of a trashed item and applies the operation to it.
+func trashItem(c *gin.Context, operation func(service services.TrashService, id, userId uint) error) {
+	var request TrashRequestAndResponse
+
+	if err := c.ShouldBindUri(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	trashService, user := getServiceAndUser(c)
+
+	if err := operation(trashService, request.ID, user.User.ID); err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, TrashRequestAndResponse{ID: request.ID})
+}
+
+// errorStatus maps a service error to the HTTP status code of the response.
+func errorStatus(err error) int {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return http.StatusNotFound
+	}
+
+	return http.StatusInternalServerError
+}
+
+// getServiceAndUser returns the trash service and user token.
+//
+// It takes a Gin context as a parameter.
+// It returns a TrashService and a Token.
+func getServiceAndUser(c *gin.Context) (services.TrashService, models.Token) {
+	service := services.TrashService{DB: services2.GetDBConnection()}
+	user := services2.GetUserFromContext(c)
+
+	return service, user
+}
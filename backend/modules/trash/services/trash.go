@@ -0,0 +1,192 @@
+package services
+
+import (
+	models2 "backend/modules/categories/models"
+	services2 "backend/modules/categories/services"
+	"backend/modules/passwords/models"
+	"backend/modules/passwords/services"
+	"backend/services/config"
+	"gorm.io/gorm"
+	"time"
+)
+
+type TrashService struct {
+	DB *gorm.DB
+}
+
+type Trash struct {
+	Categories []services2.TrashedCategory `json:"categories"`
+	Passwords  []services.TrashedPassword  `json:"passwords"`
+}
+
+// getPasswordService returns a PasswordService.
+func (s *TrashService) getPasswordService() services.PasswordService {
+	return services.PasswordService{DB: s.DB}
+}
+
+// getCategoryService returns a CategoryService.
+func (s *TrashService) getCategoryService() services2.CategoryService {
+	return services2.CategoryService{DB: s.DB}
+}
+
+// GetTrash returns the deleted categories and passwords of a given user.
+//
+// Parameters:
+// - userId: the ID of the user.
+//
+// Returns:
+// - Trash: the deleted items, most recently deleted first.
+// - error: any error that occurred during the retrieval process.
+func (s *TrashService) GetTrash(userId uint) (Trash, error) {
+	categoryService := s.getCategoryService()
+
+	categories, err := categoryService.GetTrashedCategories(userId)
+	if err != nil {
+		return Trash{}, err
+	}
+
+	passwordService := s.getPasswordService()
+
+	passwords, err := passwordService.GetTrashedPasswords(userId)
+	if err != nil {
+		return Trash{}, err
+	}
+
+	return Trash{Categories: categories, Passwords: passwords}, nil
+}
+
+// RestorePassword restores a deleted password, and its category if it was deleted too.
+//
+// Parameters:
+// - id: the ID of the password.
+// - userId: the ID of the user.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound if the password is not in the trash of the user, or any update error.
+func (s *TrashService) RestorePassword(id, userId uint) error {
+	passwordService := s.getPasswordService()
+
+	return passwordService.RestorePassword(id, userId)
+}
+
+// RestoreCategory restores a deleted category.
+//
+// Parameters:
+// - id: the ID of the category.
+// - userId: the ID of the user.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound if the category is not in the trash of the user, or any update error.
+func (s *TrashService) RestoreCategory(id, userId uint) error {
+	categoryService := s.getCategoryService()
+
+	return categoryService.RestoreCategory(id, userId)
+}
+
+// DeletePassword permanently deletes a password from the trash.
+//
+// Parameters:
+// - id: the ID of the password.
+// - userId: the ID of the user.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound if the password is not in the trash of the user, or any deletion error.
+func (s *TrashService) DeletePassword(id, userId uint) error {
+	passwordService := s.getPasswordService()
+
+	return passwordService.PurgePassword(id, userId)
+}
+
+// DeleteCategory permanently deletes a category from the trash, its passwords are kept without a category.
+//
+// Parameters:
+// - id: the ID of the category.
+// - userId: the ID of the user.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound if the category is not in the trash of the user, or any deletion error.
+func (s *TrashService) DeleteCategory(id, userId uint) error {
+	categoryService := s.getCategoryService()
+
+	return categoryService.PurgeCategory(id, userId)
+}
+
+// Empty permanently deletes everything in the trash of a given user.
+//
+// Parameters:
+// - userId: the ID of the user.
+//
+// Returns:
+// - int: the number of deleted items.
+// - error: any deletion error.
+func (s *TrashService) Empty(userId uint) (int, error) {
+	trash, err := s.GetTrash(userId)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+
+	for _, password := range trash.Passwords {
+		if err := s.DeletePassword(password.ID, userId); err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	for _, category := range trash.Categories {
+		if err := s.DeleteCategory(category.ID, userId); err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+// Purge permanently deletes the items of all users that have been in the trash longer than the retention period.
+//
+// TRASH_RETENTION_DAYS sets the retention period (30 by default), 0 keeps deleted items forever.
+//
+// It does not take any parameters.
+// It returns the number of deleted items and an error if a deletion fails.
+func (s *TrashService) Purge() (int, error) {
+	retention := config.GetDays("TRASH_RETENTION_DAYS", 30)
+	if retention <= 0 {
+		return 0, nil
+	}
+
+	before := time.Now().Add(-retention)
+
+	passwordModel := models.PasswordModel{DB: s.DB}
+
+	passwords, err := passwordModel.GetExpired(before)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+
+	for _, password := range passwords {
+		if err := s.DeletePassword(password.ID, password.UserID); err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	categoryModel := models2.CategoryModel{DB: s.DB}
+
+	categories, err := categoryModel.GetExpired(before)
+	if err != nil {
+		return count, err
+	}
+
+	for _, category := range categories {
+		if err := s.DeleteCategory(category.ID, category.UserID); err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	return count, nil
+}
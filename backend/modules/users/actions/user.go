@@ -1,9 +1,12 @@
 package actions
 
 import (
+	"backend/modules/users/models"
 	"backend/modules/users/services"
 	services2 "backend/services"
+	"errors"
 	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
 	"net/http"
 )
 
@@ -23,9 +26,41 @@ type UserLoginRequest struct {
 }
 
 type GetUserResponse struct {
-	ID    uint   `json:"id"`
-	Name  string `json:"name"`
-	Email string `json:"email"`
+	ID           uint   `json:"id"`
+	Name         string `json:"name"`
+	Email        string `json:"email"`
+	VaultMode    string `json:"vault_mode"`
+	SearchLogins bool   `json:"search_logins"`
+}
+
+type PreLoginRequest struct {
+	Email string `json:"email" binding:"required"`
+}
+
+type KdfResponse struct {
+	Kdf         string `json:"kdf"`
+	Salt        string `json:"salt"`
+	Memory      uint32 `json:"memory"`
+	Iterations  uint32 `json:"iterations"`
+	Parallelism uint8  `json:"parallelism"`
+}
+
+type VaultResponse struct {
+	KdfResponse
+	VaultMode    string `json:"vault_mode"`
+	ProtectedKey string `json:"protected_key"`
+	// Entries still encrypted by the server, the client should save them again with its own key.
+	LegacyEntries int64 `json:"legacy_entries"`
+}
+
+type UpdateVaultRequest struct {
+	Password     string `json:"password" binding:"required"`
+	NewPassword  string `json:"new_password" binding:"required"`
+	Salt         string `json:"salt" binding:"required"`
+	Memory       uint32 `json:"memory" binding:"required"`
+	Iterations   uint32 `json:"iterations" binding:"required"`
+	Parallelism  uint8  `json:"parallelism" binding:"required"`
+	ProtectedKey string `json:"protected_key" binding:"required"`
 }
 
 // UserRegister is a function that handles the registration of a user.
@@ -113,12 +148,159 @@ func UserLogin(c *gin.Context) {
 func GetUser(c *gin.Context) {
 	user := services2.GetUserFromContext(c)
 	c.JSON(http.StatusOK, GetUserResponse{
-		ID:    user.User.ID,
-		Name:  user.User.Name,
-		Email: user.User.Email,
+		ID:           user.User.ID,
+		Name:         user.User.Name,
+		Email:        user.User.Email,
+		VaultMode:    user.User.VaultMode,
+		SearchLogins: user.User.SearchLogins,
+	})
+}
+
+// PreLogin returns the key derivation parameters the client needs before logging in.
+//
+// In the zero-knowledge vault mode the client derives its master key with Argon2id
+// from the master password and these parameters, and logs in with a hash of that key.
+// The response has the same shape for every email and does not include the vault mode,
+// so it reveals neither whether an account exists nor how it is set up. Clients log in
+// with the derived hash first and fall back to the master password, then read the vault
+// mode from GET /user/vault.
+// @Summary Pre-login
+// @Description Returns the Argon2id parameters for an email, the same response shape is returned for unknown emails
+// @Tags Users
+// @Accept  json
+// @Produce  json
+// @Param   preLoginRequest  body    PreLoginRequest  true  "User email"
+// @Success 200 {object} KdfResponse
+// @Failure 400 {object} services2.ErrorResponse "{"error": "error"}"
+// @Failure 500 {object} services2.ErrorResponse "{"error": "error"}"
+// @Router /user/prelogin [post]
+func PreLogin(c *gin.Context) {
+	var request PreLoginRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	userService := getService()
+
+	kdf, err := userService.PreLogin(request.Email)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, toKdfResponse(kdf))
+}
+
+// GetVault returns the vault settings of the logged-in user, including the protected key.
+//
+// Parameters:
+//
+//	c: a pointer to the gin.Context object.
+//
+// @Summary Get vault settings
+// @Description Returns the vault mode, Argon2id parameters and protected symmetric key of the user,
+// @Description and the number of entries still encrypted by the server
+// @Tags Users
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Success 200 {object} VaultResponse
+// @Failure 500 {object} services2.ErrorResponse "{"error": "error"}"
+// @Router /user/vault [get]
+func GetVault(c *gin.Context) {
+	user := services2.GetUserFromContext(c)
+	userService := getService()
+
+	legacyEntries, err := userService.CountLegacyEntries(user.User.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, VaultResponse{
+		KdfResponse:   toKdfResponse(user.User.Kdf),
+		VaultMode:     user.User.VaultMode,
+		ProtectedKey:  user.User.ProtectedKey,
+		LegacyEntries: legacyEntries,
 	})
 }
 
+// UpdateVault enables the zero-knowledge vault mode or rotates the master key.
+//
+// The request must contain the current login password for re-authentication and the new
+// login password derived by the client. From now on new entries are stored as client ciphertext.
+// Existing entries stay encrypted by the server until the client saves them again, the response
+// reports how many are left in legacy_entries. The other sessions of the user are signed out.
+// @Summary Update vault settings
+// @Description Enables the zero-knowledge vault mode or changes the master key of the user.
+// @Description All other sessions of the user are signed out.
+// @Tags Users
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   updateVaultRequest  body    UpdateVaultRequest  true  "Vault settings"
+// @Success 200 {object} VaultResponse
+// @Failure 400 {object} services2.ErrorResponse "{"error": "error"}"
+// @Failure 401 {object} services2.ErrorResponse "{"error": "error"}"
+// @Failure 500 {object} services2.ErrorResponse "{"error": "error"}"
+// @Router /user/vault [put]
+func UpdateVault(c *gin.Context) {
+	var request UpdateVaultRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	user := services2.GetUserFromContext(c)
+	userService := getService()
+
+	kdf := models.KdfParams{
+		Salt:        request.Salt,
+		Memory:      request.Memory,
+		Iterations:  request.Iterations,
+		Parallelism: request.Parallelism,
+	}
+
+	err := userService.UpdateVault(user.User.ID, user.ID, request.Password, request.NewPassword, kdf, request.ProtectedKey)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, bcrypt.ErrMismatchedHashAndPassword):
+			status = http.StatusUnauthorized
+		case errors.Is(err, services.ErrWeakKdfParams), errors.Is(err, services.ErrInvalidProtectedKey):
+			status = http.StatusBadRequest
+		}
+
+		c.JSON(status, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	legacyEntries, err := userService.CountLegacyEntries(user.User.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, services2.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, VaultResponse{
+		KdfResponse:   toKdfResponse(kdf),
+		VaultMode:     models.VaultModeZeroKnowledge,
+		ProtectedKey:  request.ProtectedKey,
+		LegacyEntries: legacyEntries,
+	})
+}
+
+// toKdfResponse builds the response with the Argon2id parameters.
+func toKdfResponse(kdf models.KdfParams) KdfResponse {
+	return KdfResponse{
+		Kdf:         "argon2id",
+		Salt:        kdf.Salt,
+		Memory:      kdf.Memory,
+		Iterations:  kdf.Iterations,
+		Parallelism: kdf.Parallelism,
+	}
+}
+
 // getService returns an instance of the UserService.
 //
 // It does not take any parameters.
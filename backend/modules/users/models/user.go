@@ -2,16 +2,52 @@ package models
 
 import (
 	"backend/modules/users/services/tokens"
+	"backend/services/encryption"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
+	"strings"
+)
+
+const (
+	VaultModeServer        = "server"
+	VaultModeZeroKnowledge = "zero_knowledge"
+)
+
+// Default Argon2id parameters returned for accounts without a zero-knowledge vault.
+const (
+	DefaultKdfMemory      = 64 * 1024
+	DefaultKdfIterations  = 3
+	DefaultKdfParallelism = 4
 )
 
 type User struct {
 	gorm.Model
-	Name     string `gorm:"not null"`
-	Email    string `gorm:"unique;not null"`
-	Password string `gorm:"not null"`
-	Pin      int    `gorm:"nullable"`
+	Name         string    `gorm:"not null"`
+	Email        string    `gorm:"unique;not null"`
+	Password     string    `gorm:"not null"`
+	Pin          int       `gorm:"nullable"`
+	DataKey      string    `gorm:"nullable"`
+	VaultMode    string    `gorm:"not null;default:server"`
+	Kdf          KdfParams `gorm:"embedded;embeddedPrefix:kdf_"`
+	ProtectedKey string    `gorm:"nullable"`
+	// SearchLogins allows the logins of server-encrypted entries to be copied in plaintext to the search index.
+	SearchLogins bool `gorm:"not null;default:false"`
+	// PublicKey is the X25519 key entry keys are sealed to when passwords are shared with the user.
+	PublicKey string `gorm:"not null;default:''"`
+	// PrivateKey is the matching private key encrypted with the data-encryption key of the user.
+	PrivateKey string `gorm:"not null;default:''"`
+}
+
+// KdfParams are the Argon2id parameters the client uses to derive its key from the master password.
+type KdfParams struct {
+	Salt        string `gorm:"nullable"`
+	Memory      uint32 `gorm:"nullable"`
+	Iterations  uint32 `gorm:"nullable"`
+	Parallelism uint8  `gorm:"nullable"`
 }
 
 type Token struct {
@@ -41,10 +77,16 @@ func (u *UserModel) CreateUser(name, email, password string) (Token, error) {
 		return Token{}, err
 	}
 
+	dataKey, err := u.newDataKey()
+	if err != nil {
+		return Token{}, err
+	}
+
 	user := User{
 		Name:     name,
 		Email:    email,
 		Password: hashedPassword,
+		DataKey:  dataKey,
 	}
 
 	result := u.DB.Create(&user)
@@ -93,6 +135,282 @@ func (u *UserModel) CheckToken(token string) (Token, error) {
 	return tokenObject, nil
 }
 
+// GetKdfParams returns the key derivation parameters for the given email.
+//
+// Unknown emails and server-mode accounts get the default parameters with a salt derived from the email,
+// so the response does not reveal whether an account exists or which vault mode it uses.
+//
+// Parameters:
+// - email: the email of the user.
+//
+// Returns:
+// - KdfParams: the key derivation parameters.
+// - error: an error if the parameters cannot be computed.
+func (u *UserModel) GetKdfParams(email string) (KdfParams, error) {
+	var user User
+
+	err := u.DB.Where("email = ?", email).First(&user).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return KdfParams{}, err
+	}
+
+	if err == nil && user.VaultMode == VaultModeZeroKnowledge {
+		return user.Kdf, nil
+	}
+
+	key, err := encryption.DeriveKey("prelogin-salt")
+	if err != nil {
+		return KdfParams{}, err
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(strings.ToLower(email)))
+
+	return KdfParams{
+		Salt:        base64.StdEncoding.EncodeToString(mac.Sum(nil)[:16]),
+		Memory:      DefaultKdfMemory,
+		Iterations:  DefaultKdfIterations,
+		Parallelism: DefaultKdfParallelism,
+	}, nil
+}
+
+// UpdateVault switches a user to the zero-knowledge vault mode or changes its master key.
+//
+// The client sends the hash it derived from the master password as the new login password,
+// so the server never sees the master password itself. All other sessions of the user are signed out.
+//
+// Parameters:
+// - userID: the ID of the user.
+// - tokenID: the ID of the token of the request, which stays valid.
+// - password: the current login password, used for re-authentication.
+// - newPassword: the new login password derived by the client.
+// - kdf: the Argon2id parameters used by the client.
+// - protectedKey: the vault key encrypted by the client with the master key.
+//
+// Returns:
+// - error: bcrypt.ErrMismatchedHashAndPassword if the current password is wrong, or any update error.
+func (u *UserModel) UpdateVault(userID, tokenID uint, password, newPassword string, kdf KdfParams, protectedKey string) error {
+	var user User
+
+	err := u.DB.Where("id = ?", userID).First(&user).Error
+	if err != nil {
+		return err
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
+	if err != nil {
+		return err
+	}
+
+	hashedPassword, err := u.hashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+
+	return u.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"password":        hashedPassword,
+			"vault_mode":      VaultModeZeroKnowledge,
+			"kdf_salt":        kdf.Salt,
+			"kdf_memory":      kdf.Memory,
+			"kdf_iterations":  kdf.Iterations,
+			"kdf_parallelism": kdf.Parallelism,
+			"protected_key":   protectedKey,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Where("user_id = ? AND id <> ?", userID, tokenID).Delete(&Token{}).Error
+	})
+}
+
+// CheckPassword re-authenticates a user with the login password before a sensitive operation.
+//
+// Parameters:
+// - userID: the ID of the user.
+// - password: the login password.
+//
+// Returns:
+// - error: bcrypt.ErrMismatchedHashAndPassword if the password is wrong, or any retrieval error.
+func (u *UserModel) CheckPassword(userID uint, password string) error {
+	var user User
+
+	err := u.DB.Select("id", "password").Where("id = ?", userID).First(&user).Error
+	if err != nil {
+		return err
+	}
+
+	return bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
+}
+
+// ResetPassword replaces the login password of a user and signs out all their sessions.
+//
+// Parameters:
+// - userID: the ID of the user.
+// - password: the new login password.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound if the user does not exist, or any update error.
+func (u *UserModel) ResetPassword(userID uint, password string) error {
+	hashedPassword, err := u.hashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	return u.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&User{}).Where("id = ?", userID).Update("password", hashedPassword)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return tx.Where("user_id = ?", userID).Delete(&Token{}).Error
+	})
+}
+
+// GetCipher returns the cipher built from the data-encryption key of a user.
+//
+// Parameters:
+// - userID: the ID of the user.
+//
+// Returns:
+// - encryption.Cipher: the cipher for the user's secrets.
+// - error: an error if the key cannot be loaded, created or unwrapped.
+func (u *UserModel) GetCipher(userID uint) (encryption.Cipher, error) {
+	key, err := u.GetDataKey(userID)
+	if err != nil {
+		return encryption.Cipher{}, err
+	}
+
+	return encryption.NewCipher(key)
+}
+
+// GetDataKey returns the unwrapped data-encryption key of a user.
+//
+// Users created before encryption was introduced get their key generated on first use.
+//
+// Parameters:
+// - userID: the ID of the user.
+//
+// Returns:
+// - []byte: the data-encryption key.
+// - error: an error if the key cannot be loaded, created or unwrapped.
+func (u *UserModel) GetDataKey(userID uint) ([]byte, error) {
+	var user User
+
+	err := u.DB.Select("id", "data_key").Where("id = ?", userID).First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if user.DataKey == "" {
+		dataKey, err := u.newDataKey()
+		if err != nil {
+			return nil, err
+		}
+
+		// Only the first concurrent request wins, the others re-read its key.
+		err = u.DB.Model(&User{}).
+			Where("id = ? AND (data_key IS NULL OR data_key = '')", userID).
+			Update("data_key", dataKey).Error
+		if err != nil {
+			return nil, err
+		}
+
+		err = u.DB.Select("id", "data_key").Where("id = ?", userID).First(&user).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return encryption.UnwrapKey(user.DataKey)
+}
+
+// GetKeyPair returns the sharing key pair of a user.
+//
+// Users get their key pair generated on first use, the private key is stored encrypted with their cipher.
+// It is not encrypted with the vault key: the server never holds that key, and it seals entry keys to recipients
+// and opens them when a recipient reads a share. The key pair keeps the entry keys bound to each user and lets
+// revocation rotate them; it does not protect shared entries from the server, which is why client-encrypted entries
+// cannot be shared.
+//
+// Parameters:
+// - userID: the ID of the user.
+//
+// Returns:
+// - []byte: the X25519 public key.
+// - []byte: the X25519 private key.
+// - error: an error if the keys cannot be loaded, created or decrypted.
+func (u *UserModel) GetKeyPair(userID uint) ([]byte, []byte, error) {
+	cipher, err := u.GetCipher(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var user User
+
+	err = u.DB.Select("id", "public_key", "private_key").Where("id = ?", userID).First(&user).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if user.PublicKey == "" {
+		publicKey, privateKey, err := encryption.GenerateKeyPair()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		encrypted, err := cipher.EncryptBytes(privateKey, "private_key")
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// Only the first concurrent request wins, the others re-read its keys.
+		err = u.DB.Model(&User{}).
+			Where("id = ? AND public_key = ''", userID).
+			Updates(map[string]interface{}{
+				"public_key":  base64.StdEncoding.EncodeToString(publicKey),
+				"private_key": encrypted,
+			}).Error
+		if err != nil {
+			return nil, nil, err
+		}
+
+		err = u.DB.Select("id", "public_key", "private_key").Where("id = ?", userID).First(&user).Error
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	publicKey, err := base64.StdEncoding.DecodeString(user.PublicKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	privateKey, err := cipher.DecryptBytes(user.PrivateKey, "private_key")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return publicKey, privateKey, nil
+}
+
+// newDataKey generates a random data-encryption key wrapped by the server key.
+//
+// It returns the wrapped key ready to be stored on the user.
+func (u *UserModel) newDataKey() (string, error) {
+	key, err := encryption.GenerateKey()
+	if err != nil {
+		return "", err
+	}
+
+	return encryption.WrapKey(key)
+}
+
 // getToken generates a token for the given user and saves it to the database.
 //
 // The user parameter is the user for whom the token is being generated.
@@ -1,10 +1,25 @@
 package services
 
 import (
+	models2 "backend/modules/passwords/models"
 	"backend/modules/users/models"
+	"encoding/base64"
+	"errors"
 	"gorm.io/gorm"
 )
 
+// Minimal Argon2id parameters accepted for a zero-knowledge vault, based on the OWASP recommendations.
+const (
+	minKdfMemory     = 19 * 1024
+	minKdfIterations = 2
+	minKdfSaltSize   = 16
+)
+
+var (
+	ErrWeakKdfParams       = errors.New("argon2id parameters are too weak")
+	ErrInvalidProtectedKey = errors.New("protected key is required")
+)
+
 type UserService struct {
 	DB *gorm.DB
 }
@@ -60,3 +75,72 @@ func (s *UserService) GetUserByToken(token string) (models.Token, error) {
 
 	return userModel.CheckToken(token)
 }
+
+// PreLogin returns the key derivation parameters for an email.
+//
+// Parameters:
+// - email: the email the client is about to log in with.
+//
+// Returns:
+// - models.KdfParams: the Argon2id parameters.
+// - error: an error if the parameters cannot be computed.
+func (s *UserService) PreLogin(email string) (models.KdfParams, error) {
+	userModel := s.getModel()
+
+	return userModel.GetKdfParams(email)
+}
+
+// UpdateVault enables the zero-knowledge vault mode or changes the master key of a user.
+//
+// All sessions of the user except the current one are signed out.
+//
+// Parameters:
+// - userID: the ID of the user.
+// - tokenID: the ID of the token of the request.
+// - password: the current login password.
+// - newPassword: the new login password derived by the client.
+// - kdf: the Argon2id parameters used by the client.
+// - protectedKey: the vault key encrypted by the client.
+//
+// Returns:
+// - error: ErrWeakKdfParams, ErrInvalidProtectedKey or any update error.
+func (s *UserService) UpdateVault(userID, tokenID uint, password, newPassword string, kdf models.KdfParams, protectedKey string) error {
+	salt, err := base64.StdEncoding.DecodeString(kdf.Salt)
+	if err != nil || len(salt) < minKdfSaltSize {
+		return ErrWeakKdfParams
+	}
+
+	if kdf.Memory < minKdfMemory || kdf.Iterations < minKdfIterations || kdf.Parallelism == 0 {
+		return ErrWeakKdfParams
+	}
+
+	if protectedKey == "" {
+		return ErrInvalidProtectedKey
+	}
+
+	userModel := s.getModel()
+
+	return userModel.UpdateVault(userID, tokenID, password, newPassword, kdf, protectedKey)
+}
+
+// CountLegacyEntries counts the entries of a user that are still encrypted by the server.
+//
+// Entries created before the switch to the zero-knowledge vault mode stay readable by the server
+// until the client saves them again with its own key. Trashed entries are counted as well,
+// since they can be restored.
+//
+// Parameters:
+// - userID: the ID of the user.
+//
+// Returns:
+// - int64: the number of server-encrypted entries.
+// - error: an error if the entries cannot be counted.
+func (s *UserService) CountLegacyEntries(userID uint) (int64, error) {
+	var count int64
+
+	err := s.DB.Unscoped().Model(&models2.Password{}).
+		Where("user_id = ? AND encryption = ?", userID, models2.EncryptionServer).
+		Count(&count).Error
+
+	return count, err
+}
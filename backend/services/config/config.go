@@ -0,0 +1,60 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GetInt returns an integer environment variable.
+//
+// Parameters:
+// - name: the name of the environment variable.
+// - fallback: the value returned when the variable is missing or invalid.
+//
+// Returns the configured value or the fallback.
+func GetInt(name string, fallback int) int {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("invalid %s=%q, using %d", name, value, fallback)
+		return fallback
+	}
+
+	return number
+}
+
+// GetDays returns an environment variable holding a number of days as a duration.
+//
+// Parameters:
+// - name: the name of the environment variable.
+// - fallback: the number of days used when the variable is missing or invalid.
+//
+// Returns the duration, zero when the variable is set to 0.
+func GetDays(name string, fallback int) time.Duration {
+	return time.Duration(GetInt(name, fallback)) * 24 * time.Hour
+}
+
+// GetList returns a comma-separated environment variable as a list, blank items are skipped.
+//
+// Parameters:
+// - name: the name of the environment variable.
+//
+// Returns the items, nil when the variable is missing or empty.
+func GetList(name string) []string {
+	var items []string
+
+	for _, item := range strings.Split(os.Getenv(name), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
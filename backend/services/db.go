@@ -1,9 +1,18 @@
 package services
 
 import (
+	models7 "backend/modules/audit/models"
+	models4 "backend/modules/breaches/models"
 	models2 "backend/modules/categories/models"
+	models9 "backend/modules/emergency/models"
+	models6 "backend/modules/notifications/models"
+	models8 "backend/modules/organizations/models"
 	models3 "backend/modules/passwords/models"
+	services2 "backend/modules/passwords/services"
+	models10 "backend/modules/sends/models"
+	models5 "backend/modules/tags/models"
 	"backend/modules/users/models"
+	"backend/services/encryption"
 	"fmt"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -42,11 +51,74 @@ func GetDBConnection() *gorm.DB {
 	return dbConnect
 }
 
+// Migrations migrates the database schema and the stored data.
+//
+// Besides the schema migrations it moves the legacy additional information to custom fields,
+// encrypts passwords saved before encryption was introduced and binds older ciphertexts to their row, indexes them for search, and scores and fingerprints entries saved before strength estimation,
+// so it panics when the ENCRYPTION_KEY environment variable is missing or invalid.
 func Migrations() {
 	db := GetDBConnection()
 
+	if err := encryption.CheckKeyEncryptionKey(); err != nil {
+		panic("invalid encryption key: " + err.Error())
+	}
+
 	db.AutoMigrate(&models.User{})
 	db.AutoMigrate(&models.Token{})
 	db.AutoMigrate(&models2.Category{})
+	db.AutoMigrate(&models5.Tag{})
 	db.AutoMigrate(&models3.Password{})
+	db.AutoMigrate(&models3.PasswordField{})
+	db.AutoMigrate(&models3.PasswordURI{})
+	db.AutoMigrate(&models3.GeneratorPreset{})
+	db.AutoMigrate(&models3.PasswordRevision{})
+	db.AutoMigrate(&models3.Attachment{})
+	db.AutoMigrate(&models3.PasswordSearch{})
+	db.AutoMigrate(&models3.SharedPassword{})
+	db.AutoMigrate(&models3.PasswordShare{})
+	db.AutoMigrate(&models3.AccessLog{})
+	db.AutoMigrate(&models8.Organization{})
+	db.AutoMigrate(&models8.OrganizationMember{})
+	db.AutoMigrate(&models8.OrganizationGroup{})
+	db.AutoMigrate(&models8.Collection{})
+	db.AutoMigrate(&models8.CollectionMember{})
+	db.AutoMigrate(&models8.CollectionGroup{})
+	db.AutoMigrate(&models6.Notification{})
+	db.AutoMigrate(&models7.AuditEvent{})
+	db.AutoMigrate(&models9.EmergencyContact{})
+	db.AutoMigrate(&models10.Send{})
+	db.AutoMigrate(&models4.BreachRange{})
+
+	passwordModel := models3.PasswordModel{DB: db}
+	if err := passwordModel.MigrateAdditional(); err != nil {
+		panic("failed to migrate additional information to custom fields: " + err.Error())
+	}
+
+	if err := passwordModel.EncryptLegacy(); err != nil {
+		panic("failed to encrypt legacy passwords: " + err.Error())
+	}
+
+	attachmentModel := models3.AttachmentModel{DB: db}
+	if err := attachmentModel.BindLegacy(); err != nil {
+		panic("failed to bind legacy attachments: " + err.Error())
+	}
+
+	shareModel := models3.ShareModel{DB: db}
+	if err := shareModel.BindLegacy(); err != nil {
+		panic("failed to bind legacy shares: " + err.Error())
+	}
+
+	searchModel := models3.SearchModel{DB: db}
+	if err := searchModel.Migrate(); err != nil {
+		panic("failed to build the search index: " + err.Error())
+	}
+
+	passwordService := services2.PasswordService{DB: db}
+	if err := passwordService.EstimateMissingStrength(); err != nil {
+		panic("failed to estimate password strength: " + err.Error())
+	}
+
+	if err := passwordService.FingerprintMissing(); err != nil {
+		panic("failed to fingerprint passwords: " + err.Error())
+	}
 }
@@ -0,0 +1,250 @@
+package encryption
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/hkdf"
+	"io"
+	"os"
+	"sync"
+)
+
+// Every encrypted value starts with this header followed by the format version,
+// so the layout can change later without breaking values that are already stored.
+var magic = []byte("SMP")
+
+// Prefix starts the base64 encoding of every encrypted value, so queries can find legacy plaintext values.
+var Prefix = base64.StdEncoding.EncodeToString(magic)
+
+const (
+	Version1  byte = 1
+	KeySize        = 32
+	nonceSize      = 12
+)
+
+var (
+	ErrInvalidKey        = errors.New("encryption key must be 32 bytes")
+	ErrInvalidCiphertext = errors.New("invalid ciphertext")
+	ErrUnknownVersion    = errors.New("unknown ciphertext version")
+)
+
+var (
+	kek     []byte
+	kekErr  error
+	kekOnce sync.Once
+)
+
+// Cipher encrypts and decrypts values with a single data-encryption key.
+type Cipher struct {
+	key []byte
+}
+
+// NewCipher returns a Cipher for the given 32 byte key.
+//
+// key: the raw AES-256 key.
+// Returns the Cipher or ErrInvalidKey.
+func NewCipher(key []byte) (Cipher, error) {
+	if len(key) != KeySize {
+		return Cipher{}, ErrInvalidKey
+	}
+
+	return Cipher{key: key}, nil
+}
+
+// GenerateKey returns a new random 32 byte key.
+//
+// It does not take any parameters.
+// It returns the key and an error if the random source fails.
+func GenerateKey() ([]byte, error) {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// WrapKey encrypts a data-encryption key with the server key-encryption key.
+//
+// key: the raw data-encryption key.
+// Returns the wrapped key as a string ready to be stored.
+func WrapKey(key []byte) (string, error) {
+	master, err := keyEncryptionKey()
+	if err != nil {
+		return "", err
+	}
+
+	return Cipher{key: master}.EncryptBytes(key, "kek")
+}
+
+// UnwrapKey decrypts a data-encryption key wrapped by WrapKey.
+//
+// wrapped: the stored wrapped key.
+// Returns the raw data-encryption key.
+func UnwrapKey(wrapped string) ([]byte, error) {
+	master, err := keyEncryptionKey()
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := Cipher{key: master}.DecryptBytes(wrapped, "kek")
+	if err != nil {
+		return nil, err
+	}
+
+	if len(key) != KeySize {
+		return nil, ErrInvalidKey
+	}
+
+	return key, nil
+}
+
+// Encrypt encrypts a string with AES-256-GCM.
+//
+// Parameters:
+// - plaintext: the value to encrypt.
+// - aad: additional authenticated data binding the ciphertext to its context, e.g. the table, column and ID of its row.
+//
+// Returns the versioned ciphertext encoded with base64.
+func (c Cipher) Encrypt(plaintext, aad string) (string, error) {
+	return c.EncryptBytes([]byte(plaintext), aad)
+}
+
+// Decrypt decrypts a value produced by Encrypt.
+//
+// Parameters:
+// - ciphertext: the stored value.
+// - aad: the same additional authenticated data used for encryption.
+//
+// Returns the plaintext.
+func (c Cipher) Decrypt(ciphertext, aad string) (string, error) {
+	plaintext, err := c.DecryptBytes(ciphertext, aad)
+
+	return string(plaintext), err
+}
+
+// EncryptBytes encrypts raw bytes with AES-256-GCM.
+//
+// The result layout is magic | version | nonce | sealed data, encoded with base64.
+func (c Cipher) EncryptBytes(plaintext []byte, aad string) (string, error) {
+	gcm, err := c.gcm()
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, nonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	header := append(append([]byte{}, magic...), Version1)
+	out := append(header, nonce...)
+	out = gcm.Seal(out, nonce, plaintext, []byte(aad))
+
+	return base64.StdEncoding.EncodeToString(out), nil
+}
+
+// DecryptBytes decrypts a value produced by EncryptBytes.
+func (c Cipher) DecryptBytes(ciphertext, aad string) ([]byte, error) {
+	raw, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil || len(raw) < len(magic)+1+nonceSize || !bytes.HasPrefix(raw, magic) {
+		return nil, ErrInvalidCiphertext
+	}
+
+	if raw[len(magic)] != Version1 {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownVersion, raw[len(magic)])
+	}
+
+	gcm, err := c.gcm()
+	if err != nil {
+		return nil, err
+	}
+
+	body := raw[len(magic)+1:]
+	plaintext, err := gcm.Open(nil, body[:nonceSize], body[nonceSize:], []byte(aad))
+	if err != nil {
+		return nil, ErrInvalidCiphertext
+	}
+
+	return plaintext, nil
+}
+
+// IsEncrypted reports whether a stored value carries the encryption header.
+//
+// It is used to tell legacy plaintext values apart from encrypted ones.
+func IsEncrypted(value string) bool {
+	raw, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return false
+	}
+
+	return len(raw) > len(magic)+1+nonceSize && bytes.HasPrefix(raw, magic)
+}
+
+// gcm builds the AES-GCM AEAD for the key of the cipher.
+func (c Cipher) gcm() (cipher.AEAD, error) {
+	if len(c.key) != KeySize {
+		return nil, ErrInvalidKey
+	}
+
+	block, err := aes.NewCipher(c.key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// keyEncryptionKey loads the server key-encryption key from the ENCRYPTION_KEY environment variable.
+//
+// The variable must hold 32 bytes encoded with standard base64, e.g. the output of `openssl rand -base64 32`.
+func keyEncryptionKey() ([]byte, error) {
+	kekOnce.Do(func() {
+		value := os.Getenv("ENCRYPTION_KEY")
+		if value == "" {
+			kekErr = errors.New("ENCRYPTION_KEY is not set")
+			return
+		}
+
+		kek, kekErr = base64.StdEncoding.DecodeString(value)
+		if kekErr == nil && len(kek) != KeySize {
+			kekErr = ErrInvalidKey
+		}
+	})
+
+	return kek, kekErr
+}
+
+// DeriveKey derives a 32 byte key for a specific purpose from the server key-encryption key.
+//
+// info: a unique label of the purpose, e.g. "prelogin-salt".
+// Returns the derived key, the same label always yields the same key.
+func DeriveKey(info string) ([]byte, error) {
+	master, err := keyEncryptionKey()
+	if err != nil {
+		return nil, err
+	}
+
+	key := make([]byte, KeySize)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, master, nil, []byte(info)), key); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// CheckKeyEncryptionKey verifies that the server key-encryption key is configured.
+//
+// It does not take any parameters.
+// It returns an error describing the configuration problem, if any.
+func CheckKeyEncryptionKey() error {
+	_, err := keyEncryptionKey()
+
+	return err
+}
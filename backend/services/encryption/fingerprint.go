@@ -0,0 +1,31 @@
+package encryption
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"golang.org/x/crypto/hkdf"
+	"io"
+)
+
+// Fingerprint returns a keyed HMAC-SHA256 fingerprint of a value.
+//
+// The HMAC key is derived from the data-encryption key, so equal values of the same user have equal
+// fingerprints while the value cannot be recovered or compared across users.
+//
+// Parameters:
+// - value: the plaintext value.
+// - purpose: a label separating the fingerprints of different kinds of values, e.g. "password".
+//
+// Returns the fingerprint encoded in base64, or an error if the key cannot be derived.
+func (c Cipher) Fingerprint(value, purpose string) (string, error) {
+	key := make([]byte, KeySize)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, c.key, nil, []byte("fingerprint:"+purpose)), key); err != nil {
+		return "", err
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(value))
+
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil)), nil
+}
@@ -0,0 +1,143 @@
+package encryption
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"golang.org/x/crypto/hkdf"
+	"io"
+)
+
+// publicKeySize is the size of an X25519 public key.
+const publicKeySize = 32
+
+// GenerateKeyPair returns a new X25519 key pair used to seal keys for a user.
+//
+// It does not take any parameters.
+// It returns the public key, the private key and an error if the random source fails.
+func GenerateKeyPair() ([]byte, []byte, error) {
+	private, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return private.PublicKey().Bytes(), private.Bytes(), nil
+}
+
+// Seal encrypts a value, typically a key, so only the owner of the private key can open it.
+//
+// An ephemeral X25519 key agrees on a secret with the public key, the AES-256-GCM key is derived from it
+// with HKDF-SHA256 and both public keys. The layout is magic | version | ephemeral public key | nonce | sealed data,
+// encoded with base64.
+//
+// Parameters:
+// - publicKey: the X25519 public key of the recipient.
+// - plaintext: the value to seal.
+// - aad: additional authenticated data binding the sealed value to its context.
+//
+// Returns the sealed value.
+func Seal(publicKey, plaintext []byte, aad string) (string, error) {
+	recipient, err := ecdh.X25519().NewPublicKey(publicKey)
+	if err != nil {
+		return "", ErrInvalidKey
+	}
+
+	ephemeral, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return "", err
+	}
+
+	secret, err := ephemeral.ECDH(recipient)
+	if err != nil {
+		return "", err
+	}
+
+	gcm, err := sealCipher(secret, ephemeral.PublicKey().Bytes(), publicKey)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, nonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	out := append(append([]byte{}, magic...), Version1)
+	out = append(out, ephemeral.PublicKey().Bytes()...)
+	out = append(out, nonce...)
+	out = gcm.Seal(out, nonce, plaintext, []byte(aad))
+
+	return base64.StdEncoding.EncodeToString(out), nil
+}
+
+// Open decrypts a value sealed by Seal.
+//
+// Parameters:
+// - privateKey: the X25519 private key of the recipient.
+// - sealed: the sealed value.
+// - aad: the same additional authenticated data used for sealing.
+//
+// Returns the plaintext, or ErrInvalidCiphertext if the value was not sealed for this key or was tampered with.
+func Open(privateKey []byte, sealed, aad string) ([]byte, error) {
+	private, err := ecdh.X25519().NewPrivateKey(privateKey)
+	if err != nil {
+		return nil, ErrInvalidKey
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil || len(raw) < len(magic)+1+publicKeySize+nonceSize || !bytes.HasPrefix(raw, magic) {
+		return nil, ErrInvalidCiphertext
+	}
+
+	if raw[len(magic)] != Version1 {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownVersion, raw[len(magic)])
+	}
+
+	body := raw[len(magic)+1:]
+
+	ephemeral, err := ecdh.X25519().NewPublicKey(body[:publicKeySize])
+	if err != nil {
+		return nil, ErrInvalidCiphertext
+	}
+
+	secret, err := private.ECDH(ephemeral)
+	if err != nil {
+		return nil, ErrInvalidCiphertext
+	}
+
+	gcm, err := sealCipher(secret, body[:publicKeySize], private.PublicKey().Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	body = body[publicKeySize:]
+
+	plaintext, err := gcm.Open(nil, body[:nonceSize], body[nonceSize:], []byte(aad))
+	if err != nil {
+		return nil, ErrInvalidCiphertext
+	}
+
+	return plaintext, nil
+}
+
+// sealCipher derives the AES-256-GCM cipher of a sealed value from the agreed secret and the public keys.
+func sealCipher(secret, ephemeral, recipient []byte) (cipher.AEAD, error) {
+	salt := append(append([]byte{}, ephemeral...), recipient...)
+
+	key := make([]byte, KeySize)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, secret, salt, []byte("seal")), key); err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
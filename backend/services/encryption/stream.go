@@ -0,0 +1,238 @@
+package encryption
+
+import (
+	"bufio"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+const (
+	// StreamChunkSize is the size of the plaintext chunks sealed one by one.
+	StreamChunkSize = 64 * 1024
+
+	streamPrefixSize = 7
+	streamTagSize    = 16
+)
+
+var streamHeaderSize = len(magic) + 1 + streamPrefixSize
+
+var ErrStreamTooLong = errors.New("stream exceeds the maximum number of chunks")
+
+// EncryptedStreamSize returns the size of an encrypted stream for a plaintext of the given size.
+//
+// size: the plaintext size in bytes.
+// Returns the ciphertext size in bytes, e.g. to set the Content-Length of an upload.
+func EncryptedStreamSize(size int64) int64 {
+	chunks := (size + StreamChunkSize - 1) / StreamChunkSize
+	if chunks == 0 {
+		chunks = 1
+	}
+
+	return int64(streamHeaderSize) + size + chunks*streamTagSize
+}
+
+// EncryptStream returns a reader that encrypts the source on the fly.
+//
+// The stream is split in chunks of StreamChunkSize bytes sealed with AES-256-GCM. The nonce of a chunk
+// is a random prefix, the chunk counter and a flag marking the last chunk, so chunks cannot be
+// reordered, dropped or truncated without being detected (the STREAM construction).
+//
+// The layout is magic | version | nonce prefix | sealed chunks.
+//
+// source: the plaintext reader.
+// Returns the ciphertext reader.
+func (c Cipher) EncryptStream(source io.Reader) (io.Reader, error) {
+	aead, err := c.gcm()
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := make([]byte, streamPrefixSize)
+	if _, err := rand.Read(prefix); err != nil {
+		return nil, err
+	}
+
+	header := append(append(append([]byte{}, magic...), Version1), prefix...)
+
+	return &streamEncrypter{
+		stream: stream{aead: aead, prefix: prefix},
+		source: bufio.NewReaderSize(source, StreamChunkSize),
+		chunk:  make([]byte, StreamChunkSize),
+		out:    header,
+	}, nil
+}
+
+// DecryptStream returns a reader that decrypts a stream produced by EncryptStream.
+//
+// Read returns ErrInvalidCiphertext as soon as a chunk fails authentication, so the data
+// already read must be discarded when the stream does not end with io.EOF.
+//
+// source: the ciphertext reader.
+// Returns the plaintext reader.
+func (c Cipher) DecryptStream(source io.Reader) (io.Reader, error) {
+	aead, err := c.gcm()
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, streamHeaderSize)
+	if _, err := io.ReadFull(source, header); err != nil {
+		return nil, ErrInvalidCiphertext
+	}
+
+	if string(header[:len(magic)]) != string(magic) {
+		return nil, ErrInvalidCiphertext
+	}
+
+	if header[len(magic)] != Version1 {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownVersion, header[len(magic)])
+	}
+
+	return &streamDecrypter{
+		stream: stream{aead: aead, prefix: header[len(magic)+1:]},
+		source: bufio.NewReaderSize(source, StreamChunkSize+streamTagSize),
+		chunk:  make([]byte, StreamChunkSize+streamTagSize),
+	}, nil
+}
+
+// stream holds the state shared by the encrypter and the decrypter.
+type stream struct {
+	aead    cipher.AEAD
+	prefix  []byte
+	counter uint32
+	done    bool
+}
+
+// nonce returns the nonce of the next chunk and advances the counter.
+func (s *stream) nonce(last bool) ([]byte, error) {
+	if s.counter == math.MaxUint32 {
+		return nil, ErrStreamTooLong
+	}
+
+	nonce := make([]byte, nonceSize)
+	copy(nonce, s.prefix)
+	binary.BigEndian.PutUint32(nonce[streamPrefixSize:], s.counter)
+	if last {
+		nonce[nonceSize-1] = 1
+	}
+
+	s.counter++
+
+	return nonce, nil
+}
+
+type streamEncrypter struct {
+	stream
+	source *bufio.Reader
+	chunk  []byte
+	out    []byte
+}
+
+// Read implements io.Reader.
+func (s *streamEncrypter) Read(p []byte) (int, error) {
+	for len(s.out) == 0 {
+		if s.done {
+			return 0, io.EOF
+		}
+
+		if err := s.seal(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, s.out)
+	s.out = s.out[n:]
+
+	return n, nil
+}
+
+// seal reads and encrypts the next chunk.
+func (s *streamEncrypter) seal() error {
+	n, err := io.ReadFull(s.source, s.chunk)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return err
+	}
+
+	last := err != nil
+	if !last {
+		if _, err := s.source.Peek(1); err == io.EOF {
+			last = true
+		} else if err != nil {
+			return err
+		}
+	}
+
+	nonce, err := s.nonce(last)
+	if err != nil {
+		return err
+	}
+
+	s.out = s.aead.Seal(nil, nonce, s.chunk[:n], nil)
+	s.done = last
+
+	return nil
+}
+
+type streamDecrypter struct {
+	stream
+	source *bufio.Reader
+	chunk  []byte
+	out    []byte
+}
+
+// Read implements io.Reader.
+func (s *streamDecrypter) Read(p []byte) (int, error) {
+	for len(s.out) == 0 {
+		if s.done {
+			return 0, io.EOF
+		}
+
+		if err := s.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, s.out)
+	s.out = s.out[n:]
+
+	return n, nil
+}
+
+// open reads and decrypts the next chunk.
+func (s *streamDecrypter) open() error {
+	n, err := io.ReadFull(s.source, s.chunk)
+	if err == io.EOF {
+		// The stream ended before the last chunk.
+		return ErrInvalidCiphertext
+	}
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return err
+	}
+
+	last := err != nil
+	if !last {
+		if _, err := s.source.Peek(1); err == io.EOF {
+			last = true
+		} else if err != nil {
+			return err
+		}
+	}
+
+	nonce, err := s.nonce(last)
+	if err != nil {
+		return err
+	}
+
+	s.out, err = s.aead.Open(nil, nonce, s.chunk[:n], nil)
+	if err != nil {
+		return ErrInvalidCiphertext
+	}
+	s.done = last
+
+	return nil
+}
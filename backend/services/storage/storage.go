@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+var ErrNotFound = errors.New("object not found")
+
+// Storage stores opaque blobs by key.
+//
+// Blobs are written and read as streams, so large files never have to be held in memory.
+type Storage interface {
+	// Put stores size bytes read from r under key, replacing an existing blob.
+	Put(ctx context.Context, key string, r io.Reader, size int64) error
+	// Get opens the blob stored under key, it returns ErrNotFound when there is none.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the blob stored under key, a missing blob is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+var current Storage
+
+// Init configures the blob storage from the environment.
+//
+// STORAGE_DRIVER selects the backend: "local" (default) stores files below STORAGE_PATH
+// ("storage" by default), "s3" uses an S3-compatible service like MinIO configured with
+// S3_ENDPOINT, S3_REGION, S3_BUCKET, S3_ACCESS_KEY, S3_SECRET_KEY and S3_PATH_STYLE.
+//
+// It does not take any parameters.
+// It returns an error if the configuration is invalid.
+func Init() error {
+	var err error
+
+	switch driver := os.Getenv("STORAGE_DRIVER"); driver {
+	case "", "local":
+		path := os.Getenv("STORAGE_PATH")
+		if path == "" {
+			path = "storage"
+		}
+		current = Local{Root: path}
+	case "s3":
+		current, err = NewS3FromEnv()
+	default:
+		err = fmt.Errorf("unknown STORAGE_DRIVER %q", driver)
+	}
+
+	return err
+}
+
+// Get returns the storage configured by Init.
+//
+// No parameters.
+// Returns the Storage.
+func Get() Storage {
+	return current
+}
@@ -0,0 +1,54 @@
+package commands
+
+import (
+	"backend/modules/breaches/services"
+	services3 "backend/modules/passwords/services"
+	services2 "backend/services"
+	"errors"
+	"fmt"
+)
+
+var ErrUnknownCommand = errors.New("unknown command")
+
+// Run executes an administrative command instead of starting the server.
+//
+// Usage:
+// - breaches:import <path> [--replace]: imports a HIBP-style dataset file or range directory.
+// - breaches:rescan: checks every stored password and WiFi key against the dataset.
+//
+// Parameters:
+// - args: the command line arguments without the program name.
+//
+// Returns an error if the command is unknown or fails.
+func Run(args []string) error {
+	db := services2.GetDBConnection()
+	breachService := services.BreachService{DB: db}
+	passwordService := services3.PasswordService{DB: db}
+
+	switch args[0] {
+	case "breaches:import":
+		if len(args) < 2 {
+			return errors.New("usage: breaches:import <path> [--replace]")
+		}
+
+		replace := len(args) > 2 && args[2] == "--replace"
+
+		count, err := breachService.Import(args[1], replace)
+		fmt.Printf("Imported %d hashes\n", count)
+		if err != nil {
+			return err
+		}
+
+		changed, err := passwordService.RescanBreaches()
+		fmt.Printf("Updated %d passwords\n", changed)
+
+		return err
+	case "breaches:rescan":
+		changed, err := passwordService.RescanBreaches()
+		fmt.Printf("Updated %d passwords\n", changed)
+
+		return err
+	}
+
+	return fmt.Errorf("%w: %s", ErrUnknownCommand, args[0])
+}